@@ -0,0 +1,135 @@
+package logger_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+// methodSet renders the methods of an interface type as name+signature
+func methodSet(t reflect.Type) []string {
+	methods := make([]string, t.NumMethod())
+	for i := range methods {
+		m := t.Method(i)
+		methods[i] = m.Name + strings.TrimPrefix(m.Type.String(), "func")
+	}
+	return methods
+}
+
+// TestStableInterfaces guards the method sets of the sub-interfaces. They
+// must never change, even as Logger grows.
+func TestStableInterfaces(t *testing.T) {
+	tests := []struct {
+		iface    reflect.Type
+		expected []string
+	}{
+		{
+			iface: reflect.TypeOf((*logger.LevelLogger)(nil)).Elem(),
+			expected: []string{
+				"Debug(string, ...logger.Field)",
+				"Error(string, ...logger.Field)",
+				"Fatal(string, ...logger.Field)",
+				"Info(string, ...logger.Field)",
+				"Warn(string, ...logger.Field)",
+			},
+		},
+		{
+			iface: reflect.TypeOf((*logger.FieldLogger)(nil)).Elem(),
+			expected: []string{
+				"With(...logger.Field) logger.Logger",
+				"WithContext(context.Context) logger.Logger",
+			},
+		},
+		{
+			iface:    reflect.TypeOf((*logger.LifecycleLogger)(nil)).Elem(),
+			expected: []string{"Close() error", "Flush() error"},
+		},
+		{
+			iface:    reflect.TypeOf((*logger.LeveledControl)(nil)).Elem(),
+			expected: []string{"Enabled(logger.Level) bool", "SetLevel(logger.Level)"},
+		},
+	}
+
+	loggerType := reflect.TypeOf((*logger.Logger)(nil)).Elem()
+	for _, tt := range tests {
+		got := methodSet(tt.iface)
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%s method set changed:\n got  %v\n want %v", tt.iface.Name(), got, tt.expected)
+		}
+		if !loggerType.Implements(tt.iface) {
+			t.Errorf("Logger no longer embeds %s", tt.iface.Name())
+		}
+	}
+}
+
+func TestSetLevelAndEnabled(t *testing.T) {
+	var buf bytes.Buffer
+	root := logger.New(logger.Config{Output: &buf, Level: logger.InfoLevel})
+	child := root.With(logger.Field{Key: "component", Value: "db"})
+
+	if child.Enabled(logger.DebugLevel) || !child.Enabled(logger.InfoLevel) {
+		t.Fatal("Expected Info to be enabled and Debug disabled")
+	}
+
+	root.SetLevel(logger.DebugLevel)
+	child.Debug("now visible")
+	if !strings.Contains(buf.String(), "[DEBUG] now visible {component=db}") {
+		t.Errorf("Expected SetLevel on the root to reach derived loggers, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	child.SetLevel(logger.ErrorLevel)
+	root.Warn("hidden")
+	if buf.Len() != 0 {
+		t.Errorf("Expected Warn to be filtered after SetLevel, got: %s", buf.String())
+	}
+}
+
+func TestMultiLoggerLevelControl(t *testing.T) {
+	multi := logger.MultiLogger(
+		logger.New(logger.Config{Output: &bytes.Buffer{}, Level: logger.ErrorLevel}),
+		logger.New(logger.Config{Output: &bytes.Buffer{}, Level: logger.InfoLevel}),
+	)
+
+	if !multi.Enabled(logger.InfoLevel) {
+		t.Error("Expected Info to be enabled when any child enables it")
+	}
+	multi.SetLevel(logger.FatalLevel)
+	if multi.Enabled(logger.ErrorLevel) {
+		t.Error("Expected SetLevel to apply to every child")
+	}
+}
+
+func TestCloseReleasesOwnedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	fileLogger, err := logger.CreateFileLogger(path, logger.InfoLevel)
+	if err != nil {
+		t.Fatalf("CreateFileLogger failed: %v", err)
+	}
+	console := logger.New(logger.Config{Output: &bytes.Buffer{}})
+	multi := logger.MultiLogger(console, fileLogger)
+
+	multi.Info("before close")
+	if err := multi.Flush(); err != nil {
+		t.Errorf("Expected Flush to succeed, got %v", err)
+	}
+	if err := multi.Close(); err != nil {
+		t.Fatalf("Expected Close to succeed, got %v", err)
+	}
+	if err := fileLogger.Close(); err != nil {
+		t.Errorf("Expected a second Close to be a no-op, got %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(content), "before close") {
+		t.Errorf("Expected entry in file, got: %s", content)
+	}
+}
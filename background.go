@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Background goroutines follow one lifecycle rule: every goroutine is
+// started through a backgroundGroup owned by a component, and the
+// component's Close or Stop method joins the group before returning.
+// Nothing is started by package initialization.
+
+// runningTasks counts background goroutines across all components
+var runningTasks int64
+
+// RunningBackgroundTasks returns the number of goroutines currently
+// running on behalf of loggers. It is intended for leak checks in tests:
+// after every component has been closed it must return zero.
+func RunningBackgroundTasks() int {
+	return int(atomic.LoadInt64(&runningTasks))
+}
+
+// backgroundGroup tracks the goroutines started by one component
+type backgroundGroup struct {
+	wg sync.WaitGroup
+}
+
+// Go runs fn in a new goroutine counted by RunningBackgroundTasks
+func (g *backgroundGroup) Go(fn func()) {
+	atomic.AddInt64(&runningTasks, 1)
+	g.wg.Add(1)
+	go func() {
+		defer func() {
+			atomic.AddInt64(&runningTasks, -1)
+			g.wg.Done()
+		}()
+		fn()
+	}()
+}
+
+// Wait blocks until every goroutine started by the group has returned
+func (g *backgroundGroup) Wait() {
+	g.wg.Wait()
+}
@@ -0,0 +1,110 @@
+package logger_test
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+// loggerGoroutines returns the stacks of goroutines running code of the
+// logger package, the way goleak finds leaked goroutines
+func loggerGoroutines() []string {
+	buf := make([]byte, 1<<20)
+	buf = buf[:runtime.Stack(buf, true)]
+	var found []string
+	for _, stack := range strings.Split(string(buf), "\n\n") {
+		if strings.Contains(stack, "github.com/MichaelAJay/go-logger.") {
+			found = append(found, stack)
+		}
+	}
+	return found
+}
+
+// requireNoBackgroundTasks fails the test unless every background task has
+// returned and no goroutine is left running logger code. Goroutines that
+// have just returned may take a moment to disappear from the stack dump.
+func requireNoBackgroundTasks(t *testing.T) {
+	t.Helper()
+	if n := logger.RunningBackgroundTasks(); n != 0 {
+		t.Fatalf("Expected no running background tasks, got %d", n)
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		leaked := loggerGoroutines()
+		if len(leaked) == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected no logger goroutines, found:\n%s", strings.Join(leaked, "\n\n"))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSLOSummaryStopLeavesNothingRunning(t *testing.T) {
+	var buf syncBuffer
+	log := logger.New(logger.Config{Output: &buf})
+
+	summary := logger.StartSLOSummary(log, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if logger.RunningBackgroundTasks() != 1 {
+		t.Errorf("Expected the summary goroutine to be counted, got %d", logger.RunningBackgroundTasks())
+	}
+	summary.Stop()
+	summary.Stop()
+
+	requireNoBackgroundTasks(t)
+}
+
+func TestProviderWorkerStopsOnClose(t *testing.T) {
+	var buf bytes.Buffer
+	base := logger.New(logger.Config{Output: &buf})
+	log := logger.MultiLogger(base.With(logger.Provided("depth", func() any { return 1 })))
+
+	log.Info("evaluated")
+	if logger.RunningBackgroundTasks() != 1 {
+		t.Errorf("Expected the provider worker to be counted, got %d", logger.RunningBackgroundTasks())
+	}
+	// Closing any logger of the family stops the worker they share
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	requireNoBackgroundTasks(t)
+
+	// Logging after Close starts the worker again, and Close stops it
+	log.Info("again")
+	if err := base.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	requireNoBackgroundTasks(t)
+}
+
+func TestProviderWorkerCloseWaitsForSlowProvider(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, ProviderTimeout: time.Millisecond})
+	release := make(chan struct{})
+
+	log.Info("slow", logger.Provided("free_disk", func() any {
+		<-release
+		return 0
+	}))
+
+	closed := make(chan struct{})
+	go func() {
+		log.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		t.Fatal("Expected Close to wait for the running provider")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	<-closed
+	requireNoBackgroundTasks(t)
+}
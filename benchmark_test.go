@@ -0,0 +1,267 @@
+package logger_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestDisabledLevelAllocations(t *testing.T) {
+	log := logger.New(logger.Config{Level: logger.InfoLevel, Output: io.Discard})
+
+	allocs := testing.AllocsPerRun(100, func() {
+		log.Debug("filtered",
+			logger.Field{Key: "user_id", Value: "abc123"},
+			logger.Field{Key: "attempt", Value: 3},
+			logger.Field{Key: "ok", Value: true},
+		)
+	})
+
+	// Only the variadic field slice may be allocated
+	if allocs > 1 {
+		t.Errorf("Expected at most 1 allocation for a filtered entry, got %v", allocs)
+	}
+}
+
+func BenchmarkDisabledDebug(b *testing.B) {
+	log := logger.New(logger.Config{Level: logger.InfoLevel, Output: io.Discard})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		log.Debug("filtered",
+			logger.Field{Key: "user_id", Value: "abc123"},
+			logger.Field{Key: "attempt", Value: 3},
+			logger.Field{Key: "ok", Value: true},
+		)
+	}
+}
+
+func BenchmarkDisabledPackageDebug(b *testing.B) {
+	previous := logger.GetDefaultLogger()
+	defer logger.SetDefaultLogger(previous)
+	logger.SetDefaultLogger(logger.New(logger.Config{Level: logger.InfoLevel, Output: io.Discard}))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Debug("filtered",
+			logger.Field{Key: "user_id", Value: "abc123"},
+			logger.Field{Key: "attempt", Value: 3},
+			logger.Field{Key: "ok", Value: true},
+		)
+	}
+}
+
+func BenchmarkEnabledInfo(b *testing.B) {
+	log := logger.New(logger.Config{Level: logger.InfoLevel, Output: io.Discard})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		log.Info("written",
+			logger.Field{Key: "user_id", Value: "abc123"},
+			logger.Field{Key: "attempt", Value: 3},
+			logger.Field{Key: "ok", Value: true},
+		)
+	}
+}
+
+func BenchmarkWithDerived(b *testing.B) {
+	log := logger.New(logger.Config{Level: logger.InfoLevel, Output: io.Discard}).With(
+		logger.Field{Key: "service", Value: "api"},
+		logger.Field{Key: "region", Value: "us-east-1"},
+	)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		log.Info("written", logger.Field{Key: "attempt", Value: 3})
+	}
+}
+
+func BenchmarkParallelInfo(b *testing.B) {
+	log := logger.New(logger.Config{Level: logger.InfoLevel, Output: io.Discard})
+
+	b.ReportAllocs()
+	b.SetParallelism(8)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			log.Info("written", logger.Field{Key: "attempt", Value: 3})
+		}
+	})
+}
+
+func BenchmarkWithNeverLogs(b *testing.B) {
+	log := logger.New(logger.Config{Level: logger.InfoLevel, Output: io.Discard})
+	fields := benchmarkRequestFields()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		log.With(fields...).Debug("filtered")
+	}
+}
+
+func BenchmarkWithLazyNeverLogs(b *testing.B) {
+	log := logger.New(logger.Config{Level: logger.InfoLevel, Output: io.Discard})
+	fields := benchmarkRequestFields()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.WithLazy(log, fields...).Debug("filtered")
+	}
+}
+
+// benchmarkRequestFields returns the fields a request middleware
+// typically derives its logger with
+func benchmarkRequestFields() []logger.Field {
+	return []logger.Field{
+		{Key: "request_id", Value: "req-1"},
+		{Key: "method", Value: "GET"},
+		{Key: "path", Value: "/orders"},
+		{Key: "remote_addr", Value: "10.0.0.1"},
+		{Key: "user_agent", Value: "curl/8.0"},
+		{Key: "user_id", Value: "u-1"},
+		{Key: "tenant_id", Value: "acme"},
+		{Key: "trace_id", Value: "t-1"},
+	}
+}
+
+func BenchmarkInlineFields(b *testing.B) {
+	log := logger.New(logger.Config{Output: io.Discard})
+	id, n, ms := "abc123", 3, 42
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		log.Info("login",
+			logger.Field{Key: "user_id", Value: id},
+			logger.Field{Key: "attempt", Value: n},
+			logger.Field{Key: "latency_ms", Value: ms},
+		)
+	}
+}
+
+func BenchmarkFieldSetValues(b *testing.B) {
+	log := logger.New(logger.Config{Output: io.Discard})
+	set := logger.NewFieldSet("user_id", "attempt", "latency_ms")
+	id, n, ms := "abc123", 3, 42
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		log.Info("login", set.Values(id, n, ms))
+	}
+}
+
+func BenchmarkTextFixed(b *testing.B) {
+	log := logger.New(logger.Config{Output: io.Discard})
+	benchmarkText(b, log)
+}
+
+func BenchmarkTextLayout(b *testing.B) {
+	log := logger.New(logger.Config{Output: io.Discard, Layout: "{{.Time}} [{{.Level}}] {{.Message}} {{.Fields}}"})
+	benchmarkText(b, log)
+}
+
+func benchmarkText(b *testing.B, log logger.Logger) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		log.Info("request handled",
+			logger.Field{Key: "user_id", Value: "abc123"},
+			logger.Field{Key: "status", Value: 200},
+		)
+	}
+}
+
+func BenchmarkTypedFields(b *testing.B) {
+	log := logger.New(logger.Config{Output: io.Discard})
+	id, n, ms := "abc123", 3, 42
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		log.Info("login",
+			logger.String("user_id", id),
+			logger.Int("attempt", n),
+			logger.Int("latency_ms", ms),
+		)
+	}
+}
+
+func BenchmarkInlineFieldsJSON(b *testing.B) {
+	log := logger.New(logger.Config{Output: io.Discard, Format: logger.FormatJSON})
+	id, n, ok, ratio := "abc123", int64(300), true, 0.25
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		log.Info("login",
+			logger.Field{Key: "user_id", Value: id},
+			logger.Field{Key: "attempt", Value: n},
+			logger.Field{Key: "ok", Value: ok},
+			logger.Field{Key: "ratio", Value: ratio},
+		)
+	}
+}
+
+func BenchmarkTypedFieldsJSON(b *testing.B) {
+	log := logger.New(logger.Config{Output: io.Discard, Format: logger.FormatJSON})
+	id, n, ok, ratio := "abc123", int64(300), true, 0.25
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		log.Info("login",
+			logger.String("user_id", id),
+			logger.Int64("attempt", n),
+			logger.Bool("ok", ok),
+			logger.Float64("ratio", ratio),
+		)
+	}
+}
+
+func BenchmarkJSONEncoder(b *testing.B) {
+	log := logger.New(logger.Config{Output: io.Discard, Format: logger.FormatJSON})
+	tags := []string{"checkout", "eu"}
+	meta := map[string]any{"retries": 2, "cached": true}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		log.Info("order placed",
+			logger.String("order_id", "ord-8231"),
+			logger.Float64("amount", 129.95),
+			logger.Field{Key: "tags", Value: tags},
+			logger.Field{Key: "meta", Value: meta},
+		)
+	}
+}
+
+// BenchmarkJSONFallback logs the same data as BenchmarkJSONEncoder as a
+// struct, which goes through encoding/json
+func BenchmarkJSONFallback(b *testing.B) {
+	log := logger.New(logger.Config{Output: io.Discard, Format: logger.FormatJSON})
+	type meta struct {
+		Retries int  `json:"retries"`
+		Cached  bool `json:"cached"`
+	}
+	type order struct {
+		OrderID string   `json:"order_id"`
+		Amount  float64  `json:"amount"`
+		Tags    []string `json:"tags"`
+		Meta    meta     `json:"meta"`
+	}
+	value := order{OrderID: "ord-8231", Amount: 129.95, Tags: []string{"checkout", "eu"}, Meta: meta{Retries: 2, Cached: true}}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		log.Info("order placed", logger.Field{Key: "order", Value: value})
+	}
+}
@@ -0,0 +1,215 @@
+// Package benchmarks compares the hot path of go-logger with log/slog, zap
+// and zerolog. It is a separate module so the comparison dependencies are
+// not required by the library.
+package benchmarks
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+	"github.com/rs/zerolog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// The five typed fields shared by every scenario
+const (
+	userID   = "abc123"
+	attempt  = 3
+	ok       = true
+	ratio    = 0.25
+	duration = 150 * time.Millisecond
+)
+
+func newGoLogger(level logger.Level) logger.Logger {
+	return logger.New(logger.Config{Level: level, Output: io.Discard, Format: logger.FormatJSON})
+}
+
+func newSlog(level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(io.Discard, &slog.HandlerOptions{Level: level}))
+}
+
+func newZap(level zapcore.Level) *zap.Logger {
+	enc := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	return zap.New(zapcore.NewCore(enc, zapcore.AddSync(io.Discard), level))
+}
+
+func newZerolog(level zerolog.Level) zerolog.Logger {
+	return zerolog.New(io.Discard).Level(level).With().Timestamp().Logger()
+}
+
+func BenchmarkDisabled(b *testing.B) {
+	b.Run("go-logger", func(b *testing.B) {
+		log := newGoLogger(logger.InfoLevel)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			log.Debug("filtered", logger.Field{Key: "user_id", Value: userID})
+		}
+	})
+	b.Run("slog", func(b *testing.B) {
+		log := newSlog(slog.LevelInfo)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			log.Debug("filtered", slog.String("user_id", userID))
+		}
+	})
+	b.Run("zap", func(b *testing.B) {
+		log := newZap(zapcore.InfoLevel)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			log.Debug("filtered", zap.String("user_id", userID))
+		}
+	})
+	b.Run("zerolog", func(b *testing.B) {
+		log := newZerolog(zerolog.InfoLevel)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			log.Debug().Str("user_id", userID).Msg("filtered")
+		}
+	})
+}
+
+func BenchmarkFiveFields(b *testing.B) {
+	b.Run("go-logger", func(b *testing.B) {
+		log := newGoLogger(logger.InfoLevel)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			log.Info("written",
+				logger.Field{Key: "user_id", Value: userID},
+				logger.Field{Key: "attempt", Value: attempt},
+				logger.Field{Key: "ok", Value: ok},
+				logger.Field{Key: "ratio", Value: ratio},
+				logger.Field{Key: "duration", Value: duration},
+			)
+		}
+	})
+	b.Run("slog", func(b *testing.B) {
+		log := newSlog(slog.LevelInfo)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			log.Info("written",
+				slog.String("user_id", userID),
+				slog.Int("attempt", attempt),
+				slog.Bool("ok", ok),
+				slog.Float64("ratio", ratio),
+				slog.Duration("duration", duration),
+			)
+		}
+	})
+	b.Run("zap", func(b *testing.B) {
+		log := newZap(zapcore.InfoLevel)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			log.Info("written",
+				zap.String("user_id", userID),
+				zap.Int("attempt", attempt),
+				zap.Bool("ok", ok),
+				zap.Float64("ratio", ratio),
+				zap.Duration("duration", duration),
+			)
+		}
+	})
+	b.Run("zerolog", func(b *testing.B) {
+		log := newZerolog(zerolog.InfoLevel)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			log.Info().
+				Str("user_id", userID).
+				Int("attempt", attempt).
+				Bool("ok", ok).
+				Float64("ratio", ratio).
+				Dur("duration", duration).
+				Msg("written")
+		}
+	})
+}
+
+func BenchmarkWithFields(b *testing.B) {
+	b.Run("go-logger", func(b *testing.B) {
+		log := newGoLogger(logger.InfoLevel).With(
+			logger.Field{Key: "user_id", Value: userID},
+			logger.Field{Key: "attempt", Value: attempt},
+		)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			log.Info("written", logger.Field{Key: "ok", Value: ok})
+		}
+	})
+	b.Run("slog", func(b *testing.B) {
+		log := newSlog(slog.LevelInfo).With(
+			slog.String("user_id", userID),
+			slog.Int("attempt", attempt),
+		)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			log.Info("written", slog.Bool("ok", ok))
+		}
+	})
+	b.Run("zap", func(b *testing.B) {
+		log := newZap(zapcore.InfoLevel).With(
+			zap.String("user_id", userID),
+			zap.Int("attempt", attempt),
+		)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			log.Info("written", zap.Bool("ok", ok))
+		}
+	})
+	b.Run("zerolog", func(b *testing.B) {
+		log := newZerolog(zerolog.InfoLevel).With().
+			Str("user_id", userID).
+			Int("attempt", attempt).
+			Logger()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			log.Info().Bool("ok", ok).Msg("written")
+		}
+	})
+}
+
+// BenchmarkParallel logs from 8 goroutines per CPU
+func BenchmarkParallel(b *testing.B) {
+	b.Run("go-logger", func(b *testing.B) {
+		log := newGoLogger(logger.InfoLevel)
+		b.ReportAllocs()
+		b.SetParallelism(8)
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				log.Info("written", logger.Field{Key: "user_id", Value: userID}, logger.Field{Key: "attempt", Value: attempt})
+			}
+		})
+	})
+	b.Run("slog", func(b *testing.B) {
+		log := newSlog(slog.LevelInfo)
+		b.ReportAllocs()
+		b.SetParallelism(8)
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				log.Info("written", slog.String("user_id", userID), slog.Int("attempt", attempt))
+			}
+		})
+	})
+	b.Run("zap", func(b *testing.B) {
+		log := newZap(zapcore.InfoLevel)
+		b.ReportAllocs()
+		b.SetParallelism(8)
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				log.Info("written", zap.String("user_id", userID), zap.Int("attempt", attempt))
+			}
+		})
+	})
+	b.Run("zerolog", func(b *testing.B) {
+		log := newZerolog(zerolog.InfoLevel)
+		b.ReportAllocs()
+		b.SetParallelism(8)
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				log.Info().Str("user_id", userID).Int("attempt", attempt).Msg("written")
+			}
+		})
+	})
+}
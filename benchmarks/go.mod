@@ -0,0 +1,18 @@
+module github.com/MichaelAJay/go-logger/benchmarks
+
+go 1.21
+
+require (
+	github.com/MichaelAJay/go-logger v0.0.0
+	github.com/rs/zerolog v1.33.0
+	go.uber.org/zap v1.27.0
+)
+
+require (
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.19 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+	golang.org/x/sys v0.12.0 // indirect
+)
+
+replace github.com/MichaelAJay/go-logger => ../
@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"os"
+)
+
+// DefaultBrokenPipeLimit is the number of consecutive broken pipe errors
+// after which a logger stops writing to its output, when
+// Config.BrokenPipeLimit is not set
+const DefaultBrokenPipeLimit = 3
+
+// isBrokenPipe reports whether err means the reader of the output is gone
+// for good: EPIPE, a closed pipe or a closed file
+func isBrokenPipe(err error) bool {
+	return errors.Is(err, io.ErrClosedPipe) || errors.Is(err, os.ErrClosed) || isEPIPE(err)
+}
+
+// guardBrokenPipe keeps a broken standard output or standard error from
+// killing the process, for Config.IgnoreSIGPIPE. Go raises SIGPIPE for
+// failed writes to descriptors 1 and 2 unless the program is notified of
+// it; once it is, the write returns EPIPE like writes to any other pipe.
+// Waiting for the first EPIPE would be too late, as that write already
+// ends the process.
+func guardBrokenPipe(w io.Writer) {
+	if w == os.Stdout || w == os.Stderr {
+		ignoreSIGPIPE()
+	}
+}
+
+// reportBrokenPipe tells the emergency logger that an output has been
+// abandoned
+func reportBrokenPipe(name string, failures int, fallback bool) {
+	emergency.Error("log output stopped: reader is gone",
+		Field{Key: "output", Value: name},
+		Field{Key: "consecutive_failures", Value: failures},
+		Field{Key: "fallback", Value: fallback},
+	)
+}
@@ -0,0 +1,11 @@
+//go:build plan9 || js
+
+package logger
+
+// isEPIPE reports whether err is EPIPE, which this platform does not have
+func isEPIPE(err error) bool {
+	return false
+}
+
+// ignoreSIGPIPE does nothing: this platform has no SIGPIPE
+func ignoreSIGPIPE() {}
@@ -0,0 +1,26 @@
+//go:build !plan9 && !js
+
+package logger
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+var sigpipeOnce sync.Once
+
+// isEPIPE reports whether err is EPIPE
+func isEPIPE(err error) bool {
+	return errors.Is(err, syscall.EPIPE)
+}
+
+// ignoreSIGPIPE subscribes to SIGPIPE, without ever reading it, so the
+// runtime no longer exits on a broken standard output
+func ignoreSIGPIPE() {
+	sigpipeOnce.Do(func() {
+		signal.Notify(make(chan os.Signal, 1), syscall.SIGPIPE)
+	})
+}
@@ -0,0 +1,145 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+// brokenPipe returns the write end of a pipe whose read end is closed
+func brokenPipe(t *testing.T) *os.File {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	r.Close()
+	t.Cleanup(func() { w.Close() })
+	return w
+}
+
+func TestBrokenPipeStopsWriting(t *testing.T) {
+	emergency := captureEmergency(t)
+	var fallback bytes.Buffer
+	log := logger.New(logger.Config{Output: brokenPipe(t), BrokenPipeLimit: 2, FallbackOutput: &fallback, OutputName: "pipe"})
+
+	log.Info("first")
+	health := log.(logger.HealthReporter).Health()
+	if !health.BrokenPipe || health.Stopped {
+		t.Errorf("Expected a broken pipe that is not stopped yet, got %+v", health)
+	}
+
+	log.Info("second")
+	log.Info("third")
+	log.Info("fourth")
+
+	health = log.(logger.HealthReporter).Health()
+	if !health.Stopped || health.Healthy || health.ConsecutiveFailures != 2 {
+		t.Errorf("Expected the output stopped after two failures, got %+v", health)
+	}
+	if strings.Contains(fallback.String(), "second") || !strings.Contains(fallback.String(), "third") || !strings.Contains(fallback.String(), "fourth") {
+		t.Errorf("Expected entries after the stop in the fallback, got: %s", fallback.String())
+	}
+	if strings.Count(emergency.String(), "log output stopped") != 1 || !strings.Contains(emergency.String(), "output=pipe") {
+		t.Errorf("Expected one stop report, got: %s", emergency.String())
+	}
+}
+
+func TestBrokenPipeClassification(t *testing.T) {
+	captureEmergency(t)
+
+	closed, err := os.CreateTemp(t.TempDir(), "closed")
+	if err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	closed.Close()
+	log := logger.New(logger.Config{Output: closed, BrokenPipeLimit: 1})
+	log.Info("to a closed file")
+	if health := log.(logger.HealthReporter).Health(); !health.BrokenPipe || !health.Stopped {
+		t.Errorf("Expected a closed file to count as a broken pipe, got %+v", health)
+	}
+
+	w := &flakyWriter{failing: 1}
+	other := logger.New(logger.Config{Output: w, BrokenPipeLimit: 1})
+	other.Info("plain failure")
+	other.Info("plain failure")
+	if health := other.(logger.HealthReporter).Health(); health.BrokenPipe || health.Stopped {
+		t.Errorf("Expected other errors to keep writing, got %+v", health)
+	}
+}
+
+func TestBrokenPipeDropsWithoutFallback(t *testing.T) {
+	captureEmergency(t)
+	log := logger.New(logger.Config{Output: brokenPipe(t), BrokenPipeLimit: 1})
+
+	log.Info("stops")
+	log.Info("dropped")
+	if health := log.(logger.HealthReporter).Health(); !health.Stopped || health.ConsecutiveFailures != 1 {
+		t.Errorf("Expected no further write attempts, got %+v", health)
+	}
+}
+
+func TestBrokenPipeNegativeLimitNeverStops(t *testing.T) {
+	captureEmergency(t)
+	log := logger.New(logger.Config{Output: brokenPipe(t), BrokenPipeLimit: -1})
+
+	for i := 0; i < 5; i++ {
+		log.Info("keeps trying")
+	}
+	if health := log.(logger.HealthReporter).Health(); health.Stopped || health.ConsecutiveFailures != 5 {
+		t.Errorf("Expected every write attempted, got %+v", health)
+	}
+}
+
+func TestSetOutputResumesStoppedOutput(t *testing.T) {
+	captureEmergency(t)
+	log := logger.New(logger.Config{Output: brokenPipe(t), BrokenPipeLimit: 1})
+	log.Info("stops")
+
+	var buf bytes.Buffer
+	if err := log.(logger.OutputSetter).SetOutput(&buf); err != nil {
+		t.Fatalf("SetOutput failed: %v", err)
+	}
+	log.Info("resumed")
+
+	if !strings.Contains(buf.String(), "resumed") {
+		t.Errorf("Expected writes to resume, got: %s", buf.String())
+	}
+	if health := log.(logger.HealthReporter).Health(); !health.Healthy || health.Stopped {
+		t.Errorf("Expected a healthy output, got %+v", health)
+	}
+}
+
+func TestIgnoreSIGPIPEIsOptIn(t *testing.T) {
+	if mode := os.Getenv("LOGGER_SIGPIPE_CHILD"); mode != "" {
+		log := logger.New(logger.Config{Output: os.Stdout, IgnoreSIGPIPE: mode == "on"})
+		log.Info("to a closed pipe")
+		os.Exit(0)
+	}
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" || runtime.GOOS == "js" {
+		t.Skip("No SIGPIPE on " + runtime.GOOS)
+	}
+
+	run := func(mode string) error {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestIgnoreSIGPIPEIsOptIn$")
+		cmd.Env = append(os.Environ(), "LOGGER_SIGPIPE_CHILD="+mode)
+		cmd.Stdout = brokenPipe(t)
+		return cmd.Run()
+	}
+
+	if err := run("on"); err != nil {
+		t.Errorf("Expected the process to survive a broken standard output with IgnoreSIGPIPE, got %v", err)
+	}
+	// Importing the package and logging to stdout must not change SIGPIPE
+	// handling on its own
+	var exitErr *exec.ExitError
+	if err := run("off"); !errors.As(err, &exitErr) || exitErr.Exited() {
+		t.Errorf("Expected the default SIGPIPE behavior without IgnoreSIGPIPE, got %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// CanonicalIncompleteKey marks canonical entries emitted by
+// CanonicalMiddleware for requests whose handler did not emit them
+const CanonicalIncompleteKey = "canonical_incomplete"
+
+// Canonical accumulates fields over the life of a request and writes them
+// as one wide entry, the canonical log line, when the request completes.
+// It is safe for concurrent use, and its methods do nothing on a nil
+// Canonical, so code can call CanonicalFromContext(ctx).Add(...) whether
+// or not the request has one.
+type Canonical struct {
+	log Logger
+
+	mu      sync.Mutex
+	fields  []Field
+	timings []canonicalTiming
+	emitted bool
+}
+
+// canonicalTiming is the running total of a timing added with AddTiming
+type canonicalTiming struct {
+	name  string
+	total time.Duration
+	count int
+}
+
+// NewCanonical returns an empty accumulator writing to log
+func NewCanonical(log Logger) *Canonical {
+	return &Canonical{log: log}
+}
+
+// Add accumulates fields for the entry. A field whose key was added
+// before replaces the earlier value.
+func (c *Canonical) Add(fields ...Field) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, field := range fields {
+		c.fields = setField(c.fields, field)
+	}
+}
+
+// AddTiming adds d to the timing called name. The entry carries the total
+// as a Duration field called name and the number of timings added as
+// name_count, so repeated database calls sum into db and db_count.
+func (c *Canonical) AddTiming(name string, d time.Duration) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range c.timings {
+		if c.timings[i].name == name {
+			c.timings[i].total += d
+			c.timings[i].count++
+			return
+		}
+	}
+	c.timings = append(c.timings, canonicalTiming{name: name, total: d, count: 1})
+}
+
+// Emit writes the accumulated fields as one entry at level. Only the
+// first call writes; it reports whether this call did.
+func (c *Canonical) Emit(level Level, msg string) bool {
+	return c.emit(level, msg)
+}
+
+// Emitted reports whether the entry has been written
+func (c *Canonical) Emitted() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.emitted
+}
+
+func (c *Canonical) emit(level Level, msg string, extra ...Field) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	if c.emitted {
+		c.mu.Unlock()
+		return false
+	}
+	c.emitted = true
+	fields := make([]Field, 0, len(c.fields)+2*len(c.timings)+len(extra))
+	fields = append(fields, c.fields...)
+	for _, timing := range c.timings {
+		fields = append(fields,
+			Duration(timing.name, timing.total),
+			Field{Key: timing.name + "_count", Value: timing.count},
+		)
+	}
+	fields = append(fields, extra...)
+	c.mu.Unlock()
+
+	logAt(c.log, level, msg, fields...)
+	return true
+}
+
+// canonicalKey is the context key of the request's Canonical
+type canonicalKey struct{}
+
+// WithCanonical returns a context carrying c
+func WithCanonical(ctx context.Context, c *Canonical) context.Context {
+	return context.WithValue(ctx, canonicalKey{}, c)
+}
+
+// CanonicalFromContext returns the Canonical stored in ctx, or nil
+func CanonicalFromContext(ctx context.Context) *Canonical {
+	c, _ := ctx.Value(canonicalKey{}).(*Canonical)
+	return c
+}
+
+// logAt writes msg at level through the method of that level
+func logAt(l LevelLogger, level Level, msg string, fields ...Field) {
+	switch level {
+	case DebugLevel:
+		l.Debug(msg, fields...)
+	case WarnLevel:
+		l.Warn(msg, fields...)
+	case ErrorLevel:
+		l.Error(msg, fields...)
+	case FatalLevel:
+		l.Fatal(msg, fields...)
+	default:
+		l.Info(msg, fields...)
+	}
+}
@@ -0,0 +1,75 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestCanonicalAccumulates(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+	cl := logger.NewCanonical(log)
+
+	cl.Add(logger.Field{Key: "auth", Value: "denied"}, logger.Field{Key: "cache_hit", Value: false})
+	cl.Add(logger.Field{Key: "auth", Value: "ok"})
+	cl.AddTiming("db", 20*time.Millisecond)
+	cl.AddTiming("db", 5*time.Millisecond)
+
+	if !cl.Emit(logger.InfoLevel, "request completed") {
+		t.Fatal("Expected the first Emit to write")
+	}
+	if cl.Emit(logger.InfoLevel, "request completed") {
+		t.Error("Expected a second Emit to do nothing")
+	}
+
+	expected := "[INFO] request completed {auth=ok cache_hit=false db=25ms db_count=2}"
+	if !strings.Contains(buf.String(), expected) || strings.Count(buf.String(), "request completed") != 1 {
+		t.Errorf("Expected one entry %q, got: %s", expected, buf.String())
+	}
+}
+
+func TestCanonicalConcurrentAdd(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatJSON})
+	cl := logger.NewCanonical(log)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				cl.Add(logger.Field{Key: "worker", Value: g})
+				cl.AddTiming("db", time.Millisecond)
+			}
+		}(g)
+	}
+	wg.Wait()
+	cl.Emit(logger.InfoLevel, "done")
+
+	entry := decodeEntry(t, buf.String())
+	if entry["db_count"] != float64(800) || entry["db"] != float64(800) {
+		t.Errorf("Expected 800 timings summing to 800ms, got %v", entry)
+	}
+	if _, ok := entry["worker"]; !ok {
+		t.Errorf("Expected the worker field, got %v", entry)
+	}
+}
+
+func TestCanonicalNilIsSafe(t *testing.T) {
+	cl := logger.CanonicalFromContext(context.Background())
+	if cl != nil {
+		t.Fatal("Expected no Canonical in an empty context")
+	}
+	cl.Add(logger.Field{Key: "k", Value: "v"})
+	cl.AddTiming("db", time.Second)
+	if cl.Emit(logger.InfoLevel, "nothing") {
+		t.Error("Expected Emit on nil to do nothing")
+	}
+}
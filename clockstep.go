@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultClockSuspectWindow is how long entries are marked clock_suspect
+// after a clock step when Config.ClockSuspectWindow is not set
+const DefaultClockSuspectWindow = time.Minute
+
+// clockMonitor compares how far the wall clock and the monotonic clock
+// moved between entries. It is shared by a logger created by New and
+// everything derived from it.
+type clockMonitor struct {
+	mono      func() time.Duration
+	threshold time.Duration
+	window    time.Duration
+
+	mu           sync.Mutex
+	started      bool
+	lastWall     time.Time
+	lastMono     time.Duration
+	suspectUntil time.Duration
+}
+
+func newClockMonitor(mono func() time.Duration, threshold, window time.Duration) *clockMonitor {
+	return &clockMonitor{mono: mono, threshold: threshold, window: window}
+}
+
+// check records the wall clock reading of an entry. It returns the size of
+// a clock step detected since the previous entry, or zero, and whether the
+// entry falls within the suspect window of a step.
+func (m *clockMonitor) check(now time.Time) (step time.Duration, suspect bool) {
+	mono := m.mono()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.started {
+		// Strip any monotonic reading so the subtraction uses wall time
+		skew := now.Round(0).Sub(m.lastWall) - (mono - m.lastMono)
+		if skew > m.threshold || skew < -m.threshold {
+			step = skew
+			m.suspectUntil = mono + m.window
+		}
+	}
+	m.started = true
+	m.lastWall = now.Round(0)
+	m.lastMono = mono
+	return step, mono < m.suspectUntil
+}
+
+// monotonicSince returns a monotonic clock reporting the time elapsed
+// since it was created
+func monotonicSince() func() time.Duration {
+	start := time.Now()
+	return func() time.Duration {
+		return time.Since(start)
+	}
+}
@@ -0,0 +1,87 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+// steppingClock is a wall clock and a monotonic clock that advance
+// together unless the wall clock is stepped
+type steppingClock struct {
+	wall time.Time
+	mono time.Duration
+}
+
+func (c *steppingClock) advance(d time.Duration) {
+	c.wall = c.wall.Add(d)
+	c.mono += d
+}
+
+func TestClockStepDetection(t *testing.T) {
+	clock := &steppingClock{wall: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)}
+
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{
+		Output:             &buf,
+		Clock:              func() time.Time { return clock.wall },
+		MonotonicClock:     func() time.Duration { return clock.mono },
+		ClockStepThreshold: time.Second,
+		ClockSuspectWindow: 30 * time.Second,
+	})
+
+	log.Info("before")
+	clock.advance(5 * time.Second)
+	log.Info("steady")
+	if strings.Contains(buf.String(), "clock_") {
+		t.Fatalf("Expected no clock annotations while clocks agree, got: %s", buf.String())
+	}
+
+	// NTP steps the wall clock back by a minute
+	buf.Reset()
+	clock.advance(time.Second)
+	clock.wall = clock.wall.Add(-time.Minute)
+	log.Info("after step")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected a meta entry and the entry, got: %s", buf.String())
+	}
+	if !strings.Contains(lines[0], "[WARN] clock step detected {clock_step_detected=true delta=-1m0s}") {
+		t.Errorf("Expected the step to be reported first, got: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], "after step {clock_suspect=true}") {
+		t.Errorf("Expected the entry to be marked suspect, got: %s", lines[1])
+	}
+
+	buf.Reset()
+	clock.advance(29 * time.Second)
+	log.With(logger.Field{Key: "k", Value: "v"}).Info("inside window")
+	if !strings.Contains(buf.String(), "inside window {k=v clock_suspect=true}") {
+		t.Errorf("Expected entries within the window to stay suspect, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	clock.advance(2 * time.Second)
+	log.Info("after window")
+	if strings.Contains(buf.String(), "clock_") {
+		t.Errorf("Expected annotations to stop after the window, got: %s", buf.String())
+	}
+}
+
+func TestClockStepDetectionDisabled(t *testing.T) {
+	clock := &steppingClock{wall: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)}
+
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Clock: func() time.Time { return clock.wall }})
+
+	log.Info("before")
+	clock.wall = clock.wall.Add(time.Hour)
+	log.Info("after")
+	if strings.Contains(buf.String(), "clock_") {
+		t.Errorf("Expected no detection unless a threshold is set, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// codeValue is the value of fields created with Code
+type codeValue string
+
+// strictViolationKey is the field used to report Strict mode problems when
+// no Config.OnStrictViolation handler is set
+const strictViolationKey = "_log_strict_violation"
+
+// Code returns a field holding a stable identifier for the statement, such
+// as DB_CONN_LOST, so alerts do not depend on message wording. Registered
+// codes also add a code_description field.
+func Code(code string) Field {
+	return Field{Key: "code", Value: codeValue(code)}
+}
+
+// CodeInfo describes a code registered with RegisterCode
+type CodeInfo struct {
+	Code         string `json:"code"`
+	Description  string `json:"description"`
+	DefaultLevel Level  `json:"default_level"`
+}
+
+var (
+	codesMu sync.Mutex
+	// codes holds an immutable map[string]CodeInfo, replaced on every
+	// registration so entries can be checked without locking
+	codes atomic.Value
+)
+
+func init() {
+	codes.Store(map[string]CodeInfo{})
+}
+
+// RegisterCode records a code with its description and the level it is
+// normally logged at. Registration is safe for concurrent use, including
+// from init functions. Registering a code twice is an error.
+func RegisterCode(code, description string, defaultLevel Level) error {
+	codesMu.Lock()
+	defer codesMu.Unlock()
+
+	current := registeredCodes()
+	if _, exists := current[code]; exists {
+		return fmt.Errorf("code %q is already registered", code)
+	}
+
+	next := make(map[string]CodeInfo, len(current)+1)
+	for k, v := range current {
+		next[k] = v
+	}
+	next[code] = CodeInfo{Code: code, Description: description, DefaultLevel: defaultLevel}
+	codes.Store(next)
+	return nil
+}
+
+// RegisteredCodes returns every registered code sorted by name, for
+// generating documentation
+func RegisteredCodes() []CodeInfo {
+	current := registeredCodes()
+	infos := make([]CodeInfo, 0, len(current))
+	for _, info := range current {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Code < infos[j].Code
+	})
+	return infos
+}
+
+func registeredCodes() map[string]CodeInfo {
+	return codes.Load().(map[string]CodeInfo)
+}
+
+// withCode prepends a Code field to the call-site fields
+func withCode(code string, fields []Field) []Field {
+	withCode := make([]Field, 0, len(fields)+1)
+	withCode = append(withCode, Code(code))
+	return append(withCode, fields...)
+}
+
+// appendCode adds a code field and, for registered codes, its description.
+// Unregistered codes are a violation in Strict mode.
+func (l *standardLogger) appendCode(fields []Field, key string, code codeValue) []Field {
+	fields = append(fields, Field{Key: key, Value: string(code)})
+
+	info, ok := registeredCodes()[string(code)]
+	if ok {
+		return append(fields, Field{Key: key + "_description", Value: info.Description})
+	}
+	if l.strict {
+		return l.violation(fields, fmt.Errorf("code %q is not registered", string(code)))
+	}
+	return fields
+}
+
+// violation reports a Strict mode problem through the configured handler,
+// or as a field on the entry being written
+func (l *standardLogger) violation(fields []Field, err error) []Field {
+	if l.onViolate != nil {
+		l.onViolate(err)
+		return fields
+	}
+	return append(fields, Field{Key: strictViolationKey, Value: err.Error()})
+}
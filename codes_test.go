@@ -0,0 +1,118 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestCodeDescription(t *testing.T) {
+	if err := logger.RegisterCode("TEST_DB_CONN_LOST", "Database connection lost", logger.ErrorLevel); err != nil {
+		t.Fatalf("RegisterCode failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+	log.(logger.CodeLogger).ErrorCode("TEST_DB_CONN_LOST", "Connection dropped", logger.Field{Key: "attempt", Value: 3})
+
+	expected := "{code=TEST_DB_CONN_LOST code_description=\"Database connection lost\" attempt=3}"
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("Expected log output to contain %q, got: %s", expected, buf.String())
+	}
+	if !strings.Contains(buf.String(), "[ERROR]") {
+		t.Errorf("Expected ErrorCode to log at error level, got: %s", buf.String())
+	}
+}
+
+func TestRegisterCodeDuplicate(t *testing.T) {
+	if err := logger.RegisterCode("TEST_DUPLICATE", "first", logger.WarnLevel); err != nil {
+		t.Fatalf("RegisterCode failed: %v", err)
+	}
+	if err := logger.RegisterCode("TEST_DUPLICATE", "second", logger.WarnLevel); err == nil {
+		t.Error("Expected registering a code twice to fail")
+	}
+}
+
+func TestRegisteredCodes(t *testing.T) {
+	for _, code := range []string{"TEST_EXPORT_B", "TEST_EXPORT_A"} {
+		if err := logger.RegisterCode(code, "exported", logger.WarnLevel); err != nil {
+			t.Fatalf("RegisterCode failed: %v", err)
+		}
+	}
+
+	var exported []string
+	for _, info := range logger.RegisteredCodes() {
+		if strings.HasPrefix(info.Code, "TEST_EXPORT_") {
+			exported = append(exported, info.Code)
+			if info.DefaultLevel != logger.WarnLevel {
+				t.Errorf("Expected default level %v for %s, got %v", logger.WarnLevel, info.Code, info.DefaultLevel)
+			}
+		}
+	}
+	if strings.Join(exported, ",") != "TEST_EXPORT_A,TEST_EXPORT_B" {
+		t.Errorf("Expected sorted exported codes, got %v", exported)
+	}
+}
+
+func TestUnknownCode(t *testing.T) {
+	t.Run("lenient", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := logger.New(logger.Config{Output: &buf})
+		log.(logger.CodeLogger).WarnCode("TEST_UNKNOWN", "Something happened")
+
+		if !strings.Contains(buf.String(), "{code=TEST_UNKNOWN}") {
+			t.Errorf("Expected unknown code to be logged as is, got: %s", buf.String())
+		}
+	})
+
+	t.Run("strict field", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := logger.New(logger.Config{Output: &buf, Strict: true})
+		log.With(logger.Code("TEST_UNKNOWN")).Info("Something happened")
+
+		if !strings.Contains(buf.String(), `_log_strict_violation="code \"TEST_UNKNOWN\" is not registered"`) {
+			t.Errorf("Expected strict violation field, got: %s", buf.String())
+		}
+	})
+
+	t.Run("strict handler", func(t *testing.T) {
+		var mu sync.Mutex
+		var violations []error
+		var buf bytes.Buffer
+		log := logger.New(logger.Config{
+			Output: &buf,
+			Strict: true,
+			OnStrictViolation: func(err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				violations = append(violations, err)
+			},
+		})
+		log.(logger.CodeLogger).ErrorCode("TEST_UNKNOWN", "Something happened")
+
+		if len(violations) != 1 {
+			t.Fatalf("Expected 1 violation, got %d", len(violations))
+		}
+		if strings.Contains(buf.String(), "_log_strict_violation") {
+			t.Errorf("Expected violation to go to the handler only, got: %s", buf.String())
+		}
+	})
+}
+
+func TestMultiLoggerCodes(t *testing.T) {
+	var buf1, buf2 bytes.Buffer
+	multi := logger.MultiLogger(
+		logger.New(logger.Config{Output: &buf1}),
+		logger.New(logger.Config{Output: &buf2}),
+	)
+	multi.(logger.CodeLogger).WarnCode("TEST_MULTI", "Both outputs")
+
+	for i, buf := range []*bytes.Buffer{&buf1, &buf2} {
+		if !strings.Contains(buf.String(), "[WARN] Both outputs {code=TEST_MULTI}") {
+			t.Errorf("Logger %d: expected coded warning, got: %s", i+1, buf.String())
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// ColorMode selects whether text output colors the level token
+type ColorMode int
+
+const (
+	// ColorAuto colors output written to a terminal unless the NO_COLOR
+	// environment variable is set. It is the default.
+	ColorAuto ColorMode = iota
+	// ColorAlways colors output regardless of where it is written
+	ColorAlways
+	// ColorNever never writes ANSI escapes
+	ColorNever
+)
+
+const ansiReset = "\x1b[0m"
+
+// ANSIColor is one of the 16 standard terminal colors, or ANSIDefault to
+// leave the terminal's color unchanged
+type ANSIColor int
+
+const (
+	ANSIDefault ANSIColor = iota
+	ANSIBlack
+	ANSIRed
+	ANSIGreen
+	ANSIYellow
+	ANSIBlue
+	ANSIMagenta
+	ANSICyan
+	ANSIWhite
+	ANSIBrightBlack
+	ANSIBrightRed
+	ANSIBrightGreen
+	ANSIBrightYellow
+	ANSIBrightBlue
+	ANSIBrightMagenta
+	ANSIBrightCyan
+	ANSIBrightWhite
+)
+
+// Style is how a level token is rendered. The zero Style is plain text.
+type Style struct {
+	Foreground ANSIColor
+	Background ANSIColor
+	Bold       bool
+	Dim        bool
+}
+
+// ColorScheme maps levels to the style of their token in colored output.
+// Levels missing from the scheme use the default style.
+type ColorScheme map[Level]Style
+
+// defaultColorScheme colors DEBUG dim, INFO green, WARN yellow and ERROR
+// and FATAL red
+var defaultColorScheme = ColorScheme{
+	DebugLevel: {Dim: true},
+	InfoLevel:  {Foreground: ANSIGreen},
+	WarnLevel:  {Foreground: ANSIYellow},
+	ErrorLevel: {Foreground: ANSIRed},
+	FatalLevel: {Foreground: ANSIRed},
+}
+
+// HighContrastColorScheme returns a scheme for dark terminals that avoids
+// plain yellow and dim text
+func HighContrastColorScheme() ColorScheme {
+	return ColorScheme{
+		DebugLevel: {Foreground: ANSIWhite},
+		InfoLevel:  {Foreground: ANSIBrightGreen, Bold: true},
+		WarnLevel:  {Foreground: ANSIBlack, Background: ANSIBrightYellow, Bold: true},
+		ErrorLevel: {Foreground: ANSIBrightWhite, Background: ANSIRed, Bold: true},
+		FatalLevel: {Foreground: ANSIBrightWhite, Background: ANSIRed, Bold: true},
+	}
+}
+
+// Validate reports levels and colors outside the defined ranges
+func (s ColorScheme) Validate() error {
+	for level, style := range s {
+		if level < DebugLevel || level > FatalLevel {
+			return fmt.Errorf("color scheme: unknown level %d", int(level))
+		}
+		for _, c := range []ANSIColor{style.Foreground, style.Background} {
+			if c < ANSIDefault || c > ANSIBrightWhite {
+				return fmt.Errorf("color scheme: %s: unknown color %d", level, int(c))
+			}
+		}
+	}
+	return nil
+}
+
+// sequence returns the escape sequence selecting the style, or "" for
+// plain text
+func (st Style) sequence() string {
+	var params []string
+	if st.Bold {
+		params = append(params, "1")
+	}
+	if st.Dim {
+		params = append(params, "2")
+	}
+	if st.Foreground != ANSIDefault {
+		params = append(params, strconv.Itoa(colorCode(st.Foreground, 30)))
+	}
+	if st.Background != ANSIDefault {
+		params = append(params, strconv.Itoa(colorCode(st.Background, 40)))
+	}
+	if len(params) == 0 {
+		return ""
+	}
+	return "\x1b[" + strings.Join(params, ";") + "m"
+}
+
+// colorCode returns the SGR parameter for c given the base of its normal
+// range, 30 for foreground or 40 for background
+func colorCode(c ANSIColor, base int) int {
+	if c >= ANSIBrightBlack {
+		return base + 60 + int(c-ANSIBrightBlack)
+	}
+	return base + int(c-ANSIBlack)
+}
+
+// levelColors resolves a scheme over the defaults into escape sequences
+func levelColors(scheme ColorScheme) map[Level]string {
+	colors := make(map[Level]string, len(defaultColorScheme))
+	for level, style := range defaultColorScheme {
+		colors[level] = style.sequence()
+	}
+	for level, style := range scheme {
+		colors[level] = style.sequence()
+	}
+	return colors
+}
+
+// colorable reports whether the format colors the level token
+func (f Format) colorable() bool {
+	return f == FormatText || f == FormatConsole
+}
+
+// updateColor decides whether output to w is colored
+func (o *outputState) updateColor(w io.Writer) {
+	var colored int32
+	if useColor(o.colorMode, w) {
+		colored = 1
+	}
+	atomic.StoreInt32(&o.colored, colored)
+}
+
+// useColor decides whether output to w is colored in the given mode
+func useColor(mode ColorMode, w io.Writer) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(w)
+}
+
+// isTerminal reports whether w is a file attached to a character device,
+// such as a terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorLevel wraps an encoded level token in its color when the output
+// is colored
+func (l *standardLogger) colorLevel(level Level, token string) string {
+	if atomic.LoadInt32(&l.out.colored) == 0 {
+		return token
+	}
+	seq := l.colors[level]
+	if seq == "" {
+		return token
+	}
+	return seq + token + ansiReset
+}
@@ -0,0 +1,144 @@
+package logger_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestColorOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     logger.ColorMode
+		format   logger.Format
+		expected string
+	}{
+		{name: "buffer is never colored automatically", mode: logger.ColorAuto, expected: "[WARN] "},
+		{name: "forced on", mode: logger.ColorAlways, expected: "[\x1b[33mWARN\x1b[0m] "},
+		{name: "forced off", mode: logger.ColorNever, expected: "[WARN] "},
+		{name: "json is never colored", mode: logger.ColorAlways, format: logger.FormatJSON, expected: `"level":"WARN"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := logger.New(logger.Config{Output: &buf, Color: tt.mode, Format: tt.format})
+			log.Warn("colored", logger.Field{Key: "k", Value: "v"})
+
+			output := buf.String()
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected output to contain %q, got %q", tt.expected, output)
+			}
+			if tt.mode != logger.ColorAlways || tt.format != logger.FormatText {
+				if strings.Contains(output, "\x1b[") {
+					t.Errorf("Expected no ANSI escapes, got %q", output)
+				}
+			} else if strings.Count(output, "\x1b[") != 2 {
+				t.Errorf("Expected only the level token to be colored, got %q", output)
+			}
+		})
+	}
+}
+
+func TestColorLevels(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Color: logger.ColorAlways, Level: logger.DebugLevel})
+	log.Debug("d")
+	log.Info("i")
+	log.Error("e")
+
+	for _, expected := range []string{"\x1b[2mDEBUG", "\x1b[32mINFO", "\x1b[31mERROR"} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("Expected output to contain %q, got %q", expected, buf.String())
+		}
+	}
+}
+
+func TestColorTerminalDetection(t *testing.T) {
+	// /dev/null is a character device, like a terminal
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Skipf("Cannot open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+	if info, err := devNull.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		t.Skipf("%s is not a character device", os.DevNull)
+	}
+
+	t.Setenv("NO_COLOR", "")
+	log := logger.New(logger.Config{Output: devNull})
+	if !logger.Describe(log).Color {
+		t.Error("Expected output to a character device to be colored")
+	}
+
+	var buf bytes.Buffer
+	if err := log.(logger.OutputSetter).SetOutput(&buf); err != nil {
+		t.Fatalf("SetOutput failed: %v", err)
+	}
+	log.Info("after switch")
+	if logger.Describe(log).Color || strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("Expected switching to a buffer to disable color, got %q", buf.String())
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if logger.Describe(logger.New(logger.Config{Output: devNull})).Color {
+		t.Error("Expected NO_COLOR to disable automatic color")
+	}
+	if !logger.Describe(logger.New(logger.Config{Output: devNull, Color: logger.ColorAlways})).Color {
+		t.Error("Expected ColorAlways to override NO_COLOR")
+	}
+}
+
+func TestColorScheme(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{
+		Output: &buf,
+		Color:  logger.ColorAlways,
+		ColorScheme: logger.ColorScheme{
+			logger.WarnLevel:  {Foreground: logger.ANSIBrightMagenta, Bold: true},
+			logger.ErrorLevel: {Foreground: logger.ANSIWhite, Background: logger.ANSIRed},
+			logger.DebugLevel: {},
+		},
+		Level: logger.DebugLevel,
+	})
+	log.Debug("d")
+	log.Info("i")
+	log.Warn("w")
+	log.Error("e")
+
+	output := buf.String()
+	for _, expected := range []string{"[DEBUG]", "[\x1b[32mINFO\x1b[0m]", "[\x1b[1;95mWARN\x1b[0m]", "[\x1b[37;41mERROR\x1b[0m]"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got %q", expected, output)
+		}
+	}
+}
+
+func TestColorSchemeValidation(t *testing.T) {
+	if err := logger.HighContrastColorScheme().Validate(); err != nil {
+		t.Errorf("Expected the high contrast scheme to be valid, got %v", err)
+	}
+
+	invalid := []logger.ColorScheme{
+		{logger.Level(42): {Foreground: logger.ANSIRed}},
+		{logger.InfoLevel: {Foreground: logger.ANSIColor(99)}},
+		{logger.InfoLevel: {Background: logger.ANSIColor(-1)}},
+	}
+	for _, scheme := range invalid {
+		if err := scheme.Validate(); err == nil {
+			t.Errorf("Expected %v to be rejected", scheme)
+		}
+
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Expected New to panic for %v", scheme)
+				}
+			}()
+			logger.New(logger.Config{Output: &bytes.Buffer{}, ColorScheme: scheme})
+		}()
+	}
+}
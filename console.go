@@ -0,0 +1,114 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultConsoleMessageWidth is the column width messages are padded to
+// in FormatConsole when Config.ConsoleMessageWidth is not set
+const DefaultConsoleMessageWidth = 40
+
+// consoleTimeFormat is the short timestamp of FormatConsole
+const consoleTimeFormat = "15:04:05.000"
+
+// consoleLevelLabels are the four character level labels of FormatConsole
+var consoleLevelLabels = map[Level]string{
+	DebugLevel: "DEBU",
+	InfoLevel:  "INFO",
+	WarnLevel:  "WARN",
+	ErrorLevel: "ERRO",
+	FatalLevel: "FATA",
+}
+
+// ansiDim is the style of field pairs in colored console output
+const ansiDim = "\x1b[2m"
+
+// encodeConsole renders an entry for reading in a development terminal:
+// a short timestamp, the level, the message padded to the message width
+// and the fields as key=value pairs. A message too long for its column is
+// followed by a line break, so the fields start in the same column on
+// every entry. Continuation lines of multi-line values are indented to
+// that column too.
+func (l *standardLogger) encodeConsole(now time.Time, level Level, msg string, fields []Field) string {
+	var b bytes.Buffer
+	var failures []string
+
+	label, ok := consoleLevelLabels[level]
+	if !ok {
+		label = fmt.Sprintf("%-4.4s", level.String())
+	}
+	b.WriteString(now.Format(consoleTimeFormat))
+	b.WriteString(" ")
+	b.WriteString(l.colorLevel(level, label))
+	b.WriteString(" ")
+	b.WriteString(msg)
+
+	var pairs bytes.Buffer
+	indent := strings.Repeat(" ", len(consoleTimeFormat)+len(" ")+4+len(" ")+l.consoleWidth+len(" "))
+	l.writeConsoleFields(&pairs, "", fields, indent, &failures)
+	if len(failures) > 0 {
+		writeConsolePair(&pairs, internalErrorKey, strings.Join(failures, "; "), indent)
+		l.formatFailed(failures)
+	}
+	if pairs.Len() == 0 {
+		return b.String()
+	}
+
+	if width := utf8.RuneCountInString(msg); width <= l.consoleWidth {
+		b.WriteString(strings.Repeat(" ", l.consoleWidth-width))
+	} else {
+		b.WriteString("\n")
+		b.WriteString(indent[:len(indent)-1])
+	}
+	if atomic.LoadInt32(&l.out.colored) != 0 {
+		b.WriteString(ansiDim)
+		b.Write(pairs.Bytes())
+		b.WriteString(ansiReset)
+	} else {
+		b.Write(pairs.Bytes())
+	}
+	return b.String()
+}
+
+// writeConsoleFields writes fields as space prefixed key=value pairs.
+// Grouped fields are expanded with their keys prefixed by the group name.
+func (l *standardLogger) writeConsoleFields(b *bytes.Buffer, prefix string, fields []Field, indent string, failures *[]string) {
+	for _, field := range fields {
+		key := prefix + field.Key
+		if group, ok := field.Value.(groupValue); ok {
+			l.writeConsoleFields(b, key+".", group, indent, failures)
+			continue
+		}
+
+		var value string
+		var err error
+		if trace, ok := field.Value.(stackTrace); ok {
+			value = trace.String()
+		} else {
+			value, err = l.formatTextValue(field.Value)
+		}
+		if err != nil {
+			*failures = append(*failures, fmt.Sprintf("%s: %v", key, err))
+		}
+		writeConsolePair(b, key, value, indent)
+	}
+}
+
+// writeConsolePair writes one pair. Multi-line values are written as they
+// are, with continuation lines indented; other values are quoted when
+// logfmt would quote them.
+func writeConsolePair(b *bytes.Buffer, key, value, indent string) {
+	b.WriteByte(' ')
+	writeLogfmtKey(b, key)
+	b.WriteByte('=')
+	if strings.Contains(value, "\n") {
+		b.WriteString(strings.ReplaceAll(strings.TrimRight(value, "\n"), "\n", "\n"+indent+"  "))
+		return
+	}
+	writeLogfmtValue(b, value)
+}
@@ -0,0 +1,93 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func newConsoleLogger(buf *bytes.Buffer, color logger.ColorMode) logger.Logger {
+	now := time.Date(2024, 3, 1, 12, 30, 45, 123*int(time.Millisecond), time.UTC)
+	return logger.New(logger.Config{
+		Output:              buf,
+		Format:              logger.FormatConsole,
+		Level:               logger.DebugLevel,
+		Color:               color,
+		ConsoleMessageWidth: 12,
+		Clock:               fixedClock(now),
+	})
+}
+
+func TestConsoleFormat(t *testing.T) {
+	var buf bytes.Buffer
+	log := newConsoleLogger(&buf, logger.ColorNever)
+
+	log.Debug("started", logger.Field{Key: "port", Value: 8080})
+	log.Info("ready")
+	log.Warn("slow", logger.Field{Key: "note", Value: "two words"}, logger.Group("db", logger.Field{Key: "ms", Value: 12}))
+
+	expected := "" +
+		"12:30:45.123 DEBU started      port=8080\n" +
+		"12:30:45.123 INFO ready\n" +
+		"12:30:45.123 WARN slow         note=\"two words\" db.ms=12\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestConsoleLongMessageKeepsAlignment(t *testing.T) {
+	var buf bytes.Buffer
+	log := newConsoleLogger(&buf, logger.ColorNever)
+
+	log.Info("short", logger.Field{Key: "a", Value: 1})
+	log.Info("a message longer than the column", logger.Field{Key: "b", Value: 2})
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected the long message's fields on their own line, got %q", buf.String())
+	}
+	if strings.Index(lines[0], "a=1") != strings.Index(lines[2], "b=2") {
+		t.Errorf("Expected fields in the same column, got:\n%s", buf.String())
+	}
+}
+
+func TestConsoleMultiLineValue(t *testing.T) {
+	var buf bytes.Buffer
+	log := newConsoleLogger(&buf, logger.ColorNever)
+
+	log.Error("query failed", logger.Field{Key: "sql", Value: "SELECT *\nFROM users\n"})
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], "sql=SELECT *") {
+		t.Fatalf("Expected the value's lines written as they are, got %q", buf.String())
+	}
+	if lines[1] != strings.Repeat(" ", strings.Index(lines[0], "sql=")+2)+"FROM users" {
+		t.Errorf("Expected the continuation indented under the fields, got %q", lines[1])
+	}
+}
+
+func TestConsoleColor(t *testing.T) {
+	var buf bytes.Buffer
+	log := newConsoleLogger(&buf, logger.ColorAlways)
+
+	log.Warn("slow", logger.Field{Key: "ms", Value: 900})
+
+	if !strings.Contains(buf.String(), "\x1b[33mWARN\x1b[0m") {
+		t.Errorf("Expected a colored level, got %q", buf.String())
+	}
+	if !strings.Contains(buf.String(), "\x1b[2m ms=900\x1b[0m") {
+		t.Errorf("Expected dim fields, got %q", buf.String())
+	}
+}
+
+func TestFactoryDev(t *testing.T) {
+	log := logger.NewFactory(logger.DefaultConfig).Dev()
+
+	d := logger.Describe(log)
+	if d.Format != "console" || d.Level != "DEBUG" {
+		t.Errorf("Expected a debug console logger, got format %q level %q", d.Format, d.Level)
+	}
+}
@@ -1,6 +1,12 @@
 package logger
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
 type requestIDKey struct{}
 type userIDKey struct{}
@@ -38,3 +44,178 @@ func GetSessionID(ctx context.Context) (string, bool) {
 	value, ok := ctx.Value(SessionIDKey).(string)
 	return value, ok
 }
+
+type startTimeKey struct{}
+
+var StartTimeKey = startTimeKey{}
+
+type minLevelKey struct{}
+
+// WithMinLevel returns a context that overrides the minimum level of
+// loggers derived from it with WithContext, for example to log one request
+// at DebugLevel in production. Unless Config.AllowLevelRaise is set, the
+// override can only lower the configured level. Entries from overridden
+// loggers carry a level_override field.
+func WithMinLevel(ctx context.Context, level Level) context.Context {
+	return context.WithValue(ctx, minLevelKey{}, level)
+}
+
+// GetMinLevel returns the level override stored in ctx
+func GetMinLevel(ctx context.Context) (Level, bool) {
+	level, ok := ctx.Value(minLevelKey{}).(Level)
+	return level, ok
+}
+
+// WithStartTime records when work on ctx began so WarnIfNearDeadline can
+// measure how much of the deadline was consumed
+func WithStartTime(ctx context.Context, start time.Time) context.Context {
+	return context.WithValue(ctx, StartTimeKey, start)
+}
+
+func GetStartTime(ctx context.Context) (time.Time, bool) {
+	value, ok := ctx.Value(StartTimeKey).(time.Time)
+	return value, ok
+}
+
+// WarnIfNearDeadline logs msg at Warn level when more than threshold (a
+// fraction between 0 and 1) of the time between ctx's start time and its
+// deadline has been used. The entry includes deadline_total,
+// deadline_used and deadline_remaining fields. It does nothing when ctx
+// has no deadline or no start time recorded with WithStartTime. The time
+// used is measured with l's Config.Clock.
+func WarnIfNearDeadline(ctx context.Context, l LevelLogger, threshold float64, msg string, fields ...Field) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return
+	}
+	start, ok := GetStartTime(ctx)
+	if !ok {
+		return
+	}
+
+	total := deadline.Sub(start)
+	used := clockOf(l)().Sub(start)
+	if total <= 0 || float64(used) <= threshold*float64(total) {
+		return
+	}
+
+	l.Warn(msg, append([]Field{
+		{Key: "deadline_total", Value: total},
+		{Key: "deadline_used", Value: used},
+		{Key: "deadline_remaining", Value: total - used},
+	}, fields...)...)
+}
+
+// contextField describes a context value that WithContext turns into a
+// field
+type contextField struct {
+	key       any
+	name      string
+	transform func(any) any
+}
+
+var (
+	contextFieldsMu sync.Mutex
+	// contextFields holds an immutable []contextField, replaced on every
+	// registration so WithContext can read it without locking
+	contextFields atomic.Value
+)
+
+func init() {
+	contextFields.Store([]contextField{
+		{key: RequestIDKey, name: "request_id", transform: stringValue},
+		{key: UserIDKey, name: "user_id", transform: stringValue},
+		{key: SessionIDKey, name: "session_id", transform: stringValue},
+	})
+}
+
+// stringValue keeps only string values, as the built-in ID helpers store
+func stringValue(value any) any {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return nil
+}
+
+// RegisterContextField makes WithContext add a field named fieldName
+// whenever ctx.Value(key) is not nil. transform, if not nil, converts the
+// context value into the field value; returning nil omits the field.
+//
+// Registration is safe for concurrent use, including from init functions
+// in different packages. Registering a fieldName twice is an error.
+func RegisterContextField(key any, fieldName string, transform func(any) any) error {
+	contextFieldsMu.Lock()
+	defer contextFieldsMu.Unlock()
+
+	current := registeredContextFields()
+	for _, cf := range current {
+		if cf.name == fieldName {
+			return fmt.Errorf("context field %q is already registered", fieldName)
+		}
+	}
+
+	next := make([]contextField, len(current), len(current)+1)
+	copy(next, current)
+	next = append(next, contextField{key: key, name: fieldName, transform: transform})
+	contextFields.Store(next)
+	return nil
+}
+
+func registeredContextFields() []contextField {
+	return contextFields.Load().([]contextField)
+}
+
+// ContextExtractor describes one field WithContext adds from a context:
+// the field named Field is added whenever ctx.Value(Key) is not nil, with
+// its value converted by Transform if Transform is not nil. Returning nil
+// from Transform omits the field.
+type ContextExtractor struct {
+	Key       any
+	Field     string
+	Transform func(any) any
+}
+
+// RegisteredContextExtractors returns the global extractors, the built-in
+// ID fields followed by those added with RegisterContextField. It is a
+// starting point for per-logger sets passed to WithContextExtractors.
+func RegisteredContextExtractors() []ContextExtractor {
+	registered := registeredContextFields()
+	extractors := make([]ContextExtractor, len(registered))
+	for i, cf := range registered {
+		extractors[i] = ContextExtractor{Key: cf.key, Field: cf.name, Transform: cf.transform}
+	}
+	return extractors
+}
+
+// contextFieldsFrom extracts the given fields present in ctx. A transform
+// that panics omits its field.
+func contextFieldsFrom(ctx context.Context, registered []contextField) []Field {
+	if len(registered) == 0 {
+		return nil
+	}
+
+	var fields []Field
+	for _, cf := range registered {
+		value := ctx.Value(cf.key)
+		if value == nil {
+			continue
+		}
+		if cf.transform != nil {
+			value = safeTransform(cf.transform, value)
+			if value == nil {
+				continue
+			}
+		}
+		fields = append(fields, Field{Key: cf.name, Value: value})
+	}
+	return fields
+}
+
+func safeTransform(transform func(any) any, value any) (result any) {
+	defer func() {
+		if recover() != nil {
+			result = nil
+		}
+	}()
+	return transform(value)
+}
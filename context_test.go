@@ -0,0 +1,355 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestWarnIfNearDeadline(t *testing.T) {
+	tests := []struct {
+		name      string
+		ctx       func() (context.Context, context.CancelFunc)
+		threshold float64
+		expectLog bool
+	}{
+		{
+			name: "no deadline",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return logger.WithStartTime(context.Background(), time.Now().Add(-time.Hour)), func() {}
+			},
+			threshold: 0.5,
+		},
+		{
+			name: "no start time",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Second)
+			},
+			threshold: 0,
+		},
+		{
+			name: "plenty of time left",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+				return logger.WithStartTime(ctx, time.Now()), cancel
+			},
+			threshold: 0.9,
+		},
+		{
+			name: "close to deadline",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				return logger.WithStartTime(ctx, time.Now().Add(-10*time.Second)), cancel
+			},
+			threshold: 0.9,
+			expectLog: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			var buf bytes.Buffer
+			log := logger.New(logger.Config{Output: &buf})
+			logger.WarnIfNearDeadline(ctx, log, tt.threshold, "slow handler", logger.Field{Key: "route", Value: "/checkout"})
+
+			output := buf.String()
+			if !tt.expectLog {
+				if output != "" {
+					t.Errorf("Expected no log output, got: %s", output)
+				}
+				return
+			}
+			for _, expected := range []string{"[WARN] slow handler", "deadline_total=", "deadline_used=", "deadline_remaining=", "route=/checkout"} {
+				if !strings.Contains(output, expected) {
+					t.Errorf("Expected log output to contain %q, got: %s", expected, output)
+				}
+			}
+		})
+	}
+}
+
+func TestWarnIfNearDeadlineUsesLoggerClock(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	ctx, cancel := context.WithDeadline(context.Background(), start.Add(10*time.Second))
+	defer cancel()
+	ctx = logger.WithStartTime(ctx, start)
+
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Clock: fixedClock(start.Add(8 * time.Second))})
+
+	logger.WarnIfNearDeadline(ctx, log, 0.9, "not yet")
+	if buf.Len() != 0 {
+		t.Fatalf("Expected no entry at 80%% of the deadline, got: %s", buf.String())
+	}
+
+	logger.WarnIfNearDeadline(ctx, log, 0.75, "slow handler")
+	if !strings.Contains(buf.String(), "deadline_total=10s deadline_used=8s deadline_remaining=2s") {
+		t.Errorf("Expected durations measured with the logger clock, got: %s", buf.String())
+	}
+}
+
+type tenantKey struct{}
+type bucketKey struct{}
+
+func TestRegisterContextField(t *testing.T) {
+	if err := logger.RegisterContextField(tenantKey{}, "tenant_id", nil); err != nil {
+		t.Fatalf("Failed to register context field: %v", err)
+	}
+	err := logger.RegisterContextField(bucketKey{}, "ab_bucket", func(v any) any {
+		if n, ok := v.(int); ok && n >= 0 {
+			return fmt.Sprintf("bucket-%d", n)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to register context field: %v", err)
+	}
+
+	if err := logger.RegisterContextField(struct{}{}, "tenant_id", nil); err == nil {
+		t.Error("Expected duplicate field name to be rejected")
+	}
+	if err := logger.RegisterContextField(struct{}{}, "request_id", nil); err == nil {
+		t.Error("Expected built-in field name to be rejected")
+	}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected string
+		absent   string
+	}{
+		{
+			name:     "registered values",
+			ctx:      context.WithValue(context.WithValue(context.Background(), tenantKey{}, "acme"), bucketKey{}, 3),
+			expected: "{tenant_id=acme ab_bucket=bucket-3}",
+		},
+		{
+			name:   "transform omits value",
+			ctx:    context.WithValue(context.Background(), bucketKey{}, -1),
+			absent: "ab_bucket",
+		},
+		{
+			name:     "built-in IDs still work",
+			ctx:      logger.WithRequestID(context.WithValue(context.Background(), tenantKey{}, "acme"), "req-1"),
+			expected: "{request_id=req-1 tenant_id=acme}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger.New(logger.Config{Output: &buf}).WithContext(tt.ctx).Info("registered")
+
+			output := buf.String()
+			if tt.expected != "" && !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected log output to contain %q, got: %s", tt.expected, output)
+			}
+			if tt.absent != "" && strings.Contains(output, tt.absent) {
+				t.Errorf("Expected log output not to contain %q, got: %s", tt.absent, output)
+			}
+		})
+	}
+}
+
+func TestRegisterContextFieldConcurrent(t *testing.T) {
+	type key int
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- logger.RegisterContextField(key(i), fmt.Sprintf("concurrent_%d", i%10), nil)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	failures := 0
+	for err := range errs {
+		if err != nil {
+			failures++
+		}
+	}
+	if failures != 10 {
+		t.Errorf("Expected 10 duplicate registrations to fail, got %d", failures)
+	}
+}
+
+func TestWithMinLevel(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Level: logger.InfoLevel})
+	ctx := logger.WithMinLevel(context.Background(), logger.DebugLevel)
+
+	requestLog := log.WithContext(ctx)
+	logger.Named(requestLog, "db").With(logger.Field{Key: "k", Value: "v"}).Debug("child debug")
+	if !strings.Contains(buf.String(), "[DEBUG] child debug {logger=db k=v level_override=debug}") {
+		t.Errorf("Expected debug entry from a derived request logger, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	log.Debug("other request")
+	if buf.Len() != 0 {
+		t.Errorf("Expected loggers outside the context to keep their level, got: %s", buf.String())
+	}
+}
+
+func TestWithMinLevelRaise(t *testing.T) {
+	ctx := logger.WithMinLevel(context.Background(), logger.ErrorLevel)
+
+	var buf bytes.Buffer
+	lowerOnly := logger.New(logger.Config{Output: &buf}).WithContext(ctx)
+	lowerOnly.Info("kept")
+	if !strings.Contains(buf.String(), "kept") {
+		t.Errorf("Expected the override not to raise the level by default, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	raising := logger.New(logger.Config{Output: &buf, AllowLevelRaise: true}).WithContext(ctx)
+	raising.Info("filtered")
+	if buf.Len() != 0 {
+		t.Errorf("Expected AllowLevelRaise to let the override raise the level, got: %s", buf.String())
+	}
+	if raising.Enabled(logger.WarnLevel) || !raising.Enabled(logger.ErrorLevel) {
+		t.Error("Expected Enabled to reflect the raised level")
+	}
+}
+
+type sessionKey struct{}
+
+func TestWithContextExtractors(t *testing.T) {
+	ctx := logger.WithUserID(logger.WithRequestID(context.Background(), "req-1"), "u-1")
+	ctx = context.WithValue(ctx, sessionKey{}, "s-1")
+
+	var buf bytes.Buffer
+	base := logger.New(logger.Config{Output: &buf})
+
+	tests := []struct {
+		name     string
+		log      logger.Logger
+		expected string
+		absent   []string
+	}{
+		{
+			name:     "global extractors",
+			log:      base,
+			expected: "{request_id=req-1 user_id=u-1}",
+			absent:   []string{"session"},
+		},
+		{
+			name: "custom extractors",
+			log: logger.WithContextExtractors(base, logger.ContextExtractor{
+				Key:   sessionKey{},
+				Field: "session",
+				Transform: func(v any) any {
+					return strings.ToUpper(v.(string))
+				},
+			}),
+			expected: "{session=S-1}",
+			absent:   []string{"request_id", "user_id"},
+		},
+		{
+			name:     "excluded field",
+			log:      logger.ExcludeContextFields(base, "user_id"),
+			expected: "{request_id=req-1}",
+			absent:   []string{"user_id"},
+		},
+		{
+			name:     "survives With and Named",
+			log:      logger.Named(logger.ExcludeContextFields(base, "user_id").With(logger.Field{Key: "k", Value: "v"}), "db"),
+			expected: "request_id=req-1",
+			absent:   []string{"user_id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			tt.log.WithContext(ctx).Info("extracted")
+
+			output := buf.String()
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected log output to contain %q, got: %s", tt.expected, output)
+			}
+			for _, absent := range tt.absent {
+				if strings.Contains(output, absent) {
+					t.Errorf("Expected log output not to contain %q, got: %s", absent, output)
+				}
+			}
+		})
+	}
+}
+
+func TestExcludeContextFieldsDoesNotLeak(t *testing.T) {
+	var buf bytes.Buffer
+	base := logger.ExcludeContextFields(logger.New(logger.Config{Output: &buf}), "user_id")
+	_ = logger.ExcludeContextFields(base, "request_id")
+
+	ctx := logger.WithRequestID(context.Background(), "req-1")
+	base.WithContext(ctx).Info("shared")
+	if !strings.Contains(buf.String(), "request_id=req-1") {
+		t.Errorf("Expected sibling exclusion not to affect the parent, got: %s", buf.String())
+	}
+
+	d := logger.Describe(base)
+	for _, name := range d.ContextFields {
+		if name == "user_id" {
+			t.Errorf("Expected excluded field to be absent from the description, got %v", d.ContextFields)
+		}
+	}
+	if len(d.ContextFields) == 0 {
+		t.Error("Expected the description to list context fields")
+	}
+}
+
+func TestContextGroup(t *testing.T) {
+	ctx := logger.WithUserID(logger.WithRequestID(context.Background(), "req-1"), "user-1")
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := logger.New(logger.Config{Output: &buf, ContextGroup: "ctx"}).
+			With(logger.Field{Key: "request_id", Value: "base"}).
+			WithContext(ctx)
+
+		log.Info("grouped", logger.Field{Key: "k", Value: 1})
+
+		expected := "{request_id=base ctx.request_id=req-1 ctx.user_id=user-1 k=1}"
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("Expected log output to contain %q, got: %s", expected, buf.String())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := logger.New(logger.Config{Output: &buf, Format: logger.FormatJSON, ContextGroup: "ctx"}).WithContext(ctx)
+
+		log.Info("grouped")
+
+		entry := decodeEntry(t, buf.String())
+		group, ok := entry["ctx"].(map[string]any)
+		if !ok || group["request_id"] != "req-1" || group["user_id"] != "user-1" || entry["request_id"] != nil {
+			t.Errorf("Expected context fields nested under ctx, got %v", entry)
+		}
+	})
+
+	t.Run("no context fields", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := logger.New(logger.Config{Output: &buf, ContextGroup: "ctx"}).WithContext(context.Background())
+
+		log.Info("plain", logger.Field{Key: "k", Value: 1})
+
+		if strings.Contains(buf.String(), "ctx") || !strings.Contains(buf.String(), "plain {k=1}") {
+			t.Errorf("Expected no empty group, got: %s", buf.String())
+		}
+	})
+}
@@ -0,0 +1,127 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+// updateGolden rewrites the golden files instead of comparing against
+// them. Any change it makes to testdata/contract is an output format
+// change that needs logger.FormatVersion bumped if it is incompatible.
+var updateGolden = flag.Bool("update-golden", false, "rewrite the output contract golden files")
+
+// contractVersion is the FormatVersion the golden files were written for
+const contractVersion = 1
+
+// stdlibDate matches the date the standard library writes in front of
+// text entries, which changes from run to run
+var stdlibDate = regexp.MustCompile(`(?m)^\d{4}/\d\d/\d\d \d\d:\d\d:\d\d `)
+
+// TestTextFormatContract pins the exact bytes of FormatText output that
+// downstream parsers depend on
+func TestTextFormatContract(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 123456789, time.UTC)
+
+	tests := []struct {
+		name string
+		cfg  logger.Config
+		log  func(logger.Logger)
+	}{
+		{
+			name: "no_fields",
+			log:  func(l logger.Logger) { l.Info("service started") },
+		},
+		{
+			name: "empty_message",
+			log:  func(l logger.Logger) { l.Warn("", logger.Field{Key: "k", Value: "v"}) },
+		},
+		{
+			name: "many_fields",
+			log: func(l logger.Logger) {
+				l.With(logger.Field{Key: "service", Value: "api"}).Error("request failed",
+					logger.Field{Key: "status", Value: 503},
+					logger.Field{Key: "latency", Value: 1500 * time.Millisecond},
+					logger.Field{Key: "ratio", Value: 0.25},
+					logger.Field{Key: "retry", Value: false},
+					logger.Field{Key: "err", Value: errors.New("upstream timeout")},
+					logger.Field{Key: "tags", Value: []string{"a", "b"}},
+					logger.Field{Key: "nil", Value: nil},
+					logger.Group("db", logger.Field{Key: "table", Value: "users"}, logger.Field{Key: "rows", Value: 3}),
+				)
+			},
+		},
+		{
+			name: "special_characters",
+			log: func(l logger.Logger) {
+				l.Info("line\nbreak\ttab \"quoted\" {braces} [INFO]",
+					logger.Field{Key: "space", Value: "two words"},
+					logger.Field{Key: "equals", Value: "a=b"},
+					logger.Field{Key: "quote", Value: `say "hi"`},
+					logger.Field{Key: "newline", Value: "x\ny"},
+					logger.Field{Key: "empty", Value: ""},
+					logger.Field{Key: "unicode", Value: "héllo 🎉"},
+					logger.Field{Key: "control", Value: "\x00\x1b"},
+				)
+			},
+		},
+		{
+			name: "all_levels",
+			cfg:  logger.Config{Level: logger.DebugLevel},
+			log: func(l logger.Logger) {
+				l.Debug("debug")
+				l.Info("info")
+				l.Warn("warn")
+				l.Error("error")
+			},
+		},
+		{
+			name: "default_time_format",
+			cfg:  logger.Config{TimeFormat: logger.DefaultConfig.TimeFormat},
+			log:  func(l logger.Logger) { l.Info("with the standard library date", logger.Field{Key: "k", Value: 1}) },
+		},
+	}
+
+	if logger.FormatVersion != contractVersion {
+		t.Errorf("FormatVersion is %d but the golden files pin version %d; regenerate them with -update-golden and update contractVersion", logger.FormatVersion, contractVersion)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			cfg := tt.cfg
+			cfg.Output = &buf
+			cfg.Clock = fixedClock(now)
+			cfg.UTC = true
+			if cfg.TimeFormat == "" {
+				cfg.TimeFormat = logger.TimeFormatRFC3339Nano
+			}
+			tt.log(logger.New(cfg))
+			got := stdlibDate.ReplaceAll(buf.Bytes(), []byte("<date> "))
+
+			path := filepath.Join("testdata", "contract", "text", tt.name+".golden")
+			if *updateGolden {
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			expected, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Missing golden file, run go test -run TestTextFormatContract -update-golden: %v", err)
+			}
+			if !bytes.Equal(got, expected) {
+				t.Errorf("Text output no longer matches %s. If the change is intended, bump FormatVersion when it is incompatible and run with -update-golden.\nexpected: %q\ngot:      %q", path, expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package logger
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// deltaTracker records when the previous entry was logged. Times are
+// stored as offsets from base so that readings from time.Now keep using
+// the monotonic clock.
+type deltaTracker struct {
+	base time.Time
+	last int64 // offset from base plus one, zero before the first entry
+}
+
+func newDeltaTracker(base time.Time) *deltaTracker {
+	return &deltaTracker{base: base}
+}
+
+// since records now as the latest entry and returns the milliseconds
+// elapsed since the previous one, or 0 for the first entry
+func (d *deltaTracker) since(now time.Time) float64 {
+	offset := int64(now.Sub(d.base))
+	prev := atomic.SwapInt64(&d.last, offset+1)
+	if prev == 0 {
+		return 0
+	}
+	return float64(offset-(prev-1)) / float64(time.Millisecond)
+}
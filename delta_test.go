@@ -0,0 +1,72 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestIncludeDelta(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{
+		Output:       &buf,
+		IncludeDelta: true,
+		Clock:        func() time.Time { return now },
+	})
+	child := log.With(logger.Field{Key: "child", Value: true})
+
+	log.Info("first")
+	now = now.Add(1500 * time.Microsecond)
+	child.Info("second")
+	now = now.Add(20 * time.Millisecond)
+	log.Info("third")
+
+	output := buf.String()
+	for _, expected := range []string{"first {delta_ms=0}", "second {child=true delta_ms=1.5}", "third {delta_ms=20}"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected log output to contain %q, got: %s", expected, output)
+		}
+	}
+}
+
+func TestIncludeDeltaPerContext(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{
+		Output:       &buf,
+		IncludeDelta: true,
+		DeltaScope:   logger.DeltaPerContext,
+		Clock:        func() time.Time { return now },
+	})
+
+	log.Info("root")
+	now = now.Add(time.Second)
+	requestLog := log.WithContext(logger.WithRequestID(context.Background(), "req-1"))
+	requestLog.Info("request start")
+	now = now.Add(5 * time.Millisecond)
+	requestLog.Info("request end")
+
+	output := buf.String()
+	for _, expected := range []string{"request start {request_id=req-1 delta_ms=0}", "request end {request_id=req-1 delta_ms=5}"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected log output to contain %q, got: %s", expected, output)
+		}
+	}
+}
+
+func TestWithoutDelta(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+	log.Info("no delta")
+
+	if strings.Contains(buf.String(), "delta_ms") {
+		t.Errorf("Expected no delta_ms field by default, got: %s", buf.String())
+	}
+}
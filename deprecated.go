@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// deprecatedFeatures maps each reported feature to a *int64 counting the
+// calls suppressed since they were last reported
+var deprecatedFeatures sync.Map
+
+// Deprecated warns once per process that feature is deprecated and will
+// be removed in removalVersion. It is meant for libraries built on this
+// package: call it from the deprecated code path and the entry points at
+// the library user's code through a caller field.
+//
+// Only the first call for each feature writes an entry, tagged
+// deprecated=true with feature and removal fields. Later calls are counted
+// and the count is reported as suppressed_deprecations on the next entry
+// for a different feature, or by FlushDeprecations. It is safe for
+// concurrent use.
+func Deprecated(l LevelLogger, feature, removalVersion string, fields ...Field) {
+	counter := new(int64)
+	if existing, loaded := deprecatedFeatures.LoadOrStore(feature, counter); loaded {
+		atomic.AddInt64(existing.(*int64), 1)
+		return
+	}
+
+	entry := make([]Field, 0, len(fields)+5)
+	entry = append(entry,
+		Field{Key: "deprecated", Value: true},
+		Field{Key: "feature", Value: feature},
+		Field{Key: "removal", Value: removalVersion},
+	)
+	// Skip Deprecated and the library function calling it
+	if _, file, line, ok := runtime.Caller(2); ok {
+		entry = append(entry, Field{Key: "caller", Value: shortCaller(file, line)})
+	}
+	if n := takeSuppressed(); n > 0 {
+		entry = append(entry, Field{Key: "suppressed_deprecations", Value: n})
+	}
+	entry = append(entry, fields...)
+
+	l.Warn(fmt.Sprintf("%s is deprecated and will be removed in %s", feature, removalVersion), entry...)
+}
+
+// FlushDeprecations writes one entry per deprecated feature that was used
+// again since it was reported, with the number of suppressed calls. Call
+// it before the process exits.
+func FlushDeprecations(l LevelLogger) {
+	type pending struct {
+		feature string
+		count   int64
+	}
+
+	var features []pending
+	deprecatedFeatures.Range(func(key, value any) bool {
+		if n := atomic.SwapInt64(value.(*int64), 0); n > 0 {
+			features = append(features, pending{feature: key.(string), count: n})
+		}
+		return true
+	})
+
+	sort.Slice(features, func(i, j int) bool {
+		return features[i].feature < features[j].feature
+	})
+	for _, p := range features {
+		l.Warn(fmt.Sprintf("%s is deprecated", p.feature),
+			Field{Key: "deprecated", Value: true},
+			Field{Key: "feature", Value: p.feature},
+			Field{Key: "suppressed_deprecations", Value: p.count},
+		)
+	}
+}
+
+// takeSuppressed returns the number of calls suppressed across all
+// features and resets their counters
+func takeSuppressed() int64 {
+	var total int64
+	deprecatedFeatures.Range(func(_, value any) bool {
+		total += atomic.SwapInt64(value.(*int64), 0)
+		return true
+	})
+	return total
+}
+
+// shortCaller formats a source location as dir/file.go:line
+func shortCaller(file string, line int) string {
+	return fmt.Sprintf("%s/%s:%d", filepath.Base(filepath.Dir(file)), filepath.Base(file), line)
+}
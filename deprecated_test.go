@@ -0,0 +1,77 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+// oldOption stands in for a library function that has been deprecated
+func oldOption(l logger.Logger, feature string) {
+	logger.Deprecated(l, feature, "v2.0.0", logger.Field{Key: "replacement", Value: "NewOption"})
+}
+
+func TestDeprecatedOncePerFeature(t *testing.T) {
+	var buf syncBuffer
+	log := logger.New(logger.Config{Output: &buf})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			oldOption(log, "TestDeprecatedOncePerFeature")
+		}()
+	}
+	wg.Wait()
+
+	output := buf.String()
+	if count := strings.Count(output, "feature=TestDeprecatedOncePerFeature"); count != 1 {
+		t.Fatalf("Expected exactly 1 deprecation entry, got %d: %s", count, output)
+	}
+	for _, expected := range []string{
+		"[WARN] TestDeprecatedOncePerFeature is deprecated and will be removed in v2.0.0",
+		"deprecated=true",
+		"removal=v2.0.0",
+		"/deprecated_test.go:",
+		"replacement=NewOption",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected log output to contain %q, got: %s", expected, output)
+		}
+	}
+}
+
+func TestDeprecatedSuppressedCount(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+
+	logger.FlushDeprecations(logger.New(logger.Config{Output: &bytes.Buffer{}}))
+	for i := 0; i < 3; i++ {
+		oldOption(log, "TestDeprecatedSuppressedCount/first")
+	}
+	oldOption(log, "TestDeprecatedSuppressedCount/second")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 entries, got %d: %s", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[1], "suppressed_deprecations=2") {
+		t.Errorf("Expected second entry to report 2 suppressed calls, got: %s", lines[1])
+	}
+
+	buf.Reset()
+	oldOption(log, "TestDeprecatedSuppressedCount/second")
+	logger.FlushDeprecations(log)
+
+	expected := "feature=TestDeprecatedSuppressedCount/second suppressed_deprecations=1"
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("Expected flush to contain %q, got: %s", expected, buf.String())
+	}
+	if strings.Contains(buf.String(), "feature=TestDeprecatedSuppressedCount/first ") {
+		t.Errorf("Expected already reported counts not to be flushed again, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,180 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// LoggerDescription reports the effective configuration of a logger. It
+// is meant for answering what a running service is logging and can be
+// serialized to JSON. Level is the minimum level entries are checked
+// against, including a WithMinLevel override, which is also reported on
+// its own as LevelOverride; an active temporary floor is in Floor. Field values are never included, only their keys,
+// and credentials in output locations are masked.
+type LoggerDescription struct {
+	Name          string              `json:"name,omitempty"`
+	Level         string              `json:"level,omitempty"`
+	LevelOverride string              `json:"level_override,omitempty"`
+	Format        string              `json:"format,omitempty"`
+	FormatVersion int                 `json:"format_version,omitempty"`
+	Color         bool                `json:"color,omitempty"`
+	TimeFormat    string              `json:"time_format,omitempty"`
+	Output        string              `json:"output,omitempty"`
+	Destination   string              `json:"destination,omitempty"`
+	Fields        []string            `json:"fields,omitempty"`
+	Enrichments   []string            `json:"enrichments,omitempty"`
+	ContextFields []string            `json:"context_fields,omitempty"`
+	Health        *SinkHealth         `json:"health,omitempty"`
+	Floor         *FloorDescription   `json:"temporary_floor,omitempty"`
+	Children      []LoggerDescription `json:"children,omitempty"`
+}
+
+// Describer is implemented by loggers that can report their effective
+// configuration
+type Describer interface {
+	Describe() LoggerDescription
+}
+
+// Describe returns the description of l, or an empty description if l
+// does not implement Describer
+func Describe(l Logger) LoggerDescription {
+	if d, ok := l.(Describer); ok {
+		return d.Describe()
+	}
+	return LoggerDescription{}
+}
+
+func (l *standardLogger) Describe() LoggerDescription {
+	health := l.Health()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	d := LoggerDescription{
+		Name:          l.name,
+		Health:        &health,
+		Floor:         l.describeFloor(),
+		Level:         l.minLevel().String(),
+		Format:        l.format.String(),
+		FormatVersion: FormatVersion,
+		Color:         atomic.LoadInt32(&l.out.colored) != 0,
+		TimeFormat:    l.timeFormat,
+		Output:        health.Name,
+		Destination:   l.dest.String(),
+	}
+	if l.override != nil {
+		d.LevelOverride = l.override.String()
+	}
+	for _, group := range [][]Field{l.fields, l.ctxFields} {
+		for _, field := range group {
+			d.Fields = append(d.Fields, field.Key)
+		}
+	}
+	if l.pretty {
+		d.Enrichments = append(d.Enrichments, "pretty_json")
+	}
+	if l.sortFields {
+		d.Enrichments = append(d.Enrichments, "sort_fields")
+	}
+	if l.spanTimes {
+		d.Enrichments = append(d.Enrichments, "span_timestamps")
+	}
+	if l.addCaller {
+		d.Enrichments = append(d.Enrichments, "caller")
+	}
+	if l.addStack {
+		d.Enrichments = append(d.Enrichments, fmt.Sprintf("stack>=%s", l.stackLevel))
+	}
+	if l.delta != nil {
+		d.Enrichments = append(d.Enrichments, "delta_ms")
+	}
+	for _, cf := range l.contextExtractors() {
+		if !l.excluded[cf.name] {
+			d.ContextFields = append(d.ContextFields, cf.name)
+		}
+	}
+	for _, rule := range l.enrichers {
+		d.Enrichments = append(d.Enrichments, fmt.Sprintf("%s>=%s", enricherName(rule.Enricher), rule.Level))
+	}
+	return d
+}
+
+func (m *multiLogger) Describe() LoggerDescription {
+	d := LoggerDescription{Format: "multi"}
+	for _, logger := range m.loggers {
+		d.Children = append(d.Children, Describe(logger))
+	}
+	return d
+}
+
+// String returns the name of the destination kind
+func (d Destination) String() string {
+	switch d {
+	case DestinationAny:
+		return "any"
+	case DestinationConsole:
+		return "console"
+	case DestinationFile:
+		return "file"
+	default:
+		return "unknown"
+	}
+}
+
+// describeOutput names an output writer, masking credentials
+func describeOutput(w io.Writer) string {
+	switch w {
+	case os.Stdout:
+		return "stdout"
+	case os.Stderr:
+		return "stderr"
+	}
+	if f, ok := w.(File); ok {
+		return maskSecrets(f.Name())
+	}
+	return fmt.Sprintf("%T", w)
+}
+
+// maskSecrets hides passwords and query values in URL-like locations
+func maskSecrets(location string) string {
+	u, err := url.Parse(location)
+	if err != nil || u.Scheme == "" {
+		return location
+	}
+	if u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), "xxxxx")
+		}
+	}
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			query.Set(key, "xxxxx")
+		}
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
+
+// logStartupSummary writes the effective configuration as a single entry.
+// It bypasses the level check so the summary explains missing entries.
+func (l *standardLogger) logStartupSummary() {
+	d := l.Describe()
+	fields := []Field{
+		{Key: "logger_startup", Value: true},
+		{Key: "level", Value: d.Level},
+		{Key: "format", Value: d.Format},
+		{Key: "format_version", Value: d.FormatVersion},
+		{Key: "output", Value: d.Output},
+		{Key: "destination", Value: d.Destination},
+		{Key: "time_format", Value: d.TimeFormat},
+	}
+	if len(d.Enrichments) > 0 {
+		fields = append(fields, Field{Key: "enrichments", Value: strings.Join(d.Enrichments, ",")})
+	}
+	l.write(InfoLevel, "logger started", fields)
+}
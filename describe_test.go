@@ -0,0 +1,91 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestDescribe(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{
+		Level:        logger.WarnLevel,
+		Output:       &buf,
+		Destination:  logger.DestinationConsole,
+		IncludeDelta: true,
+	}).With(logger.Field{Key: "service", Value: "api"}).
+		WithContext(logger.WithRequestID(context.Background(), "req-1"))
+
+	d := logger.Describe(log)
+
+	if d.Level != "WARN" || d.Format != "text" || d.Destination != "console" || d.Output != "*bytes.Buffer" {
+		t.Errorf("Unexpected description: %+v", d)
+	}
+	if !reflect.DeepEqual(d.Fields, []string{"service", "request_id"}) {
+		t.Errorf("Expected field keys, got: %v", d.Fields)
+	}
+	if !reflect.DeepEqual(d.Enrichments, []string{"delta_ms"}) {
+		t.Errorf("Expected delta_ms enrichment, got: %v", d.Enrichments)
+	}
+}
+
+func TestDescribeMultiLogger(t *testing.T) {
+	log := logger.MultiLogger(
+		logger.New(logger.Config{Level: logger.DebugLevel, Output: &bytes.Buffer{}}),
+		logger.New(logger.Config{Level: logger.ErrorLevel, Output: &bytes.Buffer{}}),
+	)
+
+	d := logger.Describe(log)
+
+	if len(d.Children) != 2 || d.Children[0].Level != "DEBUG" || d.Children[1].Level != "ERROR" {
+		t.Errorf("Expected children descriptions, got: %+v", d)
+	}
+}
+
+func TestLogStartupSummary(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{
+		Level:             logger.ErrorLevel,
+		Output:            &buf,
+		IncludeDelta:      true,
+		LogStartupSummary: true,
+	})
+
+	output := buf.String()
+	if strings.Count(output, "logger_startup=true") != 1 {
+		t.Fatalf("Expected exactly one startup entry, got: %s", output)
+	}
+	for _, expected := range []string{"[INFO] logger started", "level=ERROR", "format=text", "format_version=1", "output=*bytes.Buffer", "enrichments=delta_ms"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected startup entry to contain %q, got: %s", expected, output)
+		}
+	}
+
+	log.With(logger.Field{Key: "child", Value: true}).WithContext(context.Background())
+	if strings.Count(buf.String(), "logger_startup=true") != 1 {
+		t.Errorf("Expected derived loggers not to log a startup entry, got: %s", buf.String())
+	}
+}
+
+func TestDescribeReportsEffectiveLevel(t *testing.T) {
+	log := logger.New(logger.Config{Level: logger.WarnLevel, Output: &bytes.Buffer{}})
+
+	lowered := logger.Describe(log.WithContext(logger.WithMinLevel(context.Background(), logger.DebugLevel)))
+	if lowered.Level != "DEBUG" || lowered.LevelOverride != "DEBUG" {
+		t.Errorf("Expected the override to lower the effective level, got level=%s override=%s", lowered.Level, lowered.LevelOverride)
+	}
+
+	// Without AllowLevelRaise an override cannot raise the level
+	raised := logger.Describe(log.WithContext(logger.WithMinLevel(context.Background(), logger.ErrorLevel)))
+	if raised.Level != "WARN" || raised.LevelOverride != "ERROR" {
+		t.Errorf("Expected the configured level with the ignored override shown, got level=%s override=%s", raised.Level, raised.LevelOverride)
+	}
+
+	if d := logger.Describe(log); d.LevelOverride != "" {
+		t.Errorf("Expected no override, got %s", d.LevelOverride)
+	}
+}
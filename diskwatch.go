@@ -0,0 +1,279 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultDiskCheckInterval is how often a DiskWatchdog checks free space
+// when DiskWatchdogConfig.Interval is not set
+const DefaultDiskCheckInterval = 10 * time.Second
+
+// DiskStage is how far a DiskWatchdog has degraded logging
+type DiskStage int32
+
+const (
+	// DiskNormal writes every entry
+	DiskNormal DiskStage = iota
+	// DiskFloor drops Debug entries
+	DiskFloor
+	// DiskQuiet drops Debug and Info entries
+	DiskQuiet
+	// DiskPaused drops everything but Fatal entries
+	DiskPaused
+)
+
+func (s DiskStage) String() string {
+	switch s {
+	case DiskNormal:
+		return "normal"
+	case DiskFloor:
+		return "floor"
+	case DiskQuiet:
+		return "quiet"
+	case DiskPaused:
+		return "paused"
+	default:
+		return "unknown"
+	}
+}
+
+// allows reports whether entries at level are written in the stage
+func (s DiskStage) allows(level Level) bool {
+	switch s {
+	case DiskFloor:
+		return level >= InfoLevel
+	case DiskQuiet:
+		return level >= WarnLevel
+	case DiskPaused:
+		return level == FatalLevel
+	}
+	return true
+}
+
+// DiskSpace is the space of a filesystem as reported by a StatfsFunc
+type DiskSpace struct {
+	Free  uint64 // bytes available to the process
+	Total uint64
+}
+
+// FreePercent returns Free as a percentage of Total
+func (s DiskSpace) FreePercent() float64 {
+	if s.Total == 0 {
+		return 100
+	}
+	return float64(s.Free) / float64(s.Total) * 100
+}
+
+// StatfsFunc reports the space of the filesystem holding path
+type StatfsFunc func(path string) (DiskSpace, error)
+
+// DiskThreshold is reached when free space falls below Bytes or below
+// Percent of the filesystem. A zero field is not checked.
+type DiskThreshold struct {
+	Bytes   uint64
+	Percent float64
+}
+
+func (t DiskThreshold) reached(space DiskSpace) bool {
+	return (t.Bytes > 0 && space.Free < t.Bytes) || (t.Percent > 0 && space.FreePercent() < t.Percent)
+}
+
+// DefaultDiskThresholds are used when no DiskWatchdogConfig threshold is
+// set: Floor, Quiet and Pause, in that order
+var DefaultDiskThresholds = [3]DiskThreshold{
+	{Bytes: 1 << 30, Percent: 10},
+	{Bytes: 512 << 20, Percent: 5},
+	{Bytes: 100 << 20, Percent: 2},
+}
+
+// DiskWatchdogConfig configures a DiskWatchdog
+type DiskWatchdogConfig struct {
+	// Path is the log file, or any path on the filesystem it lives on
+	Path string
+
+	// Floor, Quiet and Pause are the thresholds for entering DiskFloor,
+	// DiskQuiet and DiskPaused. When none is set, DefaultDiskThresholds
+	// are used.
+	Floor DiskThreshold
+	Quiet DiskThreshold
+	Pause DiskThreshold
+
+	// Interval is the time between checks. It defaults to
+	// DefaultDiskCheckInterval.
+	Interval time.Duration
+
+	// Statfs reads the free space. It defaults to the operating system's
+	// statfs and can be replaced in tests.
+	Statfs StatfsFunc
+
+	// OnTransition, when set, is called after every stage change, for
+	// example to delete old logs or page someone
+	OnTransition func(from, to DiskStage, space DiskSpace)
+}
+
+// DiskWatchdog degrades logging to a file as its filesystem fills up, so
+// that logs do not take the volume and everything on it down with them.
+// Set it as Config.DiskWatchdog of the file logger; in a MultiLogger the
+// other outputs are unaffected. Several loggers may share one.
+//
+// Free space is checked when the first logger using the watchdog is
+// created and then once per interval on a goroutine owned by the
+// watchdog, which stops when every such logger has been closed. Logging
+// only reads the last result. Below the Floor threshold Debug entries are
+// dropped, below Quiet Info entries too, and below Pause everything but
+// Fatal. Each transition, including recovery once space is freed, is
+// written to the output of every logger using the watchdog as a
+// meta-entry.
+type DiskWatchdog struct {
+	path       string
+	thresholds [3]DiskThreshold
+	interval   time.Duration
+	statfs     StatfsFunc
+	onChange   func(from, to DiskStage, space DiskSpace)
+
+	stage int32
+
+	mu      sync.Mutex // serializes checks and guards the fields below
+	failing bool
+	loggers map[*outputState]*standardLogger
+	stop    chan struct{}
+	tasks   backgroundGroup
+}
+
+// NewDiskWatchdog returns a watchdog for cfg
+func NewDiskWatchdog(cfg DiskWatchdogConfig) *DiskWatchdog {
+	thresholds := [3]DiskThreshold{cfg.Floor, cfg.Quiet, cfg.Pause}
+	if thresholds == ([3]DiskThreshold{}) {
+		thresholds = DefaultDiskThresholds
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultDiskCheckInterval
+	}
+	if cfg.Statfs == nil {
+		cfg.Statfs = statfs
+	}
+	return &DiskWatchdog{
+		path:       cfg.Path,
+		thresholds: thresholds,
+		interval:   cfg.Interval,
+		statfs:     cfg.Statfs,
+		onChange:   cfg.OnTransition,
+		loggers:    make(map[*outputState]*standardLogger),
+	}
+}
+
+// Stage returns the current stage
+func (w *DiskWatchdog) Stage() DiskStage {
+	return DiskStage(atomic.LoadInt32(&w.stage))
+}
+
+// Check reads the free space now instead of at the next interval, for
+// example right after old logs were deleted
+func (w *DiskWatchdog) Check() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.check()
+}
+
+// attach makes l's output subject to the watchdog. The first logger
+// attached checks the free space and starts the refresh goroutine.
+func (w *DiskWatchdog) attach(l *standardLogger) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.loggers[l.out] = l
+	if w.stop != nil {
+		return
+	}
+	w.check()
+
+	stop := make(chan struct{})
+	w.stop = stop
+	w.tasks.Go(func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				w.Check()
+			case <-stop:
+				return
+			}
+		}
+	})
+}
+
+// detach releases the output of a closed logger. When no output is left
+// the refresh goroutine is stopped and waited for.
+func (w *DiskWatchdog) detach(out *outputState) {
+	w.mu.Lock()
+	delete(w.loggers, out)
+	stop := w.stop
+	if len(w.loggers) > 0 || stop == nil {
+		w.mu.Unlock()
+		return
+	}
+	w.stop = nil
+	w.mu.Unlock()
+
+	close(stop)
+	w.tasks.Wait()
+}
+
+// check reads the free space, updates the stage and writes a transition
+// to every attached output. w.mu must be held.
+func (w *DiskWatchdog) check() {
+	space, err := w.statfs(w.path)
+	if err != nil {
+		// Keep the current stage; report the first of a run of failures
+		if !w.failing {
+			w.failing = true
+			Emergency().Warn("disk watchdog cannot read free space",
+				Field{Key: "path", Value: w.path},
+				Field{Key: "error", Value: err},
+			)
+		}
+		return
+	}
+	w.failing = false
+
+	stage := DiskNormal
+	for i := len(w.thresholds) - 1; i >= 0; i-- {
+		if w.thresholds[i].reached(space) {
+			stage = DiskStage(i + 1)
+			break
+		}
+	}
+	from := DiskStage(atomic.SwapInt32(&w.stage, int32(stage)))
+	if from == stage {
+		return
+	}
+	if w.onChange != nil {
+		w.onChange(from, stage, space)
+	}
+	for _, l := range w.loggers {
+		l.logDiskTransition(from, stage, space)
+	}
+}
+
+func (l *standardLogger) logDiskTransition(from, to DiskStage, space DiskSpace) {
+	fields := []Field{
+		{Key: "disk_stage", Value: to.String()},
+		{Key: "previous_stage", Value: from.String()},
+		{Key: "path", Value: l.disk.path},
+		{Key: "free_bytes", Value: space.Free},
+		{Key: "free_percent", Value: fmt.Sprintf("%.1f", space.FreePercent())},
+	}
+
+	level, msg := WarnLevel, "disk space low: degrading log output"
+	if to < from {
+		level, msg = InfoLevel, "disk space recovered: restoring log output"
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.writeEntry(level, msg, fields, l.clock())
+}
@@ -0,0 +1,11 @@
+//go:build !linux && !darwin && !freebsd
+
+package logger
+
+import "errors"
+
+// statfs is not implemented on this platform; set
+// DiskWatchdogConfig.Statfs instead
+func statfs(path string) (DiskSpace, error) {
+	return DiskSpace{}, errors.New("free space is not available on this platform")
+}
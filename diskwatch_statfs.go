@@ -0,0 +1,17 @@
+//go:build linux || darwin || freebsd
+
+package logger
+
+import "syscall"
+
+// statfs reports the space of the filesystem holding path
+func statfs(path string) (DiskSpace, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return DiskSpace{}, err
+	}
+	return DiskSpace{
+		Free:  uint64(st.Bavail) * uint64(st.Bsize),
+		Total: uint64(st.Blocks) * uint64(st.Bsize),
+	}, nil
+}
@@ -0,0 +1,230 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+// fakeDisk is a filesystem whose free space the test controls
+type fakeDisk struct {
+	mu    sync.Mutex
+	space logger.DiskSpace
+	err   error
+}
+
+func (d *fakeDisk) set(free uint64, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.space = logger.DiskSpace{Free: free, Total: 1000}
+	d.err = err
+}
+
+func (d *fakeDisk) statfs(path string) (logger.DiskSpace, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.space, d.err
+}
+
+func TestDiskWatchdogTransitions(t *testing.T) {
+	disk := &fakeDisk{}
+	disk.set(500, nil)
+
+	type transition struct{ from, to logger.DiskStage }
+	var transitions []transition
+	watchdog := logger.NewDiskWatchdog(logger.DiskWatchdogConfig{
+		Path:     "/var/log/app.log",
+		Floor:    logger.DiskThreshold{Percent: 30},
+		Quiet:    logger.DiskThreshold{Bytes: 200},
+		Pause:    logger.DiskThreshold{Bytes: 100, Percent: 5},
+		Interval: time.Hour,
+		Statfs:   disk.statfs,
+		OnTransition: func(from, to logger.DiskStage, space logger.DiskSpace) {
+			transitions = append(transitions, transition{from, to})
+		},
+	})
+
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, DiskWatchdog: watchdog})
+	defer log.Close()
+	logAll := func() string {
+		log.Debug("debug entry")
+		log.Info("info entry")
+		log.Warn("warn entry")
+		log.Error("error entry")
+		return buf.String()
+	}
+
+	tests := []struct {
+		name     string
+		free     uint64
+		stage    logger.DiskStage
+		meta     string
+		written  []string
+		filtered []string
+	}{
+		{name: "plenty of space", free: 500, stage: logger.DiskNormal,
+			written: []string{"debug entry", "info entry", "warn entry", "error entry"}},
+		{name: "below floor", free: 250, stage: logger.DiskFloor,
+			meta:     "[WARN] disk space low: degrading log output {disk_stage=floor previous_stage=normal path=/var/log/app.log free_bytes=250 free_percent=25.0}",
+			written:  []string{"info entry", "warn entry", "error entry"},
+			filtered: []string{"debug entry"}},
+		{name: "below quiet", free: 150, stage: logger.DiskQuiet,
+			meta:     "disk_stage=quiet previous_stage=floor",
+			written:  []string{"warn entry", "error entry"},
+			filtered: []string{"debug entry", "info entry"}},
+		{name: "below pause", free: 40, stage: logger.DiskPaused,
+			meta:     "disk_stage=paused previous_stage=quiet",
+			filtered: []string{"debug entry", "info entry", "warn entry", "error entry"}},
+		{name: "space freed", free: 900, stage: logger.DiskNormal,
+			meta:    "[INFO] disk space recovered: restoring log output {disk_stage=normal previous_stage=paused",
+			written: []string{"debug entry", "info entry", "warn entry", "error entry"}},
+	}
+
+	for _, tt := range tests {
+		buf.Reset()
+		disk.set(tt.free, nil)
+		watchdog.Check()
+		output := logAll()
+
+		if watchdog.Stage() != tt.stage {
+			t.Errorf("%s: expected stage %s, got %s", tt.name, tt.stage, watchdog.Stage())
+		}
+		if tt.meta != "" && !strings.Contains(output, tt.meta) {
+			t.Errorf("%s: expected meta-entry %q, got: %s", tt.name, tt.meta, output)
+		}
+		for _, entry := range tt.written {
+			if !strings.Contains(output, entry) {
+				t.Errorf("%s: expected %q to be written, got: %s", tt.name, entry, output)
+			}
+		}
+		for _, entry := range tt.filtered {
+			if strings.Contains(output, entry) {
+				t.Errorf("%s: expected %q to be dropped, got: %s", tt.name, entry, output)
+			}
+		}
+	}
+
+	expected := []transition{
+		{logger.DiskNormal, logger.DiskFloor},
+		{logger.DiskFloor, logger.DiskQuiet},
+		{logger.DiskQuiet, logger.DiskPaused},
+		{logger.DiskPaused, logger.DiskNormal},
+	}
+	if len(transitions) != len(expected) {
+		t.Fatalf("Expected transitions %v, got %v", expected, transitions)
+	}
+	for i := range expected {
+		if transitions[i] != expected[i] {
+			t.Errorf("Expected transition %d to be %v, got %v", i, expected[i], transitions[i])
+		}
+	}
+}
+
+func TestDiskWatchdogOnlyDegradesItsOutput(t *testing.T) {
+	disk := &fakeDisk{}
+	disk.set(10, nil)
+	watchdog := logger.NewDiskWatchdog(logger.DiskWatchdogConfig{Statfs: disk.statfs})
+
+	var console, file bytes.Buffer
+	log := logger.MultiLogger(
+		logger.New(logger.Config{Output: &console}),
+		logger.New(logger.Config{Output: &file, DiskWatchdog: watchdog}),
+	)
+	defer log.Close()
+
+	log.Error("still on console")
+
+	if !strings.Contains(console.String(), "still on console") {
+		t.Errorf("Expected the console to keep writing, got: %s", console.String())
+	}
+	if strings.Contains(file.String(), "still on console") || !strings.Contains(file.String(), "disk_stage=paused") {
+		t.Errorf("Expected the file output to be paused, got: %s", file.String())
+	}
+}
+
+func TestDiskWatchdogLoggingDoesNotCheck(t *testing.T) {
+	var calls int32
+	watchdog := logger.NewDiskWatchdog(logger.DiskWatchdogConfig{
+		Interval: time.Hour,
+		Statfs: func(string) (logger.DiskSpace, error) {
+			atomic.AddInt32(&calls, 1)
+			return logger.DiskSpace{Free: 900, Total: 1000}, nil
+		},
+	})
+	log := logger.New(logger.Config{Output: &bytes.Buffer{}, DiskWatchdog: watchdog})
+	defer log.Close()
+
+	for i := 0; i < 20; i++ {
+		log.Enabled(logger.DebugLevel)
+		log.Info("entry")
+	}
+
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Expected only the check on creation, got %d", n)
+	}
+}
+
+func TestDiskWatchdogChecksInBackground(t *testing.T) {
+	disk := &fakeDisk{}
+	disk.set(900, nil)
+	watchdog := logger.NewDiskWatchdog(logger.DiskWatchdogConfig{Interval: time.Millisecond, Statfs: disk.statfs})
+
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, DiskWatchdog: watchdog})
+	disk.set(10, nil)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for watchdog.Stage() != logger.DiskPaused {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the watchdog to notice the full disk without logging")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	log.Close()
+	requireNoBackgroundTasks(t)
+	if !strings.Contains(buf.String(), "disk_stage=paused") {
+		t.Errorf("Expected the transition to be written, got: %s", buf.String())
+	}
+}
+
+func TestDiskWatchdogStatfsFailure(t *testing.T) {
+	emergency := captureEmergency(t)
+	disk := &fakeDisk{}
+	disk.set(10, nil)
+	watchdog := logger.NewDiskWatchdog(logger.DiskWatchdogConfig{Path: "/logs", Interval: time.Hour, Statfs: disk.statfs})
+	log := logger.New(logger.Config{Output: &bytes.Buffer{}, DiskWatchdog: watchdog})
+	defer log.Close()
+
+	disk.set(0, errors.New("stale file handle"))
+	watchdog.Check()
+	watchdog.Check()
+
+	if watchdog.Stage() != logger.DiskPaused {
+		t.Errorf("Expected the stage to be kept while free space is unknown, got %s", watchdog.Stage())
+	}
+	if strings.Count(emergency.String(), "disk watchdog cannot read free space") != 1 {
+		t.Errorf("Expected one report for a run of failures, got: %s", emergency.String())
+	}
+}
+
+func TestDiskWatchdogDefaultStatfs(t *testing.T) {
+	emergency := captureEmergency(t)
+	watchdog := logger.NewDiskWatchdog(logger.DiskWatchdogConfig{Path: os.TempDir()})
+	log := logger.New(logger.Config{Output: &bytes.Buffer{}, DiskWatchdog: watchdog})
+	defer log.Close()
+
+	log.Info("entry")
+
+	if strings.Contains(emergency.String(), "cannot read free space") {
+		t.Errorf("Expected the real filesystem to be readable, got: %s", emergency.String())
+	}
+}
@@ -0,0 +1,115 @@
+package logger
+
+import "strconv"
+
+// DuplicateKeyPolicy controls which value is written when an entry has
+// the same key more than once, for example a field added with With and
+// passed again at the call site. Base, context, pushed and call-site
+// fields are resolved together, along with fields added by options such
+// as AddCaller; fields inside groups are not affected. A context field
+// added with WithContext already replaces a base field with the same key.
+type DuplicateKeyPolicy int
+
+const (
+	// DuplicateLastWins keeps the value added last, in the position of
+	// the first occurrence, so a call-site field overrides a base field
+	// with the same key. It is the default.
+	DuplicateLastWins DuplicateKeyPolicy = iota
+	// DuplicateFirstWins keeps the value added first and drops the others
+	DuplicateFirstWins
+	// DuplicateSuffix keeps every value, renaming the second occurrence
+	// of user_id to user_id_2, the third to user_id_3 and so on
+	DuplicateSuffix
+)
+
+// String returns the name of the policy
+func (p DuplicateKeyPolicy) String() string {
+	switch p {
+	case DuplicateLastWins:
+		return "last_wins"
+	case DuplicateFirstWins:
+		return "first_wins"
+	case DuplicateSuffix:
+		return "suffix"
+	default:
+		return "unknown"
+	}
+}
+
+// resolveDuplicates applies policy to fields, which was built for the
+// entry and may be changed in place. The common case without duplicate
+// keys returns fields untouched.
+func resolveDuplicates(fields []Field, policy DuplicateKeyPolicy) []Field {
+	if !hasDuplicateKeys(fields) {
+		return fields
+	}
+
+	if policy == DuplicateSuffix {
+		counts := make(map[string]int, len(fields))
+		seen := make(map[string]bool, len(fields))
+		for _, field := range fields {
+			seen[field.Key] = true
+		}
+		for i, field := range fields {
+			count, dup := counts[field.Key]
+			if !dup {
+				counts[field.Key] = 1
+				continue
+			}
+			// Skip suffixes taken by other fields, such as a user_id_2
+			// passed alongside two user_id fields
+			key := field.Key
+			for {
+				count++
+				key = field.Key + "_" + strconv.Itoa(count)
+				if !seen[key] {
+					break
+				}
+			}
+			counts[field.Key] = count
+			seen[key] = true
+			fields[i].Key = key
+		}
+		return fields
+	}
+
+	first := make(map[string]int, len(fields))
+	kept := fields[:0]
+	for _, field := range fields {
+		if at, dup := first[field.Key]; dup {
+			if policy == DuplicateLastWins {
+				kept[at].Value = field.Value
+			}
+			continue
+		}
+		first[field.Key] = len(kept)
+		kept = append(kept, field)
+	}
+	return kept
+}
+
+// hasDuplicateKeys reports whether any key appears twice in fields
+func hasDuplicateKeys(fields []Field) bool {
+	if len(fields) < 2 {
+		return false
+	}
+	// Entries rarely have enough fields for a map to pay off
+	if len(fields) <= 16 {
+		for i := 1; i < len(fields); i++ {
+			for j := 0; j < i; j++ {
+				if fields[i].Key == fields[j].Key {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	seen := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if seen[field.Key] {
+			return true
+		}
+		seen[field.Key] = true
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+// WithContext replaces the base user_id in place before any policy
+// applies, so the context value stands for both
+func TestDuplicateKeyPolicies(t *testing.T) {
+	ctx := logger.WithUserID(context.Background(), "ctx")
+
+	tests := []struct {
+		policy   logger.DuplicateKeyPolicy
+		expected string
+	}{
+		{policy: logger.DuplicateLastWins, expected: "{user_id=call service=api}"},
+		{policy: logger.DuplicateFirstWins, expected: "{user_id=ctx service=api}"},
+		{policy: logger.DuplicateSuffix, expected: "{user_id=ctx service=api user_id_2=call}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			log := logger.New(logger.Config{Output: &buf, DuplicateKeys: tt.policy}).
+				With(logger.Field{Key: "user_id", Value: "base"}, logger.Field{Key: "service", Value: "api"}).
+				WithContext(ctx)
+
+			log.Info("request", logger.Field{Key: "user_id", Value: "call"})
+
+			if !strings.Contains(buf.String(), "request "+tt.expected) {
+				t.Errorf("Expected %s, got: %s", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestDuplicateSuffixSkipsTakenKeys(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatJSON, DuplicateKeys: logger.DuplicateSuffix})
+
+	log.Info("request",
+		logger.Field{Key: "id", Value: 1},
+		logger.Field{Key: "id_2", Value: 2},
+		logger.Field{Key: "id", Value: 3},
+	)
+
+	entry := decodeEntry(t, buf.String())
+	if entry["id"] != float64(1) || entry["id_2"] != float64(2) || entry["id_3"] != float64(3) {
+		t.Errorf("Expected id, id_2 and id_3 to keep their values, got %v", entry)
+	}
+}
+
+func TestDuplicateKeysInMultiLogger(t *testing.T) {
+	var text, json bytes.Buffer
+	log := logger.MultiLogger(
+		logger.New(logger.Config{Output: &text}),
+		logger.New(logger.Config{Output: &json, Format: logger.FormatJSON}),
+	).With(logger.Field{Key: "user_id", Value: "base"})
+
+	log.Info("request", logger.Field{Key: "user_id", Value: "call"})
+
+	if !strings.Contains(text.String(), "request {user_id=call}") {
+		t.Errorf("Expected the text child to keep the call-site value once, got: %s", text.String())
+	}
+	if strings.Count(json.String(), "user_id") != 1 || decodeEntry(t, json.String())["user_id"] != "call" {
+		t.Errorf("Expected the JSON child to keep the call-site value once, got: %s", json.String())
+	}
+}
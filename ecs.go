@@ -0,0 +1,186 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ecsVersion is the Elastic Common Schema version FormatECS follows
+const ecsVersion = "8.11.0"
+
+// ecsFieldNames maps field keys with an ECS counterpart to its name
+var ecsFieldNames = map[string]string{
+	"request_id":  "http.request.id",
+	"user_id":     "user.id",
+	"trace_id":    "trace.id",
+	"span_id":     "span.id",
+	"logger":      "log.logger",
+	"stack":       "error.stack_trace",
+	"error.stack": "error.stack_trace",
+	"code":        "event.code",
+}
+
+// ecsNode is an object or leaf of an ECS document being assembled. Keys
+// keep their insertion order.
+type ecsNode struct {
+	value    []byte // JSON of a leaf, nil for objects
+	keys     []string
+	children map[string]*ecsNode
+}
+
+// set stores value at the dotted path, replacing anything already there.
+// A leaf in the way of a longer path is replaced by an object, so the
+// later field wins a conflict.
+func (n *ecsNode) set(path []string, value []byte) {
+	for _, name := range path {
+		if n.children == nil {
+			n.children = make(map[string]*ecsNode)
+		}
+		child, ok := n.children[name]
+		if !ok {
+			child = &ecsNode{}
+			n.children[name] = child
+			n.keys = append(n.keys, name)
+		}
+		n = child
+		n.value = nil
+	}
+	n.value = value
+	n.keys = nil
+	n.children = nil
+}
+
+// write renders the node as JSON
+func (n *ecsNode) write(b *bytes.Buffer) {
+	if n.value != nil {
+		b.Write(n.value)
+		return
+	}
+	b.WriteString("{")
+	for i, key := range n.keys {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		writeJSONString(b, key)
+		b.WriteString(":")
+		n.children[key].write(b)
+	}
+	b.WriteString("}")
+}
+
+// encodeECS renders an entry as an Elastic Common Schema document. Dotted
+// names are nested into objects. Fields with an ECS counterpart are
+// renamed, error values become error.message and error.type, and other
+// fields are kept as they are or, with Config.ECSLabels, written as
+// strings under labels.
+func (l *standardLogger) encodeECS(now time.Time, level Level, msg string, fields []Field) string {
+	var failures []string
+	doc := &ecsNode{}
+	setString := func(name, value string) {
+		var b bytes.Buffer
+		writeJSONString(&b, value)
+		doc.set(strings.Split(name, "."), b.Bytes())
+	}
+
+	setString("@timestamp", now.UTC().Format(time.RFC3339Nano))
+	setString("log.level", l.encodeLvl(level))
+	setString("message", msg)
+	setString("ecs.version", ecsVersion)
+	l.setECSFields(doc, "", fields, &failures)
+	if len(failures) > 0 {
+		setString(internalErrorKey, strings.Join(failures, "; "))
+		l.formatFailed(failures)
+	}
+
+	var b bytes.Buffer
+	doc.write(&b)
+	return b.String()
+}
+
+// setECSFields adds fields to doc. Grouped fields are expanded with their
+// keys prefixed by the group name.
+func (l *standardLogger) setECSFields(doc *ecsNode, prefix string, fields []Field, failures *[]string) {
+	for _, field := range fields {
+		key := prefix + field.Key
+		if group, ok := field.Value.(groupValue); ok {
+			l.setECSFields(doc, key+".", group, failures)
+			continue
+		}
+
+		if prefix == "" {
+			if l.setECSField(doc, field) {
+				continue
+			}
+		}
+
+		var data []byte
+		var err error
+		path := strings.Split(key, ".")
+		if l.ecsLabels {
+			var s string
+			s, err = formatValue(field.Value)
+			var b bytes.Buffer
+			writeJSONString(&b, s)
+			data = b.Bytes()
+			path = []string{"labels", strings.ReplaceAll(key, ".", "_")}
+		} else {
+			data, err = jsonValue(field.Value)
+		}
+		if err != nil {
+			*failures = append(*failures, fmt.Sprintf("%s: %v", key, err))
+		}
+		doc.set(path, data)
+	}
+}
+
+// setECSField writes a field with an ECS counterpart and reports whether
+// it had one
+func (l *standardLogger) setECSField(doc *ecsNode, field Field) bool {
+	str := func(s string) []byte {
+		var b bytes.Buffer
+		writeJSONString(&b, s)
+		return b.Bytes()
+	}
+
+	switch field.Key {
+	case "error":
+		if err, ok := field.Value.(error); ok {
+			text, _ := formatValue(err)
+			doc.set([]string{"error", "message"}, str(text))
+			doc.set([]string{"error", "type"}, str(fmt.Sprintf("%T", err)))
+			return true
+		}
+		if s, ok := field.Value.(string); ok {
+			doc.set([]string{"error", "message"}, str(s))
+			return true
+		}
+		return false
+	case "caller":
+		s, ok := field.Value.(string)
+		colon := strings.LastIndex(s, ":")
+		if !ok || colon < 0 {
+			return false
+		}
+		line, err := strconv.Atoi(s[colon+1:])
+		if err != nil {
+			return false
+		}
+		doc.set([]string{"log", "origin", "file", "name"}, str(s[:colon]))
+		doc.set([]string{"log", "origin", "file", "line"}, []byte(strconv.Itoa(line)))
+		return true
+	}
+
+	name, ok := ecsFieldNames[field.Key]
+	if !ok {
+		return false
+	}
+	value, err := formatValue(field.Value)
+	if err != nil {
+		return false
+	}
+	doc.set(strings.Split(name, "."), str(value))
+	return true
+}
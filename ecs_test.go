@@ -0,0 +1,113 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestECSFormat(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	log := logger.Named(logger.New(logger.Config{Output: &buf, Format: logger.FormatECS, Clock: fixedClock(now)}), "billing")
+
+	ctx := logger.WithUserID(logger.WithRequestID(context.Background(), "req-1"), "u-1")
+	log.WithContext(ctx).Error("charge failed",
+		logger.Field{Key: "error", Value: errors.New("card declined")},
+		logger.Field{Key: "trace_id", Value: "abc"},
+		logger.Field{Key: "amount", Value: 12.5},
+		logger.Field{Key: "http.response.status_code", Value: 402},
+		logger.Group("gateway", logger.Field{Key: "name", Value: "stripe"}),
+	)
+
+	if strings.Count(buf.String(), "\n") != 1 {
+		t.Fatalf("Expected one document per line, got %q", buf.String())
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Expected a JSON document, got %q: %v", buf.String(), err)
+	}
+
+	expected := map[string]any{
+		"@timestamp": "2024-03-01T12:00:00Z",
+		"message":    "charge failed",
+		"ecs":        map[string]any{"version": "8.11.0"},
+		"log":        map[string]any{"level": "error", "logger": "billing"},
+		"user":       map[string]any{"id": "u-1"},
+		"error":      map[string]any{"message": "card declined", "type": "*errors.errorString"},
+		"trace":      map[string]any{"id": "abc"},
+		"amount":     12.5,
+		"http": map[string]any{
+			"request":  map[string]any{"id": "req-1"},
+			"response": map[string]any{"status_code": float64(402)},
+		},
+		"gateway": map[string]any{"name": "stripe"},
+	}
+	if !reflect.DeepEqual(doc, expected) {
+		got, _ := json.MarshalIndent(doc, "", "  ")
+		t.Errorf("Unexpected ECS document:\n%s", got)
+	}
+}
+
+func TestECSLabels(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatECS, ECSLabels: true, AddCaller: true})
+
+	log.Info("labeled",
+		logger.Field{Key: "tenant", Value: "acme"},
+		logger.Field{Key: "retries", Value: 2},
+		logger.Field{Key: "a.b", Value: true},
+		logger.Field{Key: "user_id", Value: "u-2"},
+	)
+
+	var doc struct {
+		Labels map[string]any `json:"labels"`
+		User   struct {
+			ID string `json:"id"`
+		} `json:"user"`
+		Log struct {
+			Origin struct {
+				File struct {
+					Name string `json:"name"`
+					Line int    `json:"line"`
+				} `json:"file"`
+			} `json:"origin"`
+		} `json:"log"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Expected a JSON document, got %q: %v", buf.String(), err)
+	}
+
+	expected := map[string]any{"tenant": "acme", "retries": "2", "a_b": "true"}
+	if !reflect.DeepEqual(doc.Labels, expected) {
+		t.Errorf("Expected labels %v, got %v", expected, doc.Labels)
+	}
+	if doc.User.ID != "u-2" {
+		t.Errorf("Expected ECS fields to stay out of labels, got: %s", buf.String())
+	}
+	if !strings.HasSuffix(doc.Log.Origin.File.Name, "ecs_test.go") || doc.Log.Origin.File.Line == 0 {
+		t.Errorf("Expected the caller in log.origin.file, got: %s", buf.String())
+	}
+}
+
+func TestECSConflictingKeys(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatECS})
+
+	log.Info("conflict", logger.Field{Key: "db", Value: "primary"}, logger.Field{Key: "db.host", Value: "h1"})
+
+	var doc map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Expected valid JSON despite conflicting keys, got %q: %v", buf.String(), err)
+	}
+	if !reflect.DeepEqual(doc["db"], map[string]any{"host": "h1"}) {
+		t.Errorf("Expected the later field to win, got %v", doc["db"])
+	}
+}
@@ -0,0 +1,47 @@
+package logger
+
+import "os"
+
+// emergency is built when the package is loaded so it exists before any
+// configuration is parsed. It is assigned in init because writing entries
+// may report to it.
+var emergency *standardLogger
+
+func init() {
+	emergency = newEmergencyLogger()
+}
+
+// Emergency returns the last-resort logger. It writes text entries to
+// standard error at every level and exists from the moment the package is
+// loaded: building it cannot fail and touches neither the filesystem nor
+// the environment. Use it in crash paths and during early startup, before
+// a regular logger could be configured.
+//
+// The package reports its own problems, such as the first failure of an
+// output after a run of successful writes, through this logger. Failures
+// of its own output are never reported, so a broken standard error cannot
+// cause recursion.
+func Emergency() Logger {
+	return emergency
+}
+
+func newEmergencyLogger() *standardLogger {
+	l := New(Config{
+		Level:      DebugLevel,
+		Output:     os.Stderr,
+		OutputName: "emergency",
+		Color:      ColorNever,
+	}).(*standardLogger)
+	l.noVolume = true
+	l.out.quiet = true
+	return l
+}
+
+// reportOutputFailure tells the emergency logger that an output stopped
+// accepting writes
+func reportOutputFailure(name string, err error) {
+	emergency.Error("log output failed",
+		Field{Key: "output", Value: name},
+		Field{Key: "error", Value: err},
+	)
+}
@@ -0,0 +1,114 @@
+package logger_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+// captureEmergency redirects the emergency logger for the rest of the test
+func captureEmergency(t *testing.T) *syncBuffer {
+	t.Helper()
+	var buf syncBuffer
+	setter := logger.Emergency().(logger.OutputSetter)
+	if err := setter.SetOutput(&buf); err != nil {
+		t.Fatalf("SetOutput failed: %v", err)
+	}
+	t.Cleanup(func() { setter.SetOutput(os.Stderr) })
+	return &buf
+}
+
+// flakyWriter fails while failing is set
+type flakyWriter struct {
+	failing int32
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	if atomic.LoadInt32(&w.failing) != 0 {
+		return 0, errors.New("broken pipe")
+	}
+	return len(p), nil
+}
+
+func TestEmergencyLogger(t *testing.T) {
+	if logger.Emergency() == nil || logger.Emergency() != logger.Emergency() {
+		t.Fatal("Expected a single preconstructed emergency logger")
+	}
+	buf := captureEmergency(t)
+
+	logger.Emergency().Debug("early startup", logger.Field{Key: "step", Value: "config"})
+	if !strings.Contains(buf.String(), "[DEBUG] early startup {step=config}") {
+		t.Errorf("Expected the emergency logger to write every level as text, got: %s", buf.String())
+	}
+	if d := logger.Describe(logger.Emergency()); d.Output != "emergency" || d.Format != "text" {
+		t.Errorf("Unexpected emergency logger description: %+v", d)
+	}
+}
+
+func TestEmergencyReportsFirstOutputFailure(t *testing.T) {
+	buf := captureEmergency(t)
+
+	w := &flakyWriter{failing: 1}
+	log := logger.New(logger.Config{Output: w, OutputName: "audit"})
+	log.Info("one")
+	log.Info("two")
+
+	if got := strings.Count(buf.String(), "log output failed"); got != 1 {
+		t.Fatalf("Expected one report for a run of failures, got %d: %s", got, buf.String())
+	}
+	if !strings.Contains(buf.String(), "{output=audit error=\"broken pipe\"}") {
+		t.Errorf("Expected the report to name the output and error, got: %s", buf.String())
+	}
+
+	atomic.StoreInt32(&w.failing, 0)
+	log.Info("recovered")
+	atomic.StoreInt32(&w.failing, 1)
+	log.Info("three")
+	if got := strings.Count(buf.String(), "log output failed"); got != 2 {
+		t.Errorf("Expected a new report after recovering, got %d: %s", got, buf.String())
+	}
+}
+
+// reportingWriter fails and logs its own failure to the emergency logger
+type reportingWriter struct {
+	calls int32
+}
+
+func (w *reportingWriter) Write(p []byte) (int, error) {
+	if atomic.AddInt32(&w.calls, 1) > 100 {
+		panic("unbounded recursion")
+	}
+	logger.Emergency().Error("writer failed")
+	return 0, errors.New("unavailable")
+}
+
+func TestEmergencyNoRecursion(t *testing.T) {
+	buf := captureEmergency(t)
+
+	w := &reportingWriter{}
+	log := logger.New(logger.Config{Output: w})
+	log.Info("one")
+	log.Info("two")
+
+	if got := atomic.LoadInt32(&w.calls); got != 2 {
+		t.Errorf("Expected one write per entry, got %d", got)
+	}
+	output := buf.String()
+	if strings.Count(output, "writer failed") != 2 || strings.Count(output, "log output failed") != 1 {
+		t.Errorf("Expected the writer's reports and one failure report, got: %s", output)
+	}
+
+	// Failures of the emergency output itself are not reported anywhere
+	if err := logger.Emergency().(logger.OutputSetter).SetOutput(failingWriter{}); err != nil {
+		t.Fatalf("SetOutput failed: %v", err)
+	}
+	logger.Emergency().Error("dropped")
+	logger.Emergency().Error("dropped again")
+	if health := logger.Emergency().(logger.HealthReporter).Health(); health.Healthy || health.ConsecutiveFailures != 2 {
+		t.Errorf("Expected the emergency output to be unhealthy, got: %+v", health)
+	}
+}
@@ -0,0 +1,254 @@
+package logger
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entry is a log entry as seen by an Enricher
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	// Fields holds the entry's fields in output order, with values
+	// evaluated at emission time already resolved
+	Fields []Field
+}
+
+// Enricher produces extra fields for an entry. Enrich is called while the
+// logger's lock is held and must not log through the same logger.
+type Enricher interface {
+	Enrich(e Entry) []Field
+}
+
+// EnricherFunc adapts a function to the Enricher interface
+type EnricherFunc func(e Entry) []Field
+
+// Enrich calls f(e)
+func (f EnricherFunc) Enrich(e Entry) []Field {
+	return f(e)
+}
+
+// EnrichRule applies an Enricher to entries at or above a level
+type EnrichRule struct {
+	Level    Level
+	Enricher Enricher
+}
+
+// EnrichAt returns a rule for Config.Enrichers that runs enricher only for
+// entries at level or above. Rules are evaluated after level filtering,
+// so entries below every rule's level pay nothing for them.
+func EnrichAt(level Level, enricher Enricher) EnrichRule {
+	return EnrichRule{Level: level, Enricher: enricher}
+}
+
+// minEnrichLevel returns the lowest level any rule applies to, or a level
+// above FatalLevel when there are no rules
+func minEnrichLevel(rules []EnrichRule) Level {
+	min := FatalLevel + 1
+	for _, rule := range rules {
+		if rule.Level < min {
+			min = rule.Level
+		}
+	}
+	return min
+}
+
+// enrich appends the fields of every rule that applies to the entry. An
+// enricher that panics contributes a _log_internal_error field instead.
+// With suppress set, enrichers governed by the memory governor are
+// skipped, and skipped reports whether any was.
+func (l *standardLogger) enrich(level Level, msg string, now time.Time, fields []Field, suppress bool) (enriched []Field, skipped bool) {
+	if level < l.enrichMin {
+		return fields, false
+	}
+
+	entry := Entry{Time: now, Level: level, Message: msg, Fields: fields}
+	var extra []Field
+	for _, rule := range l.enrichers {
+		if level < rule.Level || rule.Enricher == nil {
+			continue
+		}
+		if suppress && l.governor.governs(enricherName(rule.Enricher)) {
+			skipped = true
+			continue
+		}
+		extra = append(extra, safeEnrich(rule.Enricher, entry)...)
+	}
+	if len(extra) == 0 {
+		return fields, skipped
+	}
+
+	enriched = make([]Field, 0, len(fields)+len(extra))
+	enriched = append(enriched, fields...)
+	return append(enriched, extra...), skipped
+}
+
+func safeEnrich(enricher Enricher, entry Entry) (fields []Field) {
+	defer func() {
+		if r := recover(); r != nil {
+			fields = []Field{{Key: internalErrorKey, Value: fmt.Sprintf("panic in enricher %s: %v", enricherName(enricher), r)}}
+		}
+	}()
+	return enricher.Enrich(entry)
+}
+
+// enricherName describes an enricher in errors and logger descriptions
+func enricherName(enricher Enricher) string {
+	if s, ok := enricher.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", enricher)
+}
+
+// packagePrefix identifies stack frames inside this package
+const packagePrefix = "github.com/MichaelAJay/go-logger."
+
+// callerFrames returns the stack frames of the code that made the logging
+// call, skipping the frames of this package
+func callerFrames(limit int) []runtime.Frame {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var result []runtime.Frame
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, packagePrefix) {
+			result = append(result, frame)
+			if len(result) == limit {
+				break
+			}
+		}
+		if !more {
+			break
+		}
+	}
+	return result
+}
+
+// caller describes the logging call for Config.AddCaller
+func (l *standardLogger) caller() string {
+	frames := callerFrames(1)
+	if len(frames) == 0 {
+		return "<unknown>"
+	}
+	frame := frames[0]
+	if !l.trimCaller {
+		return frame.File + ":" + strconv.Itoa(frame.Line)
+	}
+	return packagePath(frame.Function) + "/" + filepath.Base(frame.File) + ":" + strconv.Itoa(frame.Line)
+}
+
+// packagePath returns the import path of the package declaring function,
+// given its fully qualified name such as example.com/pkg.(*T).Method
+func packagePath(function string) string {
+	slash := strings.LastIndex(function, "/")
+	if dot := strings.Index(function[slash+1:], "."); dot >= 0 {
+		return function[:slash+1+dot]
+	}
+	return function
+}
+
+type callerEnricher struct{}
+
+// CallerEnricher adds a caller field with the file and line of the logging
+// call, as dir/file.go:line
+func CallerEnricher() Enricher {
+	return callerEnricher{}
+}
+
+func (callerEnricher) Enrich(Entry) []Field {
+	frames := callerFrames(1)
+	if len(frames) == 0 {
+		return nil
+	}
+	return []Field{{Key: "caller", Value: shortCaller(frames[0].File, frames[0].Line)}}
+}
+
+func (callerEnricher) String() string { return "caller" }
+
+// stackTrace is the value of stack fields from Stack and
+// Config.AddStackTrace, innermost frame first
+type stackTrace []runtime.Frame
+
+// Stack returns a stack field holding the call stack of the caller, for
+// entries below Config.StackTraceLevel that need one
+func Stack() Field {
+	return Field{Key: "stack", Value: stackTrace(callerFrames(32))}
+}
+
+// String renders one "function (dir/file.go:line)" frame per line. Text
+// output joins the frames with semicolons instead to stay on one line.
+func (s stackTrace) String() string {
+	return s.join("\n")
+}
+
+func (s stackTrace) join(sep string) string {
+	parts := make([]string, len(s))
+	for i, frame := range s {
+		parts[i] = fmt.Sprintf("%s (%s)", frame.Function, shortCaller(frame.File, frame.Line))
+	}
+	return strings.Join(parts, sep)
+}
+
+type stackEnricher struct{}
+
+// StackEnricher adds a stack field listing the call stack of the logging
+// call, innermost first, as "function (dir/file.go:line)" separated by
+// semicolons
+func StackEnricher() Enricher {
+	return stackEnricher{}
+}
+
+func (stackEnricher) Enrich(Entry) []Field {
+	frames := callerFrames(32)
+	if len(frames) == 0 {
+		return nil
+	}
+	return []Field{{Key: "stack", Value: stackTrace(frames).join("; ")}}
+}
+
+func (stackEnricher) String() string { return "stack" }
+
+type goroutineEnricher struct{}
+
+// GoroutineCountEnricher adds a goroutines field with the number of
+// goroutines running when the entry is written
+func GoroutineCountEnricher() Enricher {
+	return goroutineEnricher{}
+}
+
+func (goroutineEnricher) Enrich(Entry) []Field {
+	return []Field{{Key: "goroutines", Value: runtime.NumGoroutine()}}
+}
+
+func (goroutineEnricher) String() string { return "goroutines" }
+
+type supportURLEnricher struct {
+	template string
+}
+
+// SupportURLEnricher adds a support_url field to entries carrying a Code
+// field, built by replacing {code} in template with the code
+func SupportURLEnricher(template string) Enricher {
+	return supportURLEnricher{template: template}
+}
+
+func (s supportURLEnricher) Enrich(e Entry) []Field {
+	for _, field := range e.Fields {
+		if field.Key != "code" {
+			continue
+		}
+		if code, ok := field.Value.(string); ok {
+			return []Field{{Key: "support_url", Value: strings.ReplaceAll(s.template, "{code}", code)}}
+		}
+	}
+	return nil
+}
+
+func (supportURLEnricher) String() string { return "support_url" }
@@ -0,0 +1,200 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestEnrichAtLevels(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{
+		Output: &buf,
+		Level:  logger.DebugLevel,
+		Enrichers: []logger.EnrichRule{
+			logger.EnrichAt(logger.WarnLevel, logger.CallerEnricher()),
+			logger.EnrichAt(logger.ErrorLevel, logger.StackEnricher()),
+			logger.EnrichAt(logger.ErrorLevel, logger.SupportURLEnricher("https://support.example.com/codes/{code}")),
+		},
+	})
+
+	log.Info("plain")
+	if strings.Contains(buf.String(), "caller=") || strings.Contains(buf.String(), "stack=") {
+		t.Errorf("Expected no enrichment below Warn, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	log.Warn("careful")
+	if !strings.Contains(buf.String(), "caller=") || !strings.Contains(buf.String(), "/enrich_test.go:") {
+		t.Errorf("Expected caller pointing at the test at Warn, got: %s", buf.String())
+	}
+	if strings.Contains(buf.String(), "stack=") {
+		t.Errorf("Expected no stack at Warn, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	log.(logger.CodeLogger).ErrorCode("DISK_FULL", "failed")
+	output := buf.String()
+	if !strings.Contains(output, "stack=\"github.com/MichaelAJay/go-logger_test.TestEnrichAtLevels (") {
+		t.Errorf("Expected stack starting at the test function, got: %s", output)
+	}
+	if !strings.Contains(output, "support_url=https://support.example.com/codes/DISK_FULL") {
+		t.Errorf("Expected support_url from the code, got: %s", output)
+	}
+}
+
+func TestEnrichersSkippedBelowLevel(t *testing.T) {
+	var calls int64
+	counting := logger.EnricherFunc(func(logger.Entry) []logger.Field {
+		atomic.AddInt64(&calls, 1)
+		return []logger.Field{{Key: "extra", Value: 1}}
+	})
+
+	enriched := logger.New(logger.Config{
+		Output:    io.Discard,
+		Level:     logger.DebugLevel,
+		Enrichers: []logger.EnrichRule{logger.EnrichAt(logger.ErrorLevel, counting)},
+	})
+	plain := logger.New(logger.Config{Output: io.Discard, Level: logger.DebugLevel})
+
+	measure := func(l logger.Logger) float64 {
+		return testing.AllocsPerRun(100, func() {
+			l.Debug("d", logger.Field{Key: "k", Value: "v"})
+			l.Info("i", logger.Field{Key: "k", Value: "v"})
+		})
+	}
+	if got, want := measure(enriched), measure(plain); got != want {
+		t.Errorf("Expected Debug/Info allocations to match a logger without rules, got %v want %v", got, want)
+	}
+	if calls != 0 {
+		t.Errorf("Expected enricher not to run below its level, ran %d times", calls)
+	}
+
+	enriched.Error("e")
+	if calls != 1 {
+		t.Errorf("Expected enricher to run once at Error, ran %d times", calls)
+	}
+}
+
+func TestEnricherPanicIsGuarded(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{
+		Output: &buf,
+		Enrichers: []logger.EnrichRule{
+			logger.EnrichAt(logger.InfoLevel, logger.EnricherFunc(func(logger.Entry) []logger.Field {
+				panic("enricher exploded")
+			})),
+			logger.EnrichAt(logger.InfoLevel, logger.GoroutineCountEnricher()),
+		},
+	})
+
+	log.Info("still written", logger.Field{Key: "k", Value: "v"})
+
+	output := buf.String()
+	if !strings.Contains(output, "still written {k=v _log_internal_error=\"panic in enricher logger.EnricherFunc: enricher exploded\" goroutines=") {
+		t.Errorf("Expected panic to be reported and later enrichers to run, got: %s", output)
+	}
+}
+
+func TestDescribeEnrichers(t *testing.T) {
+	log := logger.New(logger.Config{
+		Output:    io.Discard,
+		Enrichers: []logger.EnrichRule{logger.EnrichAt(logger.ErrorLevel, logger.StackEnricher())},
+	})
+
+	enrichments := strings.Join(logger.Describe(log).Enrichments, ",")
+	if !strings.Contains(enrichments, "stack>=ERROR") {
+		t.Errorf("Expected description to list the stack rule, got %q", enrichments)
+	}
+}
+
+func TestAddCaller(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, AddCaller: true})
+
+	_, file, line, _ := runtime.Caller(0)
+	log.Info("direct", logger.Field{Key: "k", Value: "v"})
+	expected := fmt.Sprintf("{k=v caller=%s:%d}", file, line+1)
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("Expected %q, got: %s", expected, buf.String())
+	}
+
+	previous := logger.GetDefaultLogger()
+	defer logger.SetDefaultLogger(previous)
+	logger.SetDefaultLogger(logger.WithLazy(logger.MultiLogger(log), logger.Field{Key: "lazy", Value: true}))
+
+	buf.Reset()
+	_, _, line, _ = runtime.Caller(0)
+	logger.Warn("through helpers")
+	expected = fmt.Sprintf("caller=%s:%d}", file, line+1)
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("Expected package helpers to report the calling frame %q, got: %s", expected, buf.String())
+	}
+}
+
+func TestTrimCallerPath(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, AddCaller: true, TrimCallerPath: true, Format: logger.FormatJSON})
+
+	_, _, line, _ := runtime.Caller(0)
+	log.Info("trimmed")
+	expected := fmt.Sprintf(`"caller":"github.com/MichaelAJay/go-logger_test/enrich_test.go:%d"`, line+1)
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("Expected %s, got: %s", expected, buf.String())
+	}
+}
+
+func TestAddStackTrace(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, AddStackTrace: true, StackTraceLevel: logger.ErrorLevel, Strict: true})
+
+	log.Warn("no stack")
+	log.Error("with stack")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected two single-line entries, got: %s", buf.String())
+	}
+	if strings.Contains(lines[0], "stack=") {
+		t.Errorf("Expected no stack below StackTraceLevel, got: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], "stack=\"github.com/MichaelAJay/go-logger_test.TestAddStackTrace (") ||
+		!strings.Contains(lines[1], "/enrich_test.go:") || strings.Contains(lines[1], "go-logger.(*standardLogger)") {
+		t.Errorf("Expected a stack starting at the test without logger frames, got: %s", lines[1])
+	}
+	if strings.Contains(lines[1], "_log_") {
+		t.Errorf("Expected the stack to be loggable in strict mode, got: %s", lines[1])
+	}
+	if d := logger.Describe(log); !strings.Contains(strings.Join(d.Enrichments, ","), "stack>=ERROR") {
+		t.Errorf("Expected the description to list the stack trace, got %v", d.Enrichments)
+	}
+}
+
+func TestStackField(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatJSON})
+
+	log.Info("on demand", logger.Stack())
+
+	output := buf.String()
+	if strings.Count(output, "\n") != 1 {
+		t.Fatalf("Expected a single JSON line, got: %s", output)
+	}
+	var entry struct {
+		Stack string `json:"stack"`
+	}
+	if err := json.Unmarshal([]byte(output), &entry); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	frames := strings.Split(entry.Stack, "\n")
+	if len(frames) < 2 || !strings.HasPrefix(frames[0], "github.com/MichaelAJay/go-logger_test.TestStackField (") {
+		t.Errorf("Expected newline separated frames starting at the caller, got %q", entry.Stack)
+	}
+}
@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"sort"
+	"time"
+)
+
+// EntryTruncatedKey marks entries shrunk to fit Config.MaxEntryBytes
+const EntryTruncatedKey = "truncated"
+
+// shrinkEntry encodes an entry that is over l.maxEntry again, dropping
+// the largest fields one by one until it fits and marking it
+// truncated=true. When the entry still does not fit without any field,
+// the message is cut too.
+func (l *standardLogger) shrinkEntry(now time.Time, level Level, msg string, fields []Field) string {
+	marker := Field{Key: EntryTruncatedKey, Value: true}
+
+	// Drop the largest fields first, keeping the order of the rest
+	order := make([]int, len(fields))
+	sizes := make([]int, len(fields))
+	for i, field := range fields {
+		order[i] = i
+		sizes[i] = fieldSize(field)
+	}
+	sort.SliceStable(order, func(a, b int) bool { return sizes[order[a]] > sizes[order[b]] })
+	dropped := make([]bool, len(fields))
+
+	kept := make([]Field, 0, len(fields)+1)
+	for _, next := range order {
+		dropped[next] = true
+		kept = kept[:0]
+		for i, field := range fields {
+			if !dropped[i] {
+				kept = append(kept, field)
+			}
+		}
+		kept = append(kept, marker)
+		if line := l.encode(now, level, msg, kept); len(line) <= l.maxEntry {
+			return line
+		}
+	}
+
+	// The message alone is too long: cut it by the excess until it fits,
+	// or until nothing of it is left
+	kept = []Field{marker}
+	line := l.encode(now, level, msg, kept)
+	original, keep := msg, len(msg)
+	for len(line) > l.maxEntry && keep > 0 {
+		keep -= len(line) - l.maxEntry
+		if keep < 0 {
+			keep = 0
+		}
+		cut := truncateString(original, keep)
+		msg = cut + truncationMarker(len(original)-len(cut))
+		line = l.encode(now, level, msg, kept)
+	}
+	return line
+}
+
+// fieldSize estimates the encoded size of a field from its key and the
+// text of its value
+func fieldSize(field Field) int {
+	size := len(field.Key)
+	if group, ok := field.Value.(groupValue); ok {
+		for _, member := range group {
+			size += fieldSize(member)
+		}
+		return size
+	}
+	switch v := field.Value.(type) {
+	case string:
+		return size + len(v)
+	case []byte:
+		return size + len(v)*4/3
+	}
+	text, _ := formatValue(field.Value)
+	return size + len(text)
+}
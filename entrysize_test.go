@@ -0,0 +1,80 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestMaxEntryBytes(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatJSON, MaxEntryBytes: 200})
+
+	log.Info("upload",
+		logger.Field{Key: "user", Value: "u1"},
+		logger.Field{Key: "body", Value: strings.Repeat("b", 500)},
+		logger.Field{Key: "headers", Value: strings.Repeat("h", 150)},
+		logger.Field{Key: "status", Value: 413},
+	)
+
+	line := strings.TrimSuffix(buf.String(), "\n")
+	if len(line) > 200 {
+		t.Errorf("Expected at most 200 bytes, got %d", len(line))
+	}
+	entry := decodeEntry(t, buf.String())
+	if entry["truncated"] != true || entry["body"] != nil || entry["headers"] != nil {
+		t.Errorf("Expected the largest fields dropped and the entry marked, got %v", entry)
+	}
+	if entry["user"] != "u1" || entry["status"] != float64(413) || entry["msg"] != "upload" {
+		t.Errorf("Expected the small fields and message kept, got %v", entry)
+	}
+}
+
+func TestMaxEntryBytesDropsOnlyWhatIsNeeded(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatJSON, MaxEntryBytes: 300})
+
+	log.Info("upload", logger.Field{Key: "body", Value: strings.Repeat("b", 500)}, logger.Field{Key: "headers", Value: strings.Repeat("h", 150)})
+
+	entry := decodeEntry(t, buf.String())
+	if entry["body"] != nil || entry["headers"] == nil {
+		t.Errorf("Expected only the body dropped, got %v", entry)
+	}
+}
+
+func TestMaxEntryBytesWithinCap(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, MaxEntryBytes: 1000})
+
+	log.Info("small", logger.Field{Key: "k", Value: "v"})
+
+	if !strings.Contains(buf.String(), "small {k=v}") || strings.Contains(buf.String(), "truncated") {
+		t.Errorf("Expected the entry unchanged, got: %s", buf.String())
+	}
+}
+
+func TestMaxEntryBytesMessageTooLong(t *testing.T) {
+	for _, format := range []logger.Format{logger.FormatJSON, logger.FormatGELF} {
+		t.Run(format.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			log := logger.New(logger.Config{Output: &buf, Format: format, Host: "h", MaxEntryBytes: 160})
+
+			log.Error(strings.Repeat("é", 1000), logger.Field{Key: "k", Value: "v"})
+
+			line := strings.TrimSuffix(buf.String(), "\n")
+			if len(line) > 160 {
+				t.Errorf("Expected at most 160 bytes, got %d: %s", len(line), line)
+			}
+			var entry map[string]any
+			if err := json.Unmarshal([]byte(line), &entry); err != nil {
+				t.Fatalf("Expected a valid document, got %v: %s", err, line)
+			}
+			if !strings.Contains(line, "...(truncated ") || !strings.Contains(line, `truncated":`) {
+				t.Errorf("Expected a cut message and the marker, got: %s", line)
+			}
+		})
+	}
+}
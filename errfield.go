@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+)
+
+// errorValue is the value of fields created with Err
+type errorValue struct {
+	err error
+}
+
+// StackTracer is implemented by errors that record the call stack where
+// they were created, as program counters filled in by runtime.Callers
+type StackTracer interface {
+	StackTrace() []uintptr
+}
+
+// Err returns an error field holding err's message. Machine-readable
+// formats also get error.cause, the messages of the errors.Unwrap chain
+// below err, error.type, the %T of the innermost error, and error.stack
+// when an error in the chain implements StackTracer. A nil err writes
+// nothing.
+func Err(err error) Field {
+	return Field{Key: "error", Value: errorValue{err: err}}
+}
+
+// appendError expands an Err field. Text and console output only get the
+// message.
+func (l *standardLogger) appendError(fields []Field, key string, err error) []Field {
+	if err == nil {
+		return fields
+	}
+	fields = append(fields, Field{Key: key, Value: err})
+	if l.format == FormatText || l.format == FormatConsole {
+		return fields
+	}
+
+	var causes []string
+	var pcs []uintptr
+	root := err
+	// The chain is walked by depth rather than by comparing errors, which
+	// panics for error types that are not comparable
+	for e, depth := err, 0; e != nil; e, depth = errors.Unwrap(e), depth+1 {
+		if tracer, ok := e.(StackTracer); ok {
+			pcs = tracer.StackTrace()
+		}
+		if depth > 0 {
+			text, _ := formatValue(e)
+			causes = append(causes, text)
+		}
+		root = e
+	}
+
+	if len(causes) > 0 {
+		fields = append(fields, Field{Key: key + ".cause", Value: causes})
+	}
+	fields = append(fields, Field{Key: key + ".type", Value: fmt.Sprintf("%T", root)})
+	if len(pcs) > 0 {
+		fields = append(fields, Field{Key: key + ".stack", Value: stackFrames(pcs)})
+	}
+	return fields
+}
+
+// stackFrames resolves program counters from runtime.Callers
+func stackFrames(pcs []uintptr) stackTrace {
+	frames := runtime.CallersFrames(pcs)
+	var trace stackTrace
+	for {
+		frame, more := frames.Next()
+		trace = append(trace, frame)
+		if !more {
+			break
+		}
+	}
+	return trace
+}
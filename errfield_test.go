@@ -0,0 +1,156 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+type notFoundError struct{ name string }
+
+func (e *notFoundError) Error() string { return e.name + " not found" }
+
+// tracedError records where it was created, as errors packages with
+// stack traces do
+type tracedError struct {
+	err error
+	pcs []uintptr
+}
+
+func newTracedError(err error) error {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(2, pcs)
+	return &tracedError{err: err, pcs: pcs[:n]}
+}
+
+func (e *tracedError) Error() string         { return e.err.Error() }
+func (e *tracedError) Unwrap() error         { return e.err }
+func (e *tracedError) StackTrace() []uintptr { return e.pcs }
+
+// sliceError cannot be compared with ==
+type sliceError struct{ parts []string }
+
+func (e sliceError) Error() string { return strings.Join(e.parts, ": ") }
+
+func TestErrUncomparableError(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatJSON})
+
+	log.Error("failed", logger.Err(fmt.Errorf("wrap: %w", sliceError{parts: []string{"a", "b"}})))
+	log.Error("failed", logger.Err(sliceError{parts: []string{"c"}}))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected two entries, got: %s", buf.String())
+	}
+	wrapped := decodeEntry(t, lines[0])
+	if !reflect.DeepEqual(wrapped["error.cause"], []any{"a: b"}) || wrapped["error.type"] != "logger_test.sliceError" {
+		t.Errorf("Expected the chain of an uncomparable error, got %v", wrapped)
+	}
+	if bare := decodeEntry(t, lines[1]); bare["error"] != "c" || bare["error.cause"] != nil {
+		t.Errorf("Expected no causes for an unwrapped error, got %v", bare)
+	}
+}
+
+func TestErrText(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+
+	err := fmt.Errorf("load config: %w", &notFoundError{name: "app.yaml"})
+	log.Error("startup failed", logger.Err(err))
+
+	if !strings.Contains(buf.String(), `{error="load config: app.yaml not found"}`) {
+		t.Errorf("Expected only the message in text output, got: %s", buf.String())
+	}
+}
+
+func TestErrExpandsChain(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatJSON})
+
+	root := &notFoundError{name: "app.yaml"}
+	err := fmt.Errorf("startup: %w", fmt.Errorf("load config: %w", root))
+	log.Error("startup failed", logger.Err(err))
+
+	entry := decodeEntry(t, buf.String())
+	if entry["error"] != "startup: load config: app.yaml not found" {
+		t.Errorf("Expected the full message, got %v", entry["error"])
+	}
+	expected := []any{"load config: app.yaml not found", "app.yaml not found"}
+	if !reflect.DeepEqual(entry["error.cause"], expected) {
+		t.Errorf("Expected causes %v, got %v", expected, entry["error.cause"])
+	}
+	if entry["error.type"] != "*logger_test.notFoundError" {
+		t.Errorf("Expected the root cause type, got %v", entry["error.type"])
+	}
+	if _, ok := entry["error.stack"]; ok {
+		t.Errorf("Expected no stack without a StackTracer, got %v", entry["error.stack"])
+	}
+}
+
+func TestErrUnwrapped(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatJSON})
+
+	log.Error("failed", logger.Err(errors.New("boom")))
+
+	entry := decodeEntry(t, buf.String())
+	if _, ok := entry["error.cause"]; ok {
+		t.Errorf("Expected no causes for an error that wraps nothing, got %v", entry["error.cause"])
+	}
+	if entry["error"] != "boom" || entry["error.type"] != "*errors.errorString" {
+		t.Errorf("Expected the message and type, got %v", entry)
+	}
+}
+
+func TestErrNil(t *testing.T) {
+	for _, format := range []logger.Format{logger.FormatText, logger.FormatJSON} {
+		var buf bytes.Buffer
+		log := logger.New(logger.Config{Output: &buf, Format: format})
+
+		log.Info("done", logger.Err(nil))
+		if strings.Contains(buf.String(), "error") {
+			t.Errorf("Expected a nil error to write nothing in format %v, got: %s", format, buf.String())
+		}
+	}
+}
+
+func TestErrStack(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatJSON})
+
+	err := fmt.Errorf("handler: %w", newTracedError(errors.New("disk full")))
+	log.Error("write failed", logger.Err(err))
+
+	entry := decodeEntry(t, buf.String())
+	stack, _ := entry["error.stack"].(string)
+	if !strings.Contains(stack, "TestErrStack") || !strings.Contains(stack, "errfield_test.go:") {
+		t.Errorf("Expected the stack where the error was created, got %q", stack)
+	}
+	if entry["error.type"] != "*errors.errorString" {
+		t.Errorf("Expected the root cause type below the traced error, got %v", entry["error.type"])
+	}
+}
+
+func TestErrECS(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatECS})
+
+	err := fmt.Errorf("handler: %w", newTracedError(&notFoundError{name: "user"}))
+	log.Error("lookup failed", logger.Err(err))
+
+	doc := decodeEntry(t, buf.String())
+	ecsErr, _ := doc["error"].(map[string]any)
+	if ecsErr["message"] != "handler: user not found" || ecsErr["type"] != "*logger_test.notFoundError" {
+		t.Errorf("Expected the message and root cause type under error, got %v", ecsErr)
+	}
+	if trace, _ := ecsErr["stack_trace"].(string); !strings.Contains(trace, "TestErrECS") {
+		t.Errorf("Expected the stack as error.stack_trace, got %v", ecsErr["stack_trace"])
+	}
+}
@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"errors"
+	"strings"
+)
+
+// The logger's own failures are reported as the errors below, whether
+// they reach Config.OnError, a Flush or Close return value or
+// SinkHealth.Err. Match them with errors.Is and errors.As rather than by
+// their text, which may change.
+var (
+	// ErrSinkUnavailable reports an entry dropped because its output no
+	// longer accepts writes, such as one stopped after
+	// Config.BrokenPipeLimit broken pipe errors
+	ErrSinkUnavailable = errors.New("logger: sink unavailable")
+)
+
+// WriteError is a failure to write to, or close, an output. Dest is the
+// output's name as shown in health reports.
+type WriteError struct {
+	Dest  string
+	Cause error
+}
+
+func (e *WriteError) Error() string {
+	return "logger: write to " + e.Dest + ": " + e.Cause.Error()
+}
+
+func (e *WriteError) Unwrap() error { return e.Cause }
+
+// FormatError is a failure to render part of an entry. The entry is still
+// written, with the problem in its _log_internal_error field. Formatter
+// is the name of the Format, such as "json".
+type FormatError struct {
+	Formatter string
+	Cause     error
+}
+
+func (e *FormatError) Error() string {
+	return "logger: format " + e.Formatter + ": " + e.Cause.Error()
+}
+
+func (e *FormatError) Unwrap() error { return e.Cause }
+
+// formatFailed passes the rendering failures of an entry to OnError
+func (l *standardLogger) formatFailed(failures []string) {
+	if l.onError != nil {
+		l.onError(&FormatError{Formatter: l.format.String(), Cause: errors.New(strings.Join(failures, "; "))})
+	}
+}
+
+// writeFailed passes a write failure of the named output to OnError and
+// returns it as a WriteError
+func (l *standardLogger) writeFailed(dest string, err error) *WriteError {
+	werr := &WriteError{Dest: dest, Cause: err}
+	if l.onError != nil {
+		l.onError(werr)
+	}
+	return werr
+}
@@ -0,0 +1,156 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+var errDiskFull = errors.New("disk full")
+
+// faultWriter fails every write with err
+type faultWriter struct {
+	err error
+}
+
+func (w faultWriter) Write(p []byte) (int, error) {
+	return 0, w.err
+}
+
+// collectErrors returns an OnError callback and the errors it received
+func collectErrors() (func(error), *[]error) {
+	var errs []error
+	return func(err error) { errs = append(errs, err) }, &errs
+}
+
+// assertTaxonomy fails unless every error is a WriteError or FormatError
+// or wraps one of the sentinels
+func assertTaxonomy(t *testing.T, errs []error) {
+	t.Helper()
+	for _, err := range errs {
+		var werr *logger.WriteError
+		var ferr *logger.FormatError
+		if !errors.As(err, &werr) && !errors.As(err, &ferr) &&
+			!errors.Is(err, logger.ErrSinkUnavailable) {
+			t.Errorf("Expected a taxonomy error, got %T: %v", err, err)
+		}
+	}
+}
+
+func TestWriteErrorReported(t *testing.T) {
+	captureEmergency(t)
+	onError, errs := collectErrors()
+	log := logger.New(logger.Config{Output: faultWriter{errDiskFull}, OutputName: "disk", OnError: onError})
+
+	log.Info("lost")
+
+	if len(*errs) != 1 {
+		t.Fatalf("Expected one error, got %v", *errs)
+	}
+	var werr *logger.WriteError
+	if !errors.As((*errs)[0], &werr) || werr.Dest != "disk" || !errors.Is((*errs)[0], errDiskFull) {
+		t.Errorf("Expected a WriteError for disk wrapping the cause, got %v", (*errs)[0])
+	}
+
+	health := log.(logger.HealthReporter).Health()
+	if !errors.As(health.Err, &werr) || !errors.Is(health.Err, errDiskFull) || health.LastError != "disk full" {
+		t.Errorf("Expected the health report to carry the WriteError, got %+v", health)
+	}
+
+	log.(logger.OutputSetter).SetOutput(io.Discard)
+	log.Info("written")
+	if health := log.(logger.HealthReporter).Health(); health.Err != nil {
+		t.Errorf("Expected a successful write to clear the error, got %v", health.Err)
+	}
+	assertTaxonomy(t, *errs)
+}
+
+func TestStoppedOutputIsUnavailable(t *testing.T) {
+	captureEmergency(t)
+	onError, errs := collectErrors()
+	log := logger.New(logger.Config{Output: brokenPipe(t), BrokenPipeLimit: 1, OutputName: "pipe", OnError: onError})
+
+	log.Info("breaks the pipe")
+	log.Info("dropped")
+
+	if len(*errs) != 2 {
+		t.Fatalf("Expected two errors, got %v", *errs)
+	}
+	var werr *logger.WriteError
+	if !errors.As((*errs)[1], &werr) || werr.Dest != "pipe" || !errors.Is((*errs)[1], logger.ErrSinkUnavailable) {
+		t.Errorf("Expected the dropped entry to report ErrSinkUnavailable, got %v", (*errs)[1])
+	}
+	assertTaxonomy(t, *errs)
+}
+
+func TestFallbackWriteErrorReported(t *testing.T) {
+	captureEmergency(t)
+	onError, errs := collectErrors()
+	log := logger.New(logger.Config{
+		Output:          brokenPipe(t),
+		BrokenPipeLimit: 1,
+		FallbackOutput:  faultWriter{errDiskFull},
+		OnError:         onError,
+	})
+
+	log.Info("breaks the pipe")
+	log.Info("diverted")
+
+	if len(*errs) != 2 || !errors.Is((*errs)[1], errDiskFull) {
+		t.Fatalf("Expected the fallback failure to be reported, got %v", *errs)
+	}
+	assertTaxonomy(t, *errs)
+}
+
+func TestFormatErrorReported(t *testing.T) {
+	formats := []logger.Format{
+		logger.FormatText,
+		logger.FormatJSON,
+		logger.FormatLogfmt,
+		logger.FormatGELF,
+		logger.FormatECS,
+		logger.FormatSyslog,
+		logger.FormatConsole,
+	}
+
+	for _, format := range formats {
+		t.Run(format.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			onError, errs := collectErrors()
+			log := logger.New(logger.Config{Output: &buf, Format: format, OnError: onError})
+
+			log.Info("entry", logger.Field{Key: "bad", Value: panickingStringer{}})
+
+			if buf.Len() == 0 {
+				t.Error("Expected the entry to be written despite the failure")
+			}
+			if len(*errs) != 1 {
+				t.Fatalf("Expected one error, got %v", *errs)
+			}
+			var ferr *logger.FormatError
+			if !errors.As((*errs)[0], &ferr) || ferr.Formatter != format.String() {
+				t.Errorf("Expected a FormatError from %s, got %v", format, (*errs)[0])
+			}
+			assertTaxonomy(t, *errs)
+		})
+	}
+}
+
+func TestErrorTaxonomyMatching(t *testing.T) {
+	werr := &logger.WriteError{Dest: "app.log", Cause: os.ErrClosed}
+	if !errors.Is(werr, os.ErrClosed) || werr.Error() != "logger: write to app.log: file already closed" {
+		t.Errorf("Expected WriteError to wrap its cause, got %q", werr.Error())
+	}
+	ferr := &logger.FormatError{Formatter: "json", Cause: errDiskFull}
+	if !errors.Is(ferr, errDiskFull) || ferr.Error() != "logger: format json: disk full" {
+		t.Errorf("Expected FormatError to wrap its cause, got %q", ferr.Error())
+	}
+	stopped := &logger.WriteError{Dest: "stdout", Cause: logger.ErrSinkUnavailable}
+	if !errors.Is(stopped, logger.ErrSinkUnavailable) || errors.Is(stopped, os.ErrClosed) {
+		t.Errorf("Expected WriteError to match only its sentinel, got %v", stopped)
+	}
+}
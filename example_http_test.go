@@ -0,0 +1,27 @@
+//go:build !logger_minimal
+
+package logger_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func ExampleCanonicalMiddleware() {
+	log := logger.New(exampleConfig())
+
+	orders := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Handlers add to the request's canonical line instead of logging
+		logger.CanonicalFromContext(r.Context()).Add(logger.Field{Key: "order_id", Value: 42})
+		w.WriteHeader(http.StatusCreated)
+	})
+	handler := logger.CanonicalMiddleware(log, true)(orders)
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	req = req.WithContext(logger.WithRequestID(req.Context(), "req-1"))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	// Output:
+	// 2024-03-01T12:00:00Z [INFO] request completed {request_id=req-1 http.method=POST http.path=/orders http.host=example.com http.proto=HTTP/1.1 http.remote_ip=192.0.2.1 order_id=42 http_response.status=201 http_response.size=0 http_response.duration=0s}
+}
@@ -0,0 +1,205 @@
+package logger_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+	"github.com/MichaelAJay/go-logger/logtest"
+)
+
+// exampleTime is the time of the examples' clocks, which keeps their
+// output the same on every run
+var exampleTime = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// exampleConfig returns a configuration writing to standard output with
+// a fixed clock. The sub-second time format leaves out the standard
+// library date, which would change from run to run.
+func exampleConfig() logger.Config {
+	return logger.Config{
+		Output:     os.Stdout,
+		Clock:      fixedClock(exampleTime),
+		UTC:        true,
+		TimeFormat: logger.TimeFormatRFC3339Nano,
+	}
+}
+
+func ExampleNew() {
+	log := logger.New(exampleConfig())
+
+	log.Info("server started", logger.Field{Key: "port", Value: 8080})
+	// Output:
+	// 2024-03-01T12:00:00Z [INFO] server started {port=8080}
+}
+
+func ExampleNew_json() {
+	cfg := exampleConfig()
+	cfg.Format = logger.FormatJSON
+	log := logger.New(cfg).With(logger.Field{Key: "service", Value: "api"})
+
+	log.Warn("slow query", logger.Field{Key: "table", Value: "users"}, logger.Field{Key: "elapsed", Value: 1500 * time.Millisecond})
+	// Output:
+	// {"time":"2024-03-01T12:00:00Z","level":"WARN","msg":"slow query","service":"api","table":"users","elapsed":"1.5s"}
+}
+
+func ExampleNew_fatal() {
+	cfg := exampleConfig()
+	// Tests and examples replace os.Exit to observe Fatal
+	cfg.ExitFunc = func(code int) { fmt.Println("exit", code) }
+	log := logger.New(cfg)
+
+	log.Fatal("config missing", logger.Field{Key: "path", Value: "/etc/app.yaml"})
+	// Output:
+	// 2024-03-01T12:00:00Z [FATAL] config missing {path=/etc/app.yaml}
+	// exit 1
+}
+
+func ExampleNew_duplicateKeys() {
+	cfg := exampleConfig()
+	cfg.DuplicateKeys = logger.DuplicateSuffix
+	log := logger.New(cfg).With(logger.Field{Key: "user_id", Value: "u1"})
+
+	log.Info("impersonating", logger.Field{Key: "user_id", Value: "u2"})
+	// Output:
+	// 2024-03-01T12:00:00Z [INFO] impersonating {user_id=u1 user_id_2=u2}
+}
+
+func ExampleNewFactory_presets() {
+	factory := logger.NewFactory(logger.Config{Clock: fixedClock(exampleTime)}).
+		UTC().
+		TimeFormat("2006-01-02T15:04:05.000Z07:00")
+
+	factory.Console(logger.InfoLevel).Info("from a preset")
+	// Output:
+	// 2024-03-01T12:00:00.000Z [INFO] from a preset
+}
+
+func ExampleMultiLogger_levels() {
+	console := exampleConfig()
+	console.Level = logger.WarnLevel
+	audit := exampleConfig()
+	audit.Level = logger.DebugLevel
+	audit.Format = logger.FormatJSON
+
+	// Every child applies its own level and format to the same entry
+	log := logger.MultiLogger(logger.New(console), logger.New(audit))
+
+	log.Debug("cache miss", logger.Field{Key: "key", Value: "k1"})
+	log.Warn("cache full", logger.Field{Key: "entries", Value: 1024})
+	// Output:
+	// {"time":"2024-03-01T12:00:00Z","level":"DEBUG","msg":"cache miss","key":"k1"}
+	// 2024-03-01T12:00:00Z [WARN] cache full {entries=1024}
+	// {"time":"2024-03-01T12:00:00Z","level":"WARN","msg":"cache full","entries":1024}
+}
+
+func ExampleGroup() {
+	log := logger.New(exampleConfig())
+
+	log.Info("charged", logger.Group("payment", logger.Field{Key: "amount", Value: 1999}, logger.Field{Key: "currency", Value: "EUR"}))
+	// Output:
+	// 2024-03-01T12:00:00Z [INFO] charged {payment.amount=1999 payment.currency=EUR}
+}
+
+func ExampleSince() {
+	log := logger.New(exampleConfig())
+	start := exampleTime.Add(-250 * time.Millisecond)
+
+	// The elapsed time is measured with the logger's clock when the entry
+	// is written
+	log.Info("batch done", logger.Since("elapsed", start))
+	// Output:
+	// 2024-03-01T12:00:00Z [INFO] batch done {elapsed=250ms}
+}
+
+func ExampleSnapshot() {
+	type job struct{ State string }
+	current := &job{State: "queued"}
+
+	log := logger.New(exampleConfig())
+	jobLog := log.With(logger.Snapshot("job", current))
+	current.State = "running"
+
+	jobLog.Info("picked up")
+	// Output:
+	// 2024-03-01T12:00:00Z [INFO] picked up {job="&{queued}"}
+}
+
+func ExampleNewCanonical() {
+	log := logger.New(exampleConfig())
+	cl := logger.NewCanonical(log)
+
+	cl.Add(logger.Field{Key: "auth", Value: "ok"})
+	cl.AddTiming("db", 20*time.Millisecond)
+	cl.AddTiming("db", 5*time.Millisecond)
+	cl.Emit(logger.InfoLevel, "request completed")
+	// Output:
+	// 2024-03-01T12:00:00Z [INFO] request completed {auth=ok db=25ms db_count=2}
+}
+
+func ExampleInjectContext() {
+	log := logger.New(exampleConfig())
+
+	// The producer copies its request ID into the message headers
+	headers := logger.MapCarrier{}
+	logger.InjectContext(logger.WithRequestID(context.Background(), "req-42"), headers)
+	fmt.Println(headers)
+
+	// The consumer restores it and logs with it
+	ctx := logger.ExtractContext(context.Background(), headers)
+	log.WithContext(ctx).Info("message consumed")
+	// Output:
+	// map[X-Request-Id:req-42]
+	// 2024-03-01T12:00:00Z [INFO] message consumed {request_id=req-42}
+}
+
+type regionKey struct{}
+
+func ExampleRegisteredContextExtractors_custom() {
+	// Start from the global extractors and add one for this logger only
+	extractors := append(logger.RegisteredContextExtractors(), logger.ContextExtractor{
+		Key:   regionKey{},
+		Field: "region",
+	})
+	log := logger.WithContextExtractors(logger.New(exampleConfig()), extractors...)
+
+	ctx := logger.WithRequestID(context.Background(), "req-7")
+	ctx = context.WithValue(ctx, regionKey{}, "eu-west")
+	log.WithContext(ctx).Info("invoice created")
+	// Output:
+	// 2024-03-01T12:00:00Z [INFO] invoice created {request_id=req-7 region=eu-west}
+}
+
+func ExampleParseFields() {
+	fields, err := logger.ParseFields("env=prod,replica=3")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	logger.New(exampleConfig()).With(fields...).Info("booted")
+	// Output:
+	// 2024-03-01T12:00:00Z [INFO] booted {env=prod replica=3}
+}
+
+// Example_flushOnShutdown closes a file logger before the process exits,
+// so no entry is lost. The in-memory filesystem stands in for the disk.
+func Example_flushOnShutdown() {
+	fsys := logtest.NewMemFS()
+	factory := logger.NewFactory(exampleConfig()).FileSystem(fsys)
+	log, err := factory.File("/var/log/app.log", logger.InfoLevel)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	log.Info("shutting down")
+	if err := log.Close(); err != nil {
+		fmt.Println(err)
+	}
+
+	content, _ := fsys.ReadFile("/var/log/app.log")
+	fmt.Print(string(content))
+	// Output:
+	// 2024-03-01T12:00:00Z [INFO] shutting down
+}
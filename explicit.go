@@ -0,0 +1,142 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// maxUninitializedCallSites bounds the call sites kept by the report
+const maxUninitializedCallSites = 20
+
+// ExplicitDefaultOption configures RequireExplicitDefault
+type ExplicitDefaultOption func(*uninitializedLogger)
+
+// PanicOnUse makes package-level helpers panic while no default logger
+// has been set, so tests fail at the offending call
+func PanicOnUse() ExplicitDefaultOption {
+	return func(u *uninitializedLogger) {
+		u.panicOnUse = true
+	}
+}
+
+// RequireExplicitDefault replaces the default logger with a recorder that
+// writes nothing, until SetDefaultLogger or Init installs a real one. It
+// is meant for applications that want to find libraries logging through
+// the package-level helpers instead of a logger they were given: each
+// call is counted and the first call sites are kept for
+// UninitializedUsage.
+//
+// Calling it again starts a new report.
+func RequireExplicitDefault(opts ...ExplicitDefaultOption) {
+	recorder := &uninitializedLogger{}
+	for _, opt := range opts {
+		opt(recorder)
+	}
+
+	uninitializedMu.Lock()
+	uninitialized = recorder
+	uninitializedMu.Unlock()
+
+	SetDefaultLogger(recorder)
+}
+
+// UninitializedReport describes logging through the default logger before
+// it was set
+type UninitializedReport struct {
+	// Calls counts every logging call
+	Calls int64 `json:"calls"`
+	// CallSites lists the first distinct locations that logged, as
+	// function (dir/file.go:line)
+	CallSites []string `json:"call_sites,omitempty"`
+}
+
+// UninitializedUsage reports the calls recorded since the last
+// RequireExplicitDefault. It is empty if RequireExplicitDefault was never
+// called.
+func UninitializedUsage() UninitializedReport {
+	uninitializedMu.Lock()
+	recorder := uninitialized
+	uninitializedMu.Unlock()
+
+	if recorder == nil {
+		return UninitializedReport{}
+	}
+	return recorder.report()
+}
+
+var (
+	uninitializedMu sync.Mutex
+	uninitialized   *uninitializedLogger
+)
+
+// uninitializedLogger is the default logger installed by
+// RequireExplicitDefault. Derived loggers are the recorder itself.
+type uninitializedLogger struct {
+	panicOnUse bool
+	calls      int64
+
+	mu    sync.Mutex
+	sites []string
+	seen  map[string]bool
+}
+
+func (u *uninitializedLogger) record() {
+	atomic.AddInt64(&u.calls, 1)
+
+	site := "unknown"
+	if frames := callerFrames(1); len(frames) > 0 {
+		site = fmt.Sprintf("%s (%s)", frames[0].Function, shortCaller(frames[0].File, frames[0].Line))
+	}
+	if u.panicOnUse {
+		panic("logger: default logger used before SetDefaultLogger or Init, at " + site)
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if len(u.sites) < maxUninitializedCallSites && !u.seen[site] {
+		if u.seen == nil {
+			u.seen = make(map[string]bool)
+		}
+		u.seen[site] = true
+		u.sites = append(u.sites, site)
+	}
+}
+
+func (u *uninitializedLogger) report() UninitializedReport {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	sites := make([]string, len(u.sites))
+	copy(sites, u.sites)
+	return UninitializedReport{Calls: atomic.LoadInt64(&u.calls), CallSites: sites}
+}
+
+func (u *uninitializedLogger) Debug(string, ...Field) { u.record() }
+func (u *uninitializedLogger) Info(string, ...Field)  { u.record() }
+func (u *uninitializedLogger) Warn(string, ...Field)  { u.record() }
+func (u *uninitializedLogger) Error(string, ...Field) { u.record() }
+
+// Fatal records the call and exits, as Fatal always does
+func (u *uninitializedLogger) Fatal(string, ...Field) {
+	u.record()
+	os.Exit(1)
+}
+
+func (u *uninitializedLogger) WarnCode(string, string, ...Field)  { u.record() }
+func (u *uninitializedLogger) ErrorCode(string, string, ...Field) { u.record() }
+
+func (u *uninitializedLogger) With(...Field) Logger                             { return u }
+func (u *uninitializedLogger) WithContext(context.Context) Logger               { return u }
+func (u *uninitializedLogger) WithLazy(...Field) Logger                         { return u }
+func (u *uninitializedLogger) Named(string) Logger                              { return u }
+func (u *uninitializedLogger) WithContextExtractors(...ContextExtractor) Logger { return u }
+func (u *uninitializedLogger) ExcludeContextFields(...string) Logger            { return u }
+func (u *uninitializedLogger) PushFields(...Field) func()                       { return func() {} }
+
+func (u *uninitializedLogger) Flush() error       { return nil }
+func (u *uninitializedLogger) Close() error       { return nil }
+func (u *uninitializedLogger) SetLevel(Level)     {}
+func (u *uninitializedLogger) Enabled(Level) bool { return true }
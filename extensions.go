@@ -0,0 +1,113 @@
+package logger
+
+// The interfaces below are optional extensions of Logger, kept out of it
+// so that other implementations of Logger, such as mocks, keep compiling
+// as the package grows. Loggers created by New, MultiLogger and WithLazy
+// implement all of them. The functions of the same name use the
+// extension when the logger has it and fall back to plain Logger methods
+// otherwise.
+
+// CodeLogger is implemented by loggers with shorthands for logging with a
+// Code field attached, for statements that alerting rules match on
+type CodeLogger interface {
+	WarnCode(code, msg string, fields ...Field)
+	ErrorCode(code, msg string, fields ...Field)
+}
+
+// LazyLogger is implemented by loggers that can defer deriving a child
+type LazyLogger interface {
+	// WithLazy returns a logger equivalent to With(fields...) that only
+	// derives the child when an entry first passes the level check. Use
+	// it on hot paths where most derived loggers never write.
+	WithLazy(fields ...Field) Logger
+}
+
+// ContextFieldsConfigurer is implemented by loggers whose WithContext can
+// be configured per logger
+type ContextFieldsConfigurer interface {
+	// WithContextExtractors returns a logger whose WithContext uses the
+	// given extractors instead of the global ones. Loggers derived from
+	// it keep the set.
+	WithContextExtractors(extractors ...ContextExtractor) Logger
+
+	// ExcludeContextFields returns a logger whose WithContext drops the
+	// named fields after extraction. Loggers derived from it keep the
+	// exclusions, and further calls add to them.
+	ExcludeContextFields(names ...string) Logger
+}
+
+// NamedLogger is implemented by loggers that carry a dotted name
+type NamedLogger interface {
+	// Named returns a logger whose name is this logger's name and name
+	// joined by a dot. The name is written as a logger field and output
+	// volume is attributed to it, see EnableVolumeAccounting.
+	Named(name string) Logger
+}
+
+// FieldPusher is implemented by loggers that can carry fields for a scope
+// without deriving a new logger
+type FieldPusher interface {
+	// PushFields temporarily adds fields to every entry logged through
+	// this logger value until the returned undo function is called.
+	// Loggers derived with With or WithContext, before or after the
+	// push, are not affected. It is safe to push and log concurrently.
+	PushFields(fields ...Field) (undo func())
+}
+
+// WithLazy calls l.WithLazy, or l.With when l is not a LazyLogger
+func WithLazy(l Logger, fields ...Field) Logger {
+	if lazy, ok := l.(LazyLogger); ok {
+		return lazy.WithLazy(fields...)
+	}
+	return l.With(fields...)
+}
+
+// WithContextExtractors calls l.WithContextExtractors. A logger that is
+// not a ContextFieldsConfigurer is returned unchanged.
+func WithContextExtractors(l Logger, extractors ...ContextExtractor) Logger {
+	if c, ok := l.(ContextFieldsConfigurer); ok {
+		return c.WithContextExtractors(extractors...)
+	}
+	return l
+}
+
+// ExcludeContextFields calls l.ExcludeContextFields. A logger that is not
+// a ContextFieldsConfigurer is returned unchanged.
+func ExcludeContextFields(l Logger, names ...string) Logger {
+	if c, ok := l.(ContextFieldsConfigurer); ok {
+		return c.ExcludeContextFields(names...)
+	}
+	return l
+}
+
+// Named calls l.Named, or adds the name as a logger field when l is not a
+// NamedLogger
+func Named(l Logger, name string) Logger {
+	if named, ok := l.(NamedLogger); ok {
+		return named.Named(name)
+	}
+	return l.With(Field{Key: "logger", Value: name})
+}
+
+// PushFields calls l.PushFields. For a logger that is not a FieldPusher
+// nothing is pushed and undo does nothing.
+func PushFields(l Logger, fields ...Field) (undo func()) {
+	if pusher, ok := l.(FieldPusher); ok {
+		return pusher.PushFields(fields...)
+	}
+	return func() {}
+}
+
+// logCode logs with a Code field attached, through WarnCode or ErrorCode
+// when l is a CodeLogger
+func logCode(l Logger, level Level, code, msg string, fields []Field) {
+	c, ok := l.(CodeLogger)
+	switch {
+	case ok && level == WarnLevel:
+		c.WarnCode(code, msg, fields...)
+	case ok && level == ErrorLevel:
+		c.ErrorCode(code, msg, fields...)
+	default:
+		logAt(l, level, msg, withCode(code, fields)...)
+	}
+}
@@ -0,0 +1,71 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+// plainLogger has only the methods of Logger, like a mock written against
+// the interface would
+type plainLogger struct{ logger.Logger }
+
+func TestBuiltInLoggersImplementExtensions(t *testing.T) {
+	base := logger.New(logger.Config{Output: &bytes.Buffer{}})
+	loggers := map[string]logger.Logger{
+		"New":         base,
+		"MultiLogger": logger.MultiLogger(base),
+		"WithLazy":    logger.WithLazy(base),
+	}
+
+	for name, log := range loggers {
+		if _, ok := log.(logger.CodeLogger); !ok {
+			t.Errorf("%s: not a CodeLogger", name)
+		}
+		if _, ok := log.(logger.LazyLogger); !ok {
+			t.Errorf("%s: not a LazyLogger", name)
+		}
+		if _, ok := log.(logger.ContextFieldsConfigurer); !ok {
+			t.Errorf("%s: not a ContextFieldsConfigurer", name)
+		}
+		if _, ok := log.(logger.NamedLogger); !ok {
+			t.Errorf("%s: not a NamedLogger", name)
+		}
+		if _, ok := log.(logger.FieldPusher); !ok {
+			t.Errorf("%s: not a FieldPusher", name)
+		}
+	}
+}
+
+func TestExtensionFunctionsFallBackOnPlainLogger(t *testing.T) {
+	var buf bytes.Buffer
+	var log logger.Logger = plainLogger{logger.New(logger.Config{Output: &buf})}
+
+	if _, ok := log.(logger.NamedLogger); ok {
+		t.Fatal("plainLogger should only implement Logger")
+	}
+
+	logger.Named(log, "db").Info("named")
+	logger.WithLazy(log, logger.Field{Key: "lazy", Value: 1}).Info("lazy")
+	if got := logger.ExcludeContextFields(log, "user_id"); got != log {
+		t.Error("ExcludeContextFields should return a plain logger unchanged")
+	}
+	if got := logger.WithContextExtractors(log); got != log {
+		t.Error("WithContextExtractors should return a plain logger unchanged")
+	}
+	undo := logger.PushFields(log, logger.Field{Key: "pushed", Value: 1})
+	log.Info("after push")
+	undo()
+
+	out := buf.String()
+	for _, want := range []string{"logger=db", "lazy=1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "pushed") {
+		t.Errorf("PushFields on a plain logger should push nothing:\n%s", out)
+	}
+}
@@ -3,10 +3,14 @@ package logger
 import (
 	"io"
 	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
 )
 
 type LoggerFactory struct {
 	defaultConfig Config
+	fs            FileSystem
 }
 
 func NewFactory(defaultConfig Config) *LoggerFactory {
@@ -18,15 +22,73 @@ func NewFactory(defaultConfig Config) *LoggerFactory {
 // Global factory instance
 var DefaultFactory = NewFactory(DefaultConfig)
 
+// UTC returns a factory creating loggers like f whose timestamps are in
+// UTC
+func (f *LoggerFactory) UTC() *LoggerFactory {
+	next := *f
+	next.defaultConfig.UTC = true
+	return &next
+}
+
+// TimeFormat returns a factory creating loggers like f with timestamps in
+// format, a preset such as TimeFormatRFC3339Nano or a layout
+func (f *LoggerFactory) TimeFormat(format string) *LoggerFactory {
+	next := *f
+	next.defaultConfig.TimeFormat = format
+	return &next
+}
+
+// FileSystem returns a factory creating loggers like f whose files are
+// opened on fsys, for example an in-memory filesystem in tests
+func (f *LoggerFactory) FileSystem(fsys FileSystem) *LoggerFactory {
+	next := *f
+	next.fs = fsys
+	return &next
+}
+
 func (f *LoggerFactory) Console(level Level) Logger {
 	cfg := f.defaultConfig
 	cfg.Level = level
 	cfg.Output = os.Stdout
+	cfg.Destination = DestinationConsole
+	return New(cfg)
+}
+
+// Dev returns a debug level logger writing FormatConsole entries to
+// standard output, for local development
+func (f *LoggerFactory) Dev() Logger {
+	cfg := f.defaultConfig
+	cfg.Level = DebugLevel
+	cfg.Output = os.Stdout
+	cfg.Destination = DestinationConsole
+	cfg.Format = FormatConsole
+	return New(cfg)
+}
+
+// DevJSON returns a debug level logger writing indented FormatJSON
+// entries to standard output, for reading structured logs during local
+// development. Size limits of the factory's configuration are dropped,
+// since PrettyJSON does not support them.
+func (f *LoggerFactory) DevJSON() Logger {
+	cfg := f.defaultConfig
+	cfg.Level = DebugLevel
+	cfg.Output = os.Stdout
+	cfg.Destination = DestinationConsole
+	cfg.Format = FormatJSON
+	cfg.PrettyJSON = true
+	cfg.MaxEntryBytes = 0
+	cfg.MaxFieldLength = 0
 	return New(cfg)
 }
 
+// File returns a logger like the factory's others appending to the file
+// at filePath, as CreateFileLogger does
 func (f *LoggerFactory) File(filePath string, level Level) (Logger, error) {
-	return CreateFileLogger(filePath, level)
+	fsys := f.fs
+	if fsys == nil {
+		fsys = OSFileSystem
+	}
+	return createFileLogger(fsys, f.defaultConfig, filePath, level)
 }
 
 func (f *LoggerFactory) Custom(cfg Config) Logger {
@@ -44,35 +106,179 @@ func (f *LoggerFactory) Combined(filePath string, consoleLevel, fileLevel Level)
 	return MultiLogger(consoleLogger, fileLogger), nil
 }
 
-func (f *LoggerFactory) NewWriter(logger Logger, level Level) io.Writer {
-	return &logWriter{
+// NewWriter returns an io.Writer that logs everything written to it at the
+// given level. Every line is logged as a separate entry; options control
+// how lines are decorated and filtered. A line that a write leaves
+// unterminated is held until a later write ends it, so lines split
+// across writes, as exec.Cmd's pipe copying does, stay whole. Close, or
+// Flush, logs what is still held once the writer is done with.
+func (f *LoggerFactory) NewWriter(logger Logger, level Level, opts ...WriterOption) io.WriteCloser {
+	w := &logWriter{
 		logger: logger,
 		level:  level,
 	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if len(w.fields) > 0 {
+		w.logger = w.logger.With(w.fields...)
+	}
+	return w
+}
+
+// WriterOption configures a writer created by NewWriter
+type WriterOption func(*logWriter)
+
+// WriterFields stamps the given fields on every line logged by the writer
+func WriterFields(fields ...Field) WriterOption {
+	return func(w *logWriter) {
+		w.fields = append(w.fields, fields...)
+	}
+}
+
+// WriterTrimSpace removes leading and trailing whitespace from each line
+func WriterTrimSpace() WriterOption {
+	return func(w *logWriter) {
+		w.trimSpace = true
+	}
+}
+
+// WriterSkipBlankLines drops lines that are empty or only whitespace
+func WriterSkipBlankLines() WriterOption {
+	return func(w *logWriter) {
+		w.skipBlank = true
+	}
+}
+
+// WriterMaxLineLength truncates lines longer than n bytes, appending a
+// marker. Zero means unlimited.
+func WriterMaxLineLength(n int) WriterOption {
+	return func(w *logWriter) {
+		w.maxLineLength = n
+	}
+}
+
+// CommandWriters returns writers suitable for exec.Cmd's Stdout and Stderr.
+// Lines are tagged with proc=name and stream=stdout or stream=stderr,
+// trimmed, and blank lines are dropped. Standard output is logged at Info
+// level and standard error at Warn level. Close both after the command
+// has finished to log a last line without a trailing newline.
+func CommandWriters(l Logger, name string) (stdout, stderr io.WriteCloser) {
+	stdout = DefaultFactory.NewWriter(l, InfoLevel,
+		WriterFields(Field{Key: "proc", Value: name}, Field{Key: "stream", Value: "stdout"}),
+		WriterTrimSpace(),
+		WriterSkipBlankLines(),
+	)
+	stderr = DefaultFactory.NewWriter(l, WarnLevel,
+		WriterFields(Field{Key: "proc", Value: name}, Field{Key: "stream", Value: "stderr"}),
+		WriterTrimSpace(),
+		WriterSkipBlankLines(),
+	)
+	return stdout, stderr
+}
+
+// WriterJoinLines logs each write as a single entry rather than one entry
+// per line, for libraries writing multi-line blocks such as stack traces
+// in one call. The logger's Config.Multiline policy then decides how the
+// line breaks are written.
+func WriterJoinLines() WriterOption {
+	return func(w *logWriter) {
+		w.joinLines = true
+	}
 }
 
+// truncatedMarker is appended to lines cut by WriterMaxLineLength
+const truncatedMarker = "...(truncated)"
+
+// maxPendingLine bounds the unterminated line a writer holds; a longer
+// one is logged as it is
+const maxPendingLine = 64 << 10
+
 type logWriter struct {
-	logger Logger
-	level  Level
+	logger        Logger
+	level         Level
+	fields        []Field
+	trimSpace     bool
+	skipBlank     bool
+	maxLineLength int
+	joinLines     bool
+
+	mu      sync.Mutex
+	pending string // the unterminated end of the previous writes
 }
 
 func (w *logWriter) Write(p []byte) (n int, err error) {
-	msg := string(p)
-
-	switch w.level {
-	case DebugLevel:
-		w.logger.Debug(msg)
-	case InfoLevel:
-		w.logger.Info(msg)
-	case WarnLevel:
-		w.logger.Warn(msg)
-	case ErrorLevel:
-		w.logger.Error(msg)
-	case FatalLevel:
-		w.logger.Fatal(msg)
-	default:
-		w.logger.Info(msg)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.joinLines {
+		// A terminating newline does not start another line
+		w.emit(strings.TrimSuffix(string(p), "\n"))
+		return len(p), nil
 	}
 
+	data := w.pending + string(p)
+	w.pending = ""
+	for {
+		i := strings.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(data[:i])
+		data = data[i+1:]
+	}
+	if len(data) >= maxPendingLine {
+		w.emit(data)
+		data = ""
+	}
+	w.pending = data
 	return len(p), nil
 }
+
+// Flush logs the unterminated line held from previous writes, if any
+func (w *logWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.pending != "" {
+		w.emit(w.pending)
+		w.pending = ""
+	}
+	return nil
+}
+
+// Close logs the unterminated line held from previous writes. The writer
+// remains usable.
+func (w *logWriter) Close() error {
+	return w.Flush()
+}
+
+// emit logs one line, applying the writer's options
+func (w *logWriter) emit(line string) {
+	if w.trimSpace {
+		line = strings.TrimSpace(line)
+	}
+	if w.skipBlank && strings.TrimSpace(line) == "" {
+		return
+	}
+	if w.maxLineLength > 0 && len(line) > w.maxLineLength {
+		line = truncateString(line, w.maxLineLength) + truncatedMarker
+	}
+	w.log(line)
+}
+
+func (w *logWriter) log(msg string) {
+	logAt(w.logger, w.level, msg)
+}
+
+// truncateString shortens s to at most n bytes without splitting a UTF-8
+// encoded rune
+func truncateString(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,146 @@
+package logger_test
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestNewWriterSplitsLines(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+	w := logger.DefaultFactory.NewWriter(log, logger.InfoLevel)
+
+	fmt.Fprint(w, "first line\nsecond line\n")
+
+	output := buf.String()
+	if strings.Count(output, "[INFO]") != 2 {
+		t.Errorf("Expected two entries, got: %s", output)
+	}
+	if !strings.Contains(output, "[INFO] first line") || !strings.Contains(output, "[INFO] second line") {
+		t.Errorf("Expected each line as its own entry, got: %s", output)
+	}
+}
+
+func TestNewWriterOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []logger.WriterOption
+		input    string
+		expected []string
+	}{
+		{
+			name:     "static fields",
+			opts:     []logger.WriterOption{logger.WriterFields(logger.Field{Key: "proc", Value: "ffmpeg"})},
+			input:    "frame=1\n",
+			expected: []string{"[INFO] frame=1 {proc=ffmpeg}"},
+		},
+		{
+			name:     "trim space",
+			opts:     []logger.WriterOption{logger.WriterTrimSpace()},
+			input:    "   padded\t\n",
+			expected: []string{"[INFO] padded \n"},
+		},
+		{
+			name:     "skip blank lines",
+			opts:     []logger.WriterOption{logger.WriterSkipBlankLines()},
+			input:    "one\n\n   \ntwo\n",
+			expected: []string{"[INFO] one", "[INFO] two"},
+		},
+		{
+			name:     "max line length",
+			opts:     []logger.WriterOption{logger.WriterMaxLineLength(5)},
+			input:    "0123456789\n",
+			expected: []string{"[INFO] 01234...(truncated)"},
+		},
+		{
+			name:     "max line length keeps runes intact",
+			opts:     []logger.WriterOption{logger.WriterMaxLineLength(2)},
+			input:    "héllo\n",
+			expected: []string{"[INFO] h...(truncated)"},
+		},
+		{
+			name:     "join lines",
+			opts:     []logger.WriterOption{logger.WriterJoinLines()},
+			input:    "panic: boom\n\ngoroutine 1\n",
+			expected: []string{`[INFO] panic: boom\n\ngoroutine 1 ` + "\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := logger.New(logger.Config{Output: &buf})
+			w := logger.DefaultFactory.NewWriter(log, logger.InfoLevel, tt.opts...)
+
+			fmt.Fprint(w, tt.input)
+
+			output := buf.String()
+			if strings.Count(output, "[INFO]") != len(tt.expected) {
+				t.Errorf("Expected %d entries, got: %s", len(tt.expected), output)
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(output, expected) {
+					t.Errorf("Expected log output to contain %q, got: %s", expected, output)
+				}
+			}
+		})
+	}
+}
+
+func TestCommandWriters(t *testing.T) {
+	if _, err := exec.LookPath("echo"); err != nil {
+		t.Skip("echo not available")
+	}
+
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+	stdout, stderr := logger.CommandWriters(log, "echo")
+
+	cmd := exec.Command("echo", "  hello from echo  ")
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to run command: %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "[INFO] hello from echo {proc=echo stream=stdout}") {
+		t.Errorf("Expected command output to be logged with static fields, got: %s", output)
+	}
+	if strings.Count(output, "[INFO]") != 1 {
+		t.Errorf("Expected exactly one entry, got: %s", output)
+	}
+}
+
+func TestNewWriterJoinsLinesSplitAcrossWrites(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+	w := logger.DefaultFactory.NewWriter(log, logger.WarnLevel, logger.WriterFields(logger.Field{Key: "stream", Value: "stderr"}))
+
+	// exec.Cmd copies a pipe in chunks that need not end on a line
+	fmt.Fprint(w, "first line\nerror: connection re")
+	fmt.Fprint(w, "fused by upstream\ntrailing without newline")
+
+	output := buf.String()
+	if strings.Count(output, "[WARN]") != 2 || !strings.Contains(output, "[WARN] error: connection refused by upstream {stream=stderr}") {
+		t.Errorf("Expected the split line logged whole, got: %s", output)
+	}
+	if strings.Contains(output, "trailing") {
+		t.Errorf("Expected an unterminated line to be held, got: %s", output)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "[WARN] trailing without newline {stream=stderr}") {
+		t.Errorf("Expected Close to log the held line, got: %s", buf.String())
+	}
+	if err := w.Close(); err != nil || strings.Count(buf.String(), "trailing") != 1 {
+		t.Errorf("Expected a second Close to log nothing, got %v: %s", err, buf.String())
+	}
+}
@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// FatalPolicy selects what happens to entries logged from other goroutines
+// once a Fatal entry has started the exit sequence
+type FatalPolicy int
+
+const (
+	// FatalBlock makes concurrent calls wait for the process to exit, up
+	// to Config.FatalTimeout, so they cannot interleave with the final
+	// entry. Calls still waiting when the timeout expires are dropped.
+	FatalBlock FatalPolicy = iota
+	// FatalDrop makes concurrent calls return immediately without
+	// writing. The number dropped before the Fatal entry is written is
+	// reported in its dropped_after_fatal field.
+	FatalDrop
+)
+
+// DefaultFatalTimeout bounds how long FatalBlock holds concurrent calls
+const DefaultFatalTimeout = time.Second
+
+// fatalGate is shared by a logger created by New and everything derived
+// from it. Until a Fatal entry starts, the emission path only loads
+// closing.
+type fatalGate struct {
+	closing int32
+	dropped int64
+	// exited is closed if the exit function returns, which only happens
+	// when it has been replaced, to release blocked callers. The gate
+	// stays closed, so from then on every call is dropped immediately.
+	exited chan struct{}
+}
+
+func newFatalGate() *fatalGate {
+	return &fatalGate{exited: make(chan struct{})}
+}
+
+// closed reports whether a Fatal entry has started the exit sequence
+func (g *fatalGate) closed() bool {
+	return atomic.LoadInt32(&g.closing) != 0
+}
+
+// hold applies the policy to a call made after the gate closed
+func (g *fatalGate) hold(policy FatalPolicy, timeout time.Duration) {
+	if policy == FatalBlock {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case <-g.exited:
+		case <-timer.C:
+		}
+	}
+	atomic.AddInt64(&g.dropped, 1)
+}
+
+// fatal writes the final entry and exits. Only the first Fatal call runs
+// the sequence; later ones are treated like any other concurrent call.
+func (l *standardLogger) fatal(msg string, fields []Field) {
+	if !atomic.CompareAndSwapInt32(&l.gate.closing, 0, 1) {
+		l.gate.hold(l.fatalPolicy, l.fatalTimeout)
+		return
+	}
+
+	if l.fatalPolicy == FatalDrop {
+		if n := atomic.LoadInt64(&l.gate.dropped); n > 0 {
+			fields = append(fields[:len(fields):len(fields)], Field{Key: "dropped_after_fatal", Value: n})
+		}
+	}
+	l.write(FatalLevel, msg, fields)
+
+	l.exit(1)
+	close(l.gate.exited)
+}
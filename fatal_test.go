@@ -0,0 +1,154 @@
+package logger_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+// fatalRun logs Fatal while writers log concurrently from other
+// goroutines, and returns the output and the exit codes requested
+func fatalRun(t *testing.T, policy logger.FatalPolicy, timeout time.Duration) (string, []int) {
+	t.Helper()
+
+	var buf syncBuffer
+	var mu sync.Mutex
+	var codes []int
+	fatalStarted := make(chan struct{})
+
+	log := logger.New(logger.Config{
+		Output:                 &buf,
+		FatalConcurrencyPolicy: policy,
+		FatalTimeout:           timeout,
+		ExitFunc: func(code int) {
+			mu.Lock()
+			codes = append(codes, code)
+			mu.Unlock()
+			// Give concurrent writers time to hit the gate before "exiting"
+			time.Sleep(20 * time.Millisecond)
+		},
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-fatalStarted
+			for j := 0; j < 50; j++ {
+				log.With(logger.Field{Key: "writer", Value: true}).Info("after fatal")
+			}
+		}()
+	}
+
+	log.Info("before fatal")
+	close(fatalStarted)
+	log.Fatal("shutting down", logger.Field{Key: "reason", Value: "test"})
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	return buf.String(), codes
+}
+
+func TestFatalBlocksConcurrentWriters(t *testing.T) {
+	output, codes := fatalRun(t, logger.FatalBlock, time.Second)
+
+	if len(codes) != 1 || codes[0] != 1 {
+		t.Fatalf("Expected a single exit with status 1, got %v", codes)
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	last := lines[len(lines)-1]
+	if !strings.Contains(last, "[FATAL] shutting down {reason=test}") {
+		t.Errorf("Expected the fatal entry to be the last line, got: %s", last)
+	}
+}
+
+func TestFatalBlockTimeout(t *testing.T) {
+	var buf syncBuffer
+	release := make(chan struct{})
+	log := logger.New(logger.Config{
+		Output:       &buf,
+		FatalTimeout: 10 * time.Millisecond,
+		ExitFunc:     func(int) { <-release },
+	})
+
+	go log.Fatal("stuck exit")
+	for !strings.Contains(buf.String(), "stuck exit") {
+		time.Sleep(time.Millisecond)
+	}
+
+	start := time.Now()
+	log.Info("blocked")
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond || elapsed > time.Second {
+		t.Errorf("Expected the call to be held for the timeout, took %v", elapsed)
+	}
+	if strings.Contains(buf.String(), "blocked") {
+		t.Errorf("Expected the held entry to be dropped, got: %s", buf.String())
+	}
+	close(release)
+}
+
+func TestFatalDropCountsEntries(t *testing.T) {
+	var buf syncBuffer
+	exited := make(chan struct{})
+	log := logger.New(logger.Config{
+		Output:                 &buf,
+		FatalConcurrencyPolicy: logger.FatalDrop,
+		ExitFunc:               func(int) { close(exited) },
+	})
+
+	log.Fatal("first fatal")
+	<-exited
+
+	start := time.Now()
+	log.Info("dropped")
+	log.Fatal("second fatal")
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("Expected dropped calls to return immediately, took %v", elapsed)
+	}
+	if strings.Contains(buf.String(), "dropped") || strings.Contains(buf.String(), "second fatal") {
+		t.Errorf("Expected calls after Fatal to be dropped, got: %s", buf.String())
+	}
+
+	output, codes := fatalRun(t, logger.FatalDrop, time.Second)
+	if len(codes) != 1 {
+		t.Fatalf("Expected a single exit, got %v", codes)
+	}
+	if strings.Count(output, "[FATAL]") != 1 {
+		t.Errorf("Expected exactly one fatal entry, got: %s", output)
+	}
+}
+
+func TestLoggingAfterReturningExitFunc(t *testing.T) {
+	for _, policy := range []logger.FatalPolicy{logger.FatalBlock, logger.FatalDrop} {
+		var buf syncBuffer
+		log := logger.New(logger.Config{
+			Output:                 &buf,
+			FatalConcurrencyPolicy: policy,
+			FatalTimeout:           time.Minute,
+			ExitFunc:               func(int) {},
+		})
+		derived := log.With(logger.Field{Key: "k", Value: "v"})
+
+		log.Fatal("fatal")
+		start := time.Now()
+		log.Error("after")
+		derived.Info("derived after")
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("policy %d: expected calls after Fatal to return immediately, took %v", policy, elapsed)
+		}
+		if output := buf.String(); strings.Contains(output, "after") || !strings.Contains(output, "[FATAL] fatal") {
+			t.Errorf("policy %d: expected only the fatal entry, got: %s", policy, output)
+		}
+
+		var fresh syncBuffer
+		logger.New(logger.Config{Output: &fresh}).Info("new logger")
+		if !strings.Contains(fresh.String(), "new logger") {
+			t.Errorf("policy %d: expected a new logger to write, got: %s", policy, fresh.String())
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package logger
+
+import "time"
+
+// groupValue is the value of fields created with Group
+type groupValue []Field
+
+// Group returns a field that namespaces the given fields under name. Text
+// output renders grouped fields as name.key=value; groups can be nested.
+func Group(name string, fields ...Field) Field {
+	return Field{Key: name, Value: groupValue(fields)}
+}
+
+// visibleValue restricts a field to one kind of destination
+type visibleValue struct {
+	value any
+	dest  Destination
+}
+
+// FileOnly marks a field to be written only by loggers whose Destination
+// is DestinationFile or DestinationAny
+func FileOnly(f Field) Field {
+	return Field{Key: f.Key, Value: visibleValue{value: f.Value, dest: DestinationFile}}
+}
+
+// ConsoleOnly marks a field to be written only by loggers whose
+// Destination is DestinationConsole or DestinationAny
+func ConsoleOnly(f Field) Field {
+	return Field{Key: f.Key, Value: visibleValue{value: f.Value, dest: DestinationConsole}}
+}
+
+// timeSpan is the value of fields created with Since and Until. It is
+// resolved into a time.Duration when the entry is written.
+type timeSpan struct {
+	t     time.Time
+	until bool
+}
+
+// Since returns a field whose value is the time elapsed since t, measured
+// when the entry is written. A zero t omits the field.
+func Since(key string, t time.Time) Field {
+	return Field{Key: key, Value: timeSpan{t: t}}
+}
+
+// Until returns a field whose value is the time remaining until t,
+// measured when the entry is written. A zero t omits the field.
+func Until(key string, t time.Time) Field {
+	return Field{Key: key, Value: timeSpan{t: t, until: true}}
+}
+
+// resolveFields replaces values that are evaluated at emission time with
+// their current value. The input slice is not modified.
+func (l *standardLogger) resolveFields(fields []Field, now time.Time) []Field {
+	if !hasDeferredValues(fields) {
+		return fields
+	}
+
+	resolved := make([]Field, 0, len(fields))
+	for _, field := range fields {
+		if visible, ok := field.Value.(visibleValue); ok {
+			if l.dest != DestinationAny && visible.dest != l.dest {
+				continue
+			}
+			field.Value = visible.value
+		}
+
+		if provider, ok := field.Value.(providedValue); ok {
+			field.Value = l.evaluateProvider(provider)
+		}
+
+		if flat, ok := field.Value.(flattenValue); ok {
+			field.Value = flatten(flat.value)
+		}
+
+		if group, ok := field.Value.(groupValue); ok && hasDeferredValues(group) {
+			// Groups are shared with the loggers that hold them;
+			// resolveFields returns a new slice
+			field.Value = groupValue(l.resolveFields(group, now))
+		}
+
+		if code, ok := field.Value.(codeValue); ok {
+			resolved = l.appendCode(resolved, field.Key, code)
+			continue
+		}
+
+		if ev, ok := field.Value.(errorValue); ok {
+			resolved = l.appendError(resolved, field.Key, ev.err)
+			continue
+		}
+
+		span, ok := field.Value.(timeSpan)
+		if !ok {
+			resolved = append(resolved, field)
+			continue
+		}
+		if span.t.IsZero() {
+			continue
+		}
+
+		d := now.Sub(span.t)
+		if span.until {
+			d = span.t.Sub(now)
+		}
+		resolved = append(resolved, Field{Key: field.Key, Value: d})
+		if l.spanTimes {
+			resolved = append(resolved, Field{Key: field.Key + "_at", Value: formatTime(span.t, l.timeFormat)})
+		}
+	}
+	return resolved
+}
+
+// hasDeferredValues reports whether any field, including those in
+// groups, needs resolving at emission
+func hasDeferredValues(fields []Field) bool {
+	for _, field := range fields {
+		switch v := field.Value.(type) {
+		case timeSpan, visibleValue, providedValue, codeValue, flattenValue, errorValue:
+			return true
+		case groupValue:
+			if hasDeferredValues(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
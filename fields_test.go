@@ -0,0 +1,210 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func fixedClock(t time.Time) func() time.Time {
+	return func() time.Time { return t }
+}
+
+func TestSinceAndUntil(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		cfg      logger.Config
+		field    logger.Field
+		expected string
+		absent   string
+	}{
+		{
+			name:     "since",
+			field:    logger.Since("age", now.Add(-90*time.Second)),
+			expected: "{age=1m30s}",
+		},
+		{
+			name:     "until",
+			field:    logger.Until("expires_in", now.Add(2*time.Hour)),
+			expected: "{expires_in=2h0m0s}",
+		},
+		{
+			name:   "zero time is omitted",
+			field:  logger.Since("age", time.Time{}),
+			absent: "age=",
+		},
+		{
+			name:     "companion timestamp",
+			cfg:      logger.Config{SpanTimestamps: true},
+			field:    logger.Since("age", now.Add(-time.Minute)),
+			expected: "{age=1m0s age_at=2024-03-01T11:59:00Z}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.cfg.Output = &buf
+			tt.cfg.Clock = fixedClock(now)
+			log := logger.New(tt.cfg)
+
+			log.Info("span", tt.field)
+
+			output := buf.String()
+			if tt.expected != "" && !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected log output to contain %q, got: %s", tt.expected, output)
+			}
+			if tt.absent != "" && strings.Contains(output, tt.absent) {
+				t.Errorf("Expected log output not to contain %q, got: %s", tt.absent, output)
+			}
+		})
+	}
+}
+
+func TestSinceEvaluatedAtEmission(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{
+		Output: &buf,
+		Clock:  func() time.Time { return now },
+	}).With(logger.Since("elapsed", start))
+
+	now = start.Add(time.Second)
+	log.Info("first")
+	now = start.Add(3 * time.Second)
+	log.Info("second")
+
+	output := buf.String()
+	if !strings.Contains(output, "first {elapsed=1s}") || !strings.Contains(output, "second {elapsed=3s}") {
+		t.Errorf("Expected elapsed time to be measured per entry, got: %s", output)
+	}
+}
+
+func TestGroup(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf}).With(
+		logger.Group("service", logger.Field{Key: "name", Value: "api"}),
+	)
+
+	log.Info("grouped",
+		logger.Group("db",
+			logger.Field{Key: "table", Value: "users"},
+			logger.Group("pool", logger.Field{Key: "size", Value: 4}),
+		),
+		logger.Field{Key: "plain", Value: true},
+	)
+
+	expected := "{service.name=api db.table=users db.pool.size=4 plain=true}"
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("Expected log output to contain %q, got: %s", expected, buf.String())
+	}
+}
+
+func TestGroupResolvesDeferredValues(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{
+		Output:      &buf,
+		Format:      logger.FormatJSON,
+		Clock:       fixedClock(now),
+		Destination: logger.DestinationConsole,
+	}).With(logger.Group("g",
+		logger.Err(errors.New("boom")),
+		logger.Since("age", now.Add(-time.Minute)),
+		logger.Code("E_GROUP"),
+		logger.Provided("calls", func() any { return 7 }),
+		logger.Flatten("user", flatUser{ID: 42}),
+		logger.FileOnly(logger.Field{Key: "secret", Value: "x"}),
+		logger.Group("inner", logger.Until("ttl", now.Add(time.Hour))),
+	))
+	defer log.Close()
+
+	// Twice, to check the group held by the logger is left unresolved
+	log.Info("first")
+	log.Info("second")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected two entries, got: %s", buf.String())
+	}
+	for _, line := range lines {
+		group, ok := decodeEntry(t, line)["g"].(map[string]any)
+		if !ok {
+			t.Fatalf("Expected a g object, got: %s", line)
+		}
+		expected := map[string]any{
+			"error":      "boom",
+			"error.type": "*errors.errorString",
+			"age":        "1m0s",
+			"code":       "E_GROUP",
+			"calls":      float64(7),
+			"inner":      map[string]any{"ttl": "1h0m0s"},
+		}
+		for key, want := range expected {
+			if !reflect.DeepEqual(group[key], want) {
+				t.Errorf("Expected g.%s to be %v, got %v in: %s", key, want, group[key], line)
+			}
+		}
+		if _, ok := group["user"].(map[string]any); !ok {
+			t.Errorf("Expected g.user to be flattened, got: %s", line)
+		}
+		if _, ok := group["secret"]; ok {
+			t.Errorf("Expected the file-only field to be dropped on the console, got: %s", line)
+		}
+	}
+}
+
+func TestFieldVisibility(t *testing.T) {
+	var console, file, plain bytes.Buffer
+	log := logger.MultiLogger(
+		logger.New(logger.Config{Output: &console, Destination: logger.DestinationConsole}),
+		logger.New(logger.Config{Output: &file, Destination: logger.DestinationFile}),
+		logger.New(logger.Config{Output: &plain}),
+	).With(logger.FileOnly(logger.Field{Key: "stack", Value: "main.go:1"}))
+
+	log.Info("visibility",
+		logger.ConsoleOnly(logger.Field{Key: "hint", Value: "retry"}),
+		logger.Field{Key: "shared", Value: 1},
+	)
+
+	if output := console.String(); !strings.Contains(output, "{hint=retry shared=1}") {
+		t.Errorf("Expected console output without file-only fields, got: %s", output)
+	}
+	if output := file.String(); !strings.Contains(output, "{stack=main.go:1 shared=1}") {
+		t.Errorf("Expected file output without console-only fields, got: %s", output)
+	}
+	if output := plain.String(); !strings.Contains(output, "{stack=main.go:1 hint=retry shared=1}") {
+		t.Errorf("Expected plain output to include every field, got: %s", output)
+	}
+}
+
+func TestFieldVisibilityCombined(t *testing.T) {
+	path := t.TempDir() + "/combined.log"
+	log, err := logger.NewFactory(logger.DefaultConfig).Combined(path, logger.InfoLevel, logger.InfoLevel)
+	if err != nil {
+		t.Fatalf("Failed to create combined logger: %v", err)
+	}
+
+	log.Info("visibility",
+		logger.FileOnly(logger.Field{Key: "payload_hash", Value: "abc"}),
+		logger.ConsoleOnly(logger.Field{Key: "hint", Value: "retry"}),
+	)
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(content), "{payload_hash=abc}") {
+		t.Errorf("Expected file to contain only the file field, got: %s", content)
+	}
+}
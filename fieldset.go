@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// FieldSet builds the same group of fields for many entries without
+// allocating a field slice each time. It is safe for concurrent use.
+//
+//	var loginFields = logger.NewFieldSet("user_id", "attempt", "latency_ms")
+//
+//	log.Info("login", loginFields.Values(id, n, ms))
+//
+// Values returns a single Field standing for all of the set's fields. It
+// is expanded where the entry is written, in place of the Field, and its
+// storage is recycled once the entry has been written. The result must
+// therefore be passed to exactly one logging call and not kept, copied
+// into another slice or given to With; Fields built inline have no such
+// restriction. In builds with the logger_debug tag recycled values are
+// poisoned instead, so a misused Field fails loudly on its next write.
+type FieldSet struct {
+	keys []string
+	pool sync.Pool
+}
+
+// fieldSetValues is the opaque value behind a FieldSet.Values Field. refs
+// counts the writes still to come: a multi logger fanning the entry out
+// retains one per extra destination, and each write releases one.
+type fieldSetValues struct {
+	set    *FieldSet
+	fields []Field
+	refs   int32
+}
+
+// poisonedFieldSet is the value written for fields of a recycled set in
+// debug builds
+const poisonedFieldSet = "<recycled FieldSet values>"
+
+// NewFieldSet returns a set producing fields with the given keys, in order
+func NewFieldSet(keys ...string) *FieldSet {
+	s := &FieldSet{keys: append([]string(nil), keys...)}
+	s.pool.New = func() any {
+		fields := make([]Field, len(s.keys))
+		for i, key := range s.keys {
+			fields[i].Key = key
+		}
+		return &fieldSetValues{set: s, fields: fields}
+	}
+	return s
+}
+
+// Keys returns the keys of the set
+func (s *FieldSet) Keys() []string {
+	return append([]string(nil), s.keys...)
+}
+
+// Values returns a Field carrying one value per key. Missing values are
+// written as nil and extra values are ignored. See FieldSet for the rules
+// on using the result.
+func (s *FieldSet) Values(values ...any) Field {
+	v := s.pool.Get().(*fieldSetValues)
+	for i := range v.fields {
+		v.fields[i].Key = s.keys[i]
+		if i < len(values) {
+			v.fields[i].Value = values[i]
+		} else {
+			v.fields[i].Value = nil
+		}
+	}
+	atomic.StoreInt32(&v.refs, 1)
+	return Field{Value: v}
+}
+
+// expand returns the fields the value stands for
+func (v *fieldSetValues) expand() []Field {
+	if poisonFieldSets && atomic.LoadInt32(&v.refs) <= 0 {
+		panic("logger: FieldSet.Values result used after its entry was written")
+	}
+	return v.fields
+}
+
+// retain adds n pending writes
+func (v *fieldSetValues) retain(n int) {
+	atomic.AddInt32(&v.refs, int32(n))
+}
+
+// release ends one write, recycling the value after the last
+func (v *fieldSetValues) release() {
+	if atomic.AddInt32(&v.refs, -1) != 0 {
+		return
+	}
+	if poisonFieldSets {
+		// Never reuse, so any later write sees the poison
+		for i := range v.fields {
+			v.fields[i].Value = poisonedFieldSet
+		}
+		return
+	}
+	for i := range v.fields {
+		v.fields[i].Value = nil
+	}
+	v.set.pool.Put(v)
+}
+
+// expandedLen returns the number of fields after expanding FieldSet values
+func expandedLen(fields []Field) int {
+	n := len(fields)
+	for _, field := range fields {
+		if v, ok := field.Value.(*fieldSetValues); ok {
+			n += len(v.fields) - 1
+		}
+	}
+	return n
+}
+
+// appendExpanded appends fields to dst with FieldSet values expanded
+func appendExpanded(dst, fields []Field) []Field {
+	for _, field := range fields {
+		if v, ok := field.Value.(*fieldSetValues); ok {
+			dst = append(dst, v.expand()...)
+			continue
+		}
+		dst = append(dst, field)
+	}
+	return dst
+}
+
+// retainFieldSets adds n pending writes to every FieldSet value in fields
+func retainFieldSets(fields []Field, n int) {
+	if n <= 0 {
+		return
+	}
+	for _, field := range fields {
+		if v, ok := field.Value.(*fieldSetValues); ok {
+			v.retain(n)
+		}
+	}
+}
+
+// releaseFieldSets ends one write for every FieldSet value in fields
+func releaseFieldSets(fields []Field) {
+	for _, field := range fields {
+		if v, ok := field.Value.(*fieldSetValues); ok {
+			v.release()
+		}
+	}
+}
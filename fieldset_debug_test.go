@@ -0,0 +1,23 @@
+//go:build logger_debug
+
+package logger_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestFieldSetReuseFailsInDebugBuilds(t *testing.T) {
+	log := logger.New(logger.Config{Output: io.Discard})
+	field := loginFields.Values("u1", 1, 1)
+	log.Info("first", field)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected reusing a written FieldSet value to panic")
+		}
+	}()
+	log.Info("second", field)
+}
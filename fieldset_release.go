@@ -0,0 +1,6 @@
+//go:build !logger_debug
+
+package logger
+
+// poisonFieldSets makes recycled FieldSet values fail loudly when reused
+const poisonFieldSets = false
@@ -0,0 +1,119 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+var loginFields = logger.NewFieldSet("user_id", "attempt", "latency_ms")
+
+func TestFieldSetValues(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Format: logger.FormatJSON, Output: &buf})
+
+	log.Info("login", loginFields.Values("u1", 2, 15), logger.Field{Key: "ok", Value: true})
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON, got %q: %v", buf.String(), err)
+	}
+	if entry["user_id"] != "u1" || entry["attempt"] != float64(2) || entry["latency_ms"] != float64(15) || entry["ok"] != true {
+		t.Errorf("Expected the set expanded next to other fields, got %v", entry)
+	}
+	if strings.Index(buf.String(), `"user_id"`) > strings.Index(buf.String(), `"ok"`) {
+		t.Errorf("Expected the set's fields in place of its Field, got %s", buf.String())
+	}
+}
+
+func TestFieldSetMissingAndExtraValues(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+
+	log.Info("short", loginFields.Values("u1"))
+	if !strings.Contains(buf.String(), "attempt=<nil>") {
+		t.Errorf("Expected missing values written as nil, got %s", buf.String())
+	}
+
+	buf.Reset()
+	log.Info("long", loginFields.Values("u1", 1, 2, "extra"))
+	if strings.Contains(buf.String(), "extra") {
+		t.Errorf("Expected extra values ignored, got %s", buf.String())
+	}
+}
+
+func TestFieldSetRecycledBetweenEntries(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+
+	for i := 0; i < 3; i++ {
+		log.Info("login", loginFields.Values(fmt.Sprintf("u%d", i), i, i*10))
+	}
+	for i := 0; i < 3; i++ {
+		if !strings.Contains(buf.String(), fmt.Sprintf("user_id=u%d attempt=%d", i, i)) {
+			t.Errorf("Expected entry %d with its own values, got %s", i, buf.String())
+		}
+	}
+}
+
+func TestFieldSetFilteredEntry(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Level: logger.WarnLevel, Output: &buf})
+
+	log.Debug("filtered", loginFields.Values("u1", 1, 1))
+	log.Warn("written", loginFields.Values("u2", 2, 2))
+	if !strings.Contains(buf.String(), "user_id=u2") || strings.Contains(buf.String(), "u1") {
+		t.Errorf("Expected only the written entry, got %s", buf.String())
+	}
+}
+
+func TestFieldSetMultiLogger(t *testing.T) {
+	var first, second bytes.Buffer
+	log := logger.MultiLogger(
+		logger.New(logger.Config{Output: &first}),
+		logger.New(logger.Config{Output: &second}),
+	)
+
+	log.Info("login", loginFields.Values("u1", 1, 5))
+	log.Info("login", loginFields.Values("u2", 2, 6))
+	for name, buf := range map[string]*bytes.Buffer{"first": &first, "second": &second} {
+		if !strings.Contains(buf.String(), "user_id=u1 attempt=1") || !strings.Contains(buf.String(), "user_id=u2 attempt=2") {
+			t.Errorf("Expected both entries in the %s output, got %s", name, buf.String())
+		}
+	}
+}
+
+func TestFieldSetConcurrent(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Format: logger.FormatJSON, Output: &buf})
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				log.Info("login", loginFields.Values(fmt.Sprintf("u%d", g), g, g))
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry struct {
+			UserID  string `json:"user_id"`
+			Attempt int    `json:"attempt"`
+		}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Expected valid JSON, got %q: %v", line, err)
+		}
+		if entry.UserID != fmt.Sprintf("u%d", entry.Attempt) {
+			t.Fatalf("Expected values from one call, got %s", line)
+		}
+	}
+}
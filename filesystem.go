@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"io"
+	"os"
+)
+
+// File is a file opened for writing by a FileSystem. *os.File implements
+// it.
+type File interface {
+	io.WriteCloser
+	// Name returns the name the file was opened with
+	Name() string
+}
+
+// FileSystem is what file loggers use to create, move and remove their
+// files, so tests can replace the disk with an in-memory filesystem such
+// as logtest.MemFS and inject failures. Errors should be *os.PathError
+// values wrapping the cause, as the os package returns them, so callers
+// can test them with errors.Is.
+type FileSystem interface {
+	OpenFile(name string, flag int, perm os.FileMode) (File, error)
+	Rename(oldpath, newpath string) error
+	Remove(name string) error
+	Stat(name string) (os.FileInfo, error)
+	Symlink(oldname, newname string) error
+	MkdirAll(path string, perm os.FileMode) error
+}
+
+// OSFileSystem is the FileSystem of the operating system, used unless
+// another one is given
+var OSFileSystem FileSystem = osFileSystem{}
+
+type osFileSystem struct{}
+
+func (osFileSystem) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	file, err := os.OpenFile(name, flag, perm)
+	if err != nil {
+		// Keep a failed open from returning a non-nil File holding a nil
+		// *os.File
+		return nil, err
+	}
+	return file, nil
+}
+
+func (osFileSystem) Rename(oldpath, newpath string) error { return os.Rename(oldpath, newpath) }
+
+func (osFileSystem) Remove(name string) error { return os.Remove(name) }
+
+func (osFileSystem) Stat(name string) (os.FileInfo, error) { return os.Stat(name) }
+
+func (osFileSystem) Symlink(oldname, newname string) error { return os.Symlink(oldname, newname) }
+
+func (osFileSystem) MkdirAll(path string, perm os.FileMode) error { return os.MkdirAll(path, perm) }
@@ -0,0 +1,170 @@
+package logger
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MaxFlattenDepth is how many levels of maps and structs Flatten expands.
+// Deeper values are written whole, as their text.
+const MaxFlattenDepth = 8
+
+// flattenValue is the value of fields created with Flatten
+type flattenValue struct {
+	value any
+}
+
+// Flatten returns a field expanding value, a map or struct, into a group
+// with one field per map entry or exported struct field, so that text
+// output reads payload.user.id=7 and JSON output nests real objects.
+// Struct fields are named and skipped following their json tags, embedded
+// structs are inlined, and map keys are sorted. Values with their own text
+// form, such as errors, time.Time or fmt.Stringer implementations, are not
+// expanded. A value reached again while expanding itself is written as
+// <cycle>. Expansion happens only for entries that are written.
+func Flatten(key string, value any) Field {
+	return Field{Key: key, Value: flattenValue{value: value}}
+}
+
+// flatten expands v into a groupValue, or returns it unchanged if it is
+// not a map or struct
+func flatten(v any) any {
+	return flattenReflect(reflect.ValueOf(v), 0, map[visit]bool{})
+}
+
+func flattenReflect(v reflect.Value, depth int, visiting map[visit]bool) any {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		if v.Kind() == reflect.Ptr {
+			if hasOwnText(v) {
+				return v.Interface()
+			}
+			key, ok := enter(v, visiting)
+			if !ok {
+				return "<cycle>"
+			}
+			defer delete(visiting, key)
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Map, reflect.Struct:
+	default:
+		return v.Interface()
+	}
+	if hasOwnText(v) {
+		return v.Interface()
+	}
+	if depth >= MaxFlattenDepth {
+		s, _ := formatValue(v.Interface())
+		return s
+	}
+
+	if v.Kind() == reflect.Struct {
+		return flattenStruct(nil, v, depth, visiting)
+	}
+	if v.IsNil() {
+		return nil
+	}
+	key, ok := enter(v, visiting)
+	if !ok {
+		return "<cycle>"
+	}
+	defer delete(visiting, key)
+
+	group := make(groupValue, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		name, _ := formatValue(iter.Key().Interface())
+		group = append(group, Field{Key: name, Value: flattenReflect(iter.Value(), depth+1, visiting)})
+	}
+	sort.SliceStable(group, func(i, j int) bool { return group[i].Key < group[j].Key })
+	return group
+}
+
+// flattenStruct appends the exported fields of v to group
+func flattenStruct(group groupValue, v reflect.Value, depth int, visiting map[visit]bool) groupValue {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, omitEmpty, skip := jsonFieldName(sf)
+		if skip {
+			continue
+		}
+		fv := v.Field(i)
+
+		if sf.Anonymous && name == "" && !hasOwnText(fv) {
+			if inlined, ok := flattenEmbedded(group, fv, depth, visiting); ok {
+				group = inlined
+				continue
+			}
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		if omitEmpty && fv.IsZero() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		group = append(group, Field{Key: name, Value: flattenReflect(fv, depth+1, visiting)})
+	}
+	return group
+}
+
+// flattenEmbedded inlines the fields of an embedded struct, or pointer to
+// one, into group. It reports false for other embedded types.
+func flattenEmbedded(group groupValue, v reflect.Value, depth int, visiting map[visit]bool) (groupValue, bool) {
+	if v.Kind() == reflect.Ptr {
+		if v.Type().Elem().Kind() != reflect.Struct {
+			return group, false
+		}
+		if v.IsNil() || depth >= MaxFlattenDepth {
+			return group, true
+		}
+		key, ok := enter(v, visiting)
+		if !ok {
+			return group, true
+		}
+		defer delete(visiting, key)
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return group, false
+	}
+	return flattenStruct(group, v, depth+1, visiting), true
+}
+
+// jsonFieldName reads the json tag of a struct field
+func jsonFieldName(sf reflect.StructField) (name string, omitEmpty, skip bool) {
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	return name, strings.Contains(","+opts+",", ",omitempty,"), false
+}
+
+// hasOwnText reports whether v renders itself, in which case it is
+// written whole rather than expanded
+func hasOwnText(v reflect.Value) bool {
+	if !v.CanInterface() {
+		return false
+	}
+	switch v.Interface().(type) {
+	case error, fmt.Stringer, encoding.TextMarshaler, json.Marshaler:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+type flatUser struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name,omitempty"`
+	Password string `json:"-"`
+	Email    string
+	internal int
+}
+
+type flatAudit struct {
+	By string `json:"by"`
+}
+
+type flatPayload struct {
+	flatAudit
+	User   flatUser          `json:"user"`
+	Labels map[string]string `json:"labels"`
+	At     time.Time         `json:"at"`
+	Err    error             `json:"err"`
+}
+
+type flatNode struct {
+	Name string    `json:"name"`
+	Next *flatNode `json:"next"`
+}
+
+func TestFlatten(t *testing.T) {
+	payload := flatPayload{
+		flatAudit: flatAudit{By: "admin"},
+		User:      flatUser{ID: 7, Password: "secret", Email: "a@example.com", internal: 1},
+		Labels:    map[string]string{"b": "2", "a": "1"},
+		At:        time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		Err:       errors.New("boom"),
+	}
+
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{
+			name:     "struct",
+			value:    payload,
+			expected: `{payload.by=admin payload.user.id=7 payload.user.Email=a@example.com payload.labels.a=1 payload.labels.b=2 payload.at="2024-03-01 12:00:00 +0000 UTC" payload.err=boom}`,
+		},
+		{
+			name:     "pointer to struct",
+			value:    &flatUser{ID: 1, Name: "ann"},
+			expected: `{payload.id=1 payload.name=ann payload.Email=""}`,
+		},
+		{
+			name:     "nested maps",
+			value:    map[string]any{"db": map[string]int{"rows": 3}, "ok": true},
+			expected: "{payload.db.rows=3 payload.ok=true}",
+		},
+		{
+			name:     "scalar",
+			value:    42,
+			expected: "{payload=42}",
+		},
+		{
+			name:     "nil pointer",
+			value:    (*flatUser)(nil),
+			expected: "{payload=<nil>}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := logger.New(logger.Config{Output: &buf})
+
+			log.Info("flat", logger.Flatten("payload", tt.value))
+
+			if !strings.Contains(buf.String(), "flat "+tt.expected) {
+				t.Errorf("Expected fields %s, got: %s", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestFlattenJSONNests(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatJSON})
+
+	log.Info("flat", logger.Flatten("payload", flatPayload{User: flatUser{ID: 7}}))
+
+	entry := decodeEntry(t, buf.String())
+	payload, ok := entry["payload"].(map[string]any)
+	if !ok {
+		t.Fatalf("Expected payload to be an object, got %v", entry)
+	}
+	user, ok := payload["user"].(map[string]any)
+	if !ok || user["id"] != float64(7) || entry["payload.user.id"] != nil {
+		t.Errorf("Expected a nested user object, got %v", payload)
+	}
+}
+
+func TestFlattenCycle(t *testing.T) {
+	loop := &flatNode{Name: "a"}
+	loop.Next = &flatNode{Name: "b", Next: loop}
+
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+
+	log.Info("cycle", logger.Flatten("node", loop))
+
+	expected := "{node.name=a node.next.name=b node.next.next=<cycle>}"
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("Expected log output to contain %q, got: %s", expected, buf.String())
+	}
+}
+
+func TestFlattenMaxDepth(t *testing.T) {
+	var deep *flatNode
+	for i := 0; i < logger.MaxFlattenDepth+2; i++ {
+		deep = &flatNode{Name: "n", Next: deep}
+	}
+
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+
+	log.Info("deep", logger.Flatten("node", deep))
+
+	key := "node" + strings.Repeat(".next", logger.MaxFlattenDepth)
+	if !strings.Contains(buf.String(), key+`="{n `) || strings.Contains(buf.String(), key+".name") {
+		t.Errorf("Expected expansion to stop at %s, got: %s", key, buf.String())
+	}
+}
+
+func TestFlattenSkippedWhenFiltered(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Level: logger.InfoLevel})
+
+	log.Debug("filtered", logger.Flatten("payload", map[string]any{"k": "v"}))
+
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing written, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// TemporaryFloorSetter is implemented by loggers whose outputs can have
+// their minimum level raised for a while, for example to cut noise during
+// an incident. Loggers created by New and MultiLogger implement it.
+type TemporaryFloorSetter interface {
+	// SetTemporaryFloor raises the minimum level of the output named
+	// destName to level for d. The effective level is the higher of the
+	// configured level and the floor. A later call for the same output
+	// replaces the floor and its expiry; a d of zero or less removes it.
+	// Applying and reverting a floor are logged on the output. It returns
+	// an error if no output has that name.
+	SetTemporaryFloor(destName string, level Level, d time.Duration) error
+}
+
+// FloorDescription describes an active temporary floor
+type FloorDescription struct {
+	Level string    `json:"level"`
+	Until time.Time `json:"until"`
+}
+
+// outputName returns the name of the logger's output as used in health
+// reports. The caller holds l.out.mu.
+func (l *standardLogger) outputName() string {
+	if l.out.name != "" {
+		return l.out.name
+	}
+	return describeOutput(l.logger.Writer())
+}
+
+func (l *standardLogger) SetTemporaryFloor(destName string, level Level, d time.Duration) error {
+	l.out.mu.Lock()
+	if l.outputName() != destName {
+		l.out.mu.Unlock()
+		return fmt.Errorf("no output named %q", destName)
+	}
+
+	now := l.clock()
+	if d <= 0 {
+		hadFloor := atomic.SwapInt32(&l.out.floor, 0) != 0
+		l.out.mu.Unlock()
+		if hadFloor {
+			l.logFloorReverted()
+		}
+		return nil
+	}
+
+	until := now.Add(d)
+	atomic.StoreInt64(&l.out.floorUntil, until.UnixNano())
+	atomic.StoreInt32(&l.out.floor, int32(level)+1)
+	l.out.mu.Unlock()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.writeEntry(WarnLevel, "temporary level floor applied", []Field{
+		{Key: "incident_floor", Value: level.String()},
+		{Key: "output", Value: destName},
+		{Key: "until", Value: formatTime(until, l.timeFormat)},
+	}, now)
+	return nil
+}
+
+// aboveFloor reports whether an entry at level passes the output's
+// temporary floor, reverting the floor once it has expired
+func (l *standardLogger) aboveFloor(level Level) bool {
+	floor := atomic.LoadInt32(&l.out.floor)
+	if floor == 0 {
+		return true
+	}
+	if l.clock().UnixNano() < atomic.LoadInt64(&l.out.floorUntil) {
+		return level >= Level(floor-1)
+	}
+	if atomic.CompareAndSwapInt32(&l.out.floor, floor, 0) {
+		l.logFloorReverted()
+	}
+	return true
+}
+
+func (l *standardLogger) logFloorReverted() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.writeEntry(InfoLevel, "temporary level floor reverted", nil, l.clock())
+}
+
+// describeFloor returns the active floor, if any
+func (l *standardLogger) describeFloor() *FloorDescription {
+	floor := atomic.LoadInt32(&l.out.floor)
+	until := time.Unix(0, atomic.LoadInt64(&l.out.floorUntil))
+	if floor == 0 || !l.clock().Before(until) {
+		return nil
+	}
+	return &FloorDescription{Level: Level(floor - 1).String(), Until: until}
+}
+
+// SetTemporaryFloor applies the floor to every child whose output is
+// named destName. It returns an error only if no child matched.
+func (m *multiLogger) SetTemporaryFloor(destName string, level Level, d time.Duration) error {
+	matched := false
+	for _, logger := range m.loggers {
+		setter, ok := logger.(TemporaryFloorSetter)
+		if !ok {
+			continue
+		}
+		if setter.SetTemporaryFloor(destName, level, d) == nil {
+			matched = true
+		}
+	}
+	if !matched {
+		return fmt.Errorf("no output named %q", destName)
+	}
+	return nil
+}
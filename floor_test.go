@@ -0,0 +1,89 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestTemporaryFloor(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	var fileBuf, consoleBuf bytes.Buffer
+	file := logger.New(logger.Config{Output: &fileBuf, OutputName: "file", Clock: clock})
+	console := logger.New(logger.Config{Output: &consoleBuf, OutputName: "console", Clock: clock})
+	multi := logger.MultiLogger(console, file)
+
+	setter, ok := multi.(logger.TemporaryFloorSetter)
+	if !ok {
+		t.Fatal("Expected MultiLogger to implement TemporaryFloorSetter")
+	}
+	if err := setter.SetTemporaryFloor("file", logger.WarnLevel, 10*time.Minute); err != nil {
+		t.Fatalf("SetTemporaryFloor failed: %v", err)
+	}
+	if !strings.Contains(fileBuf.String(), "[WARN] temporary level floor applied {incident_floor=WARN output=file until=2024-03-01T12:10:00Z}") {
+		t.Errorf("Expected the floor to be logged on apply, got: %s", fileBuf.String())
+	}
+
+	fileBuf.Reset()
+	multi.Info("routine")
+	multi.Warn("important")
+	if strings.Contains(fileBuf.String(), "routine") || !strings.Contains(fileBuf.String(), "important") {
+		t.Errorf("Expected the file to drop Info during the incident, got: %s", fileBuf.String())
+	}
+	if !strings.Contains(consoleBuf.String(), "routine") {
+		t.Errorf("Expected the console to be untouched, got: %s", consoleBuf.String())
+	}
+
+	floor := logger.Describe(multi).Children[1].Floor
+	if floor == nil || floor.Level != "WARN" || !floor.Until.Equal(now.Add(10*time.Minute)) {
+		t.Errorf("Expected Describe to report the floor, got %+v", floor)
+	}
+
+	// The floor reverts on its own once it expires
+	fileBuf.Reset()
+	now = now.Add(10 * time.Minute)
+	multi.Info("back to normal")
+	lines := strings.Split(strings.TrimSpace(fileBuf.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "temporary level floor reverted") || !strings.Contains(lines[1], "back to normal") {
+		t.Errorf("Expected the revert to be logged before the entry, got: %s", fileBuf.String())
+	}
+	if logger.Describe(file).Floor != nil {
+		t.Error("Expected no floor in the description after reverting")
+	}
+}
+
+func TestTemporaryFloorReplaceAndClear(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, OutputName: "net", Clock: func() time.Time { return now }})
+	setter := log.(logger.TemporaryFloorSetter)
+
+	setter.SetTemporaryFloor("net", logger.ErrorLevel, time.Minute)
+	setter.SetTemporaryFloor("net", logger.WarnLevel, time.Hour)
+
+	buf.Reset()
+	now = now.Add(2 * time.Minute)
+	log.Warn("replaced floor")
+	if !strings.Contains(buf.String(), "replaced floor") {
+		t.Errorf("Expected the later call to replace level and expiry, got: %s", buf.String())
+	}
+
+	setter.SetTemporaryFloor("net", logger.WarnLevel, 0)
+	if !strings.Contains(buf.String(), "temporary level floor reverted") {
+		t.Errorf("Expected clearing the floor to be logged, got: %s", buf.String())
+	}
+	buf.Reset()
+	log.Info("cleared")
+	if !strings.Contains(buf.String(), "cleared") {
+		t.Errorf("Expected Info after clearing, got: %s", buf.String())
+	}
+
+	if err := setter.SetTemporaryFloor("missing", logger.WarnLevel, time.Minute); err == nil {
+		t.Error("Expected an error for an unknown output name")
+	}
+}
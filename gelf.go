@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultHost returns the host name reported in GELF messages
+func defaultHost() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		return "localhost"
+	}
+	return host
+}
+
+// encodeGELF renders an entry as a GELF 1.1 message. The level is the
+// syslog severity of the entry's level. Fields are flattened with dotted
+// group keys, and values that are not numbers are written as strings,
+// since GELF does not allow nested additional fields.
+func (l *standardLogger) encodeGELF(now time.Time, level Level, msg string, fields []Field) string {
+	var b bytes.Buffer
+	var failures []string
+
+	b.WriteString(`{"version":"1.1","host":`)
+	writeJSONString(&b, l.host)
+	b.WriteString(`,"short_message":`)
+	writeJSONString(&b, msg)
+	b.WriteString(`,"timestamp":`)
+	b.WriteString(strconv.FormatFloat(float64(now.UnixNano()/int64(time.Millisecond))/1000, 'f', 3, 64))
+	b.WriteString(`,"level":`)
+	severity, ok := SyslogSeverities[level]
+	if !ok {
+		severity = SyslogSeverities[InfoLevel]
+	}
+	b.WriteString(strconv.Itoa(severity))
+	writeGELFFields(&b, "", fields, &failures)
+	if len(failures) > 0 {
+		b.WriteString(`,"_` + internalErrorKey + `":`)
+		writeJSONString(&b, strings.Join(failures, "; "))
+		l.formatFailed(failures)
+	}
+	b.WriteString("}")
+
+	return b.String()
+}
+
+// writeGELFFields writes fields as additional fields
+func writeGELFFields(b *bytes.Buffer, prefix string, fields []Field, failures *[]string) {
+	for _, field := range fields {
+		key := prefix + field.Key
+		if group, ok := field.Value.(groupValue); ok {
+			writeGELFFields(b, key+".", group, failures)
+			continue
+		}
+
+		b.WriteString(",")
+		writeJSONString(b, gelfFieldName(key))
+		b.WriteString(":")
+		if number, ok := gelfNumber(field.Value); ok {
+			b.WriteString(number)
+			continue
+		}
+		value, err := formatValue(field.Value)
+		if err != nil {
+			*failures = append(*failures, fmt.Sprintf("%s: %v", key, err))
+		}
+		writeJSONString(b, value)
+	}
+}
+
+// gelfFieldName prefixes key with an underscore and replaces characters
+// outside [A-Za-z0-9_.-]. The reserved _id is renamed to __id.
+func gelfFieldName(key string) string {
+	name := []byte("_" + key)
+	for i := 1; i < len(name); i++ {
+		c := name[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.' || c == '-') {
+			name[i] = '_'
+		}
+	}
+	if string(name) == "_id" {
+		return "__id"
+	}
+	return string(name)
+}
+
+// gelfNumber renders integer and finite float values as JSON numbers
+func gelfNumber(value any) (string, bool) {
+	switch v := value.(type) {
+	case int:
+		return strconv.Itoa(v), true
+	case int8, int16, int32, int64:
+		return fmt.Sprintf("%d", v), true
+	case uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", v), true
+	case float32:
+		return gelfFloat(float64(v))
+	case float64:
+		return gelfFloat(v)
+	case byteSize:
+		return strconv.FormatInt(int64(v), 10), true
+	case durationValue:
+		return v.millis(), true
+	}
+	return "", false
+}
+
+func gelfFloat(f float64) (string, bool) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", false
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64), true
+}
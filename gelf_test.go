@@ -0,0 +1,89 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestGELFFormat(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2024, 3, 1, 12, 0, 0, 250*int(time.Millisecond), time.UTC)
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatGELF, Host: "api-1", Clock: fixedClock(now), Prefix: "ignored "})
+
+	log.With(logger.Field{Key: "service", Value: "api"}).Error("payment failed",
+		logger.Field{Key: "attempt", Value: 3},
+		logger.Field{Key: "ratio", Value: 0.5},
+		logger.Field{Key: "nan", Value: math.NaN()},
+		logger.Field{Key: "ok", Value: false},
+		logger.Field{Key: "err", Value: errors.New("card declined")},
+		logger.Field{Key: "tags", Value: []string{"a", "b"}},
+		logger.Field{Key: "id", Value: "reserved"},
+		logger.Field{Key: "bad key!", Value: "v"},
+		logger.Group("http", logger.Field{Key: "status", Value: 402}),
+	)
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected a JSON object, got %q: %v", buf.String(), err)
+	}
+
+	// Required and standard GELF 1.1 keys
+	expected := map[string]any{
+		"version":       "1.1",
+		"host":          "api-1",
+		"short_message": "payment failed",
+		"timestamp":     1709294400.25,
+		"level":         float64(3),
+		"_service":      "api",
+		"_attempt":      float64(3),
+		"_ratio":        0.5,
+		"_nan":          "NaN",
+		"_ok":           "false",
+		"_err":          "card declined",
+		"_tags":         "[a b]",
+		"__id":          "reserved",
+		"_bad_key_":     "v",
+		"_http.status":  float64(402),
+	}
+	for key, want := range expected {
+		if got := entry[key]; got != want {
+			t.Errorf("Expected %s to be %v (%T), got %v (%T)", key, want, want, got, got)
+		}
+	}
+
+	valid := regexp.MustCompile(`^[\w\.\-]*$`)
+	for key, value := range entry {
+		switch key {
+		case "version", "host", "short_message", "timestamp", "level":
+			continue
+		}
+		if !strings.HasPrefix(key, "_") || key == "_id" || !valid.MatchString(key) {
+			t.Errorf("Invalid additional field name %q", key)
+		}
+		switch value.(type) {
+		case string, float64:
+		default:
+			t.Errorf("Expected additional field %s to be a string or number, got %T", key, value)
+		}
+	}
+}
+
+func TestGELFDefaultHost(t *testing.T) {
+	var buf bytes.Buffer
+	logger.New(logger.Config{Output: &buf, Format: logger.FormatGELF}).Info("hello")
+
+	var entry struct {
+		Host string `json:"host"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil || entry.Host == "" {
+		t.Errorf("Expected a default host, got %q (%v)", buf.String(), err)
+	}
+}
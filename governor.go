@@ -0,0 +1,198 @@
+package logger
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultGovernorInterval is how often a MemoryGovernor samples pressure
+// when GovernorConfig.Interval is not set
+const DefaultGovernorInterval = time.Second
+
+// DefaultLargeValueBytes is the size above which string and []byte values
+// count as large when GovernorConfig.LargeValueBytes is not set
+const DefaultLargeValueBytes = 4096
+
+// LargeValues names the suppression of large field values in
+// GovernorConfig.Governed
+const LargeValues = "large_values"
+
+// DefaultGovernedEnrichments are governed when GovernorConfig.Governed is
+// nil: stack traces, goroutine counts and large values
+var DefaultGovernedEnrichments = []string{"stack", "goroutines", LargeValues}
+
+// suppressedKey marks entries that lost enrichments or values to pressure
+const suppressedKey = "enrichment_suppressed"
+
+// PressureSignal reports memory pressure as a fraction, where 1 means the
+// process is at its memory limit
+type PressureSignal func() float64
+
+// MemStatsPressure returns a signal reporting the live heap as a fraction
+// of limit bytes. It reads runtime.MemStats, which briefly stops the
+// world, so it is only sampled once per governor interval.
+func MemStatsPressure(limit uint64) PressureSignal {
+	return func() float64 {
+		if limit == 0 {
+			return 0
+		}
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		return float64(stats.HeapAlloc) / float64(limit)
+	}
+}
+
+// GovernorConfig configures a MemoryGovernor
+type GovernorConfig struct {
+	// Signal reports the current pressure. It defaults to
+	// MemStatsPressure(MemoryLimit).
+	Signal PressureSignal
+
+	// MemoryLimit is the heap size the default signal measures against.
+	// Without it or a Signal, pressure is always zero.
+	MemoryLimit uint64
+
+	// Threshold is the pressure at or above which governed enrichments are
+	// suppressed. It defaults to 0.9.
+	Threshold float64
+
+	// ResumeThreshold is the pressure below which they are enabled again.
+	// It defaults to 0.1 below Threshold, so that pressure hovering around
+	// the threshold does not flip the state on every sample.
+	ResumeThreshold float64
+
+	// Interval is the minimum time between samples. It defaults to
+	// DefaultGovernorInterval.
+	Interval time.Duration
+
+	// Governed lists the names of the enrichments to suppress: "stack"
+	// for Config.AddStackTrace and StackEnricher, the name of any other
+	// enricher as shown by Describe, and LargeValues for string and
+	// []byte values longer than LargeValueBytes and Stack fields. It
+	// defaults to DefaultGovernedEnrichments.
+	Governed []string
+
+	// LargeValueBytes defaults to DefaultLargeValueBytes
+	LargeValueBytes int
+}
+
+// MemoryGovernor turns off expensive enrichments while the process is
+// under memory pressure, so that logging a problem does not make it
+// worse. Set it as Config.MemoryGovernor; several loggers may share one.
+//
+// Pressure is sampled while entries are written, at most once per
+// interval. Entries written while it is at or above the threshold skip
+// the governed enrichments and have large values replaced by their size,
+// and those that lost anything carry enrichment_suppressed=memory_pressure.
+// Both transitions are reported to the Emergency logger.
+type MemoryGovernor struct {
+	signal     PressureSignal
+	threshold  float64
+	resume     float64
+	interval   int64
+	governed   map[string]bool
+	largeValue int
+
+	lastSample  int64 // Unix nanoseconds, zero before the first sample
+	suppressing int32
+}
+
+// NewMemoryGovernor returns a governor for cfg
+func NewMemoryGovernor(cfg GovernorConfig) *MemoryGovernor {
+	if cfg.Signal == nil {
+		cfg.Signal = MemStatsPressure(cfg.MemoryLimit)
+	}
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = 0.9
+	}
+	if cfg.ResumeThreshold <= 0 || cfg.ResumeThreshold > cfg.Threshold {
+		cfg.ResumeThreshold = cfg.Threshold - 0.1
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultGovernorInterval
+	}
+	if cfg.Governed == nil {
+		cfg.Governed = DefaultGovernedEnrichments
+	}
+	if cfg.LargeValueBytes <= 0 {
+		cfg.LargeValueBytes = DefaultLargeValueBytes
+	}
+
+	g := &MemoryGovernor{
+		signal:     cfg.Signal,
+		threshold:  cfg.Threshold,
+		resume:     cfg.ResumeThreshold,
+		interval:   int64(cfg.Interval),
+		governed:   make(map[string]bool, len(cfg.Governed)),
+		largeValue: cfg.LargeValueBytes,
+	}
+	for _, name := range cfg.Governed {
+		g.governed[name] = true
+	}
+	return g
+}
+
+// Suppressing reports whether governed enrichments are currently off
+func (g *MemoryGovernor) Suppressing() bool {
+	return atomic.LoadInt32(&g.suppressing) != 0
+}
+
+// check samples the signal if the interval has passed and reports whether
+// enrichments are suppressed. One caller samples; concurrent ones use the
+// previous state.
+func (g *MemoryGovernor) check(now time.Time) bool {
+	last := atomic.LoadInt64(&g.lastSample)
+	if (last != 0 && now.UnixNano()-last < g.interval) || !atomic.CompareAndSwapInt64(&g.lastSample, last, now.UnixNano()) {
+		return g.Suppressing()
+	}
+
+	pressure := g.signal()
+	switch {
+	case !g.Suppressing() && pressure >= g.threshold:
+		atomic.StoreInt32(&g.suppressing, 1)
+		Emergency().Warn("memory pressure: suppressing expensive log enrichments",
+			Field{Key: "pressure", Value: pressure},
+			Field{Key: "threshold", Value: g.threshold},
+		)
+	case g.Suppressing() && pressure < g.resume:
+		atomic.StoreInt32(&g.suppressing, 0)
+		Emergency().Info("memory pressure subsided: log enrichments restored",
+			Field{Key: "pressure", Value: pressure},
+		)
+	}
+	return g.Suppressing()
+}
+
+// governs reports whether the named enrichment is suppressed under
+// pressure. A nil governor governs nothing.
+func (g *MemoryGovernor) governs(name string) bool {
+	return g != nil && g.governed[name]
+}
+
+// shrinkLargeValues replaces large values with a description of their
+// size and reports whether any was replaced. fields is modified in place.
+func (g *MemoryGovernor) shrinkLargeValues(fields []Field) bool {
+	shrunk := false
+	for i, field := range fields {
+		var size int
+		switch v := field.Value.(type) {
+		case string:
+			size = len(v)
+		case []byte:
+			size = len(v)
+		case stackTrace:
+			fields[i].Value = "<suppressed>"
+			shrunk = true
+			continue
+		default:
+			continue
+		}
+		if size > g.largeValue {
+			fields[i].Value = fmt.Sprintf("<suppressed: %d bytes>", size)
+			shrunk = true
+		}
+	}
+	return shrunk
+}
@@ -0,0 +1,140 @@
+package logger_test
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+// fakePressure is a pressure signal set by the test
+type fakePressure struct {
+	bits uint64
+}
+
+func (p *fakePressure) set(v float64) { atomic.StoreUint64(&p.bits, math.Float64bits(v)) }
+func (p *fakePressure) get() float64  { return math.Float64frombits(atomic.LoadUint64(&p.bits)) }
+
+// tickingClock advances by step on every call
+func tickingClock(step time.Duration) func() time.Time {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		now = now.Add(step)
+		return now
+	}
+}
+
+func TestMemoryGovernorTransitions(t *testing.T) {
+	emergency := captureEmergency(t)
+	pressure := &fakePressure{}
+	governor := logger.NewMemoryGovernor(logger.GovernorConfig{
+		Signal:          pressure.get,
+		Threshold:       0.8,
+		ResumeThreshold: 0.6,
+		Interval:        time.Second,
+		LargeValueBytes: 8,
+	})
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{
+		Output:         &buf,
+		Clock:          tickingClock(2 * time.Second),
+		AddStackTrace:  true,
+		Enrichers:      []logger.EnrichRule{logger.EnrichAt(logger.InfoLevel, logger.GoroutineCountEnricher())},
+		MemoryGovernor: governor,
+	})
+	body := logger.Field{Key: "body", Value: strings.Repeat("x", 20)}
+
+	log.Error("normal", body)
+	if governor.Suppressing() || !strings.Contains(buf.String(), "stack=") || !strings.Contains(buf.String(), "goroutines=") || strings.Contains(buf.String(), "enrichment_suppressed") {
+		t.Fatalf("Expected full enrichment without pressure, got: %s", buf.String())
+	}
+
+	pressure.set(0.85)
+	buf.Reset()
+	log.Error("pressured", body)
+	output := buf.String()
+	if !governor.Suppressing() {
+		t.Fatal("Expected suppression above the threshold")
+	}
+	for _, unexpected := range []string{"stack=", "goroutines=", "xxxxxxxx"} {
+		if strings.Contains(output, unexpected) {
+			t.Errorf("Expected %q to be suppressed, got: %s", unexpected, output)
+		}
+	}
+	if !strings.Contains(output, `body="<suppressed: 20 bytes>"`) || !strings.Contains(output, "enrichment_suppressed=memory_pressure") {
+		t.Errorf("Expected the entry to be annotated, got: %s", output)
+	}
+
+	// Between the thresholds the state is kept
+	pressure.set(0.7)
+	log.Error("still pressured")
+	if !governor.Suppressing() {
+		t.Error("Expected suppression to continue above the resume threshold")
+	}
+
+	pressure.set(0.5)
+	buf.Reset()
+	log.Error("recovered", body)
+	if governor.Suppressing() || !strings.Contains(buf.String(), "stack=") || strings.Contains(buf.String(), "enrichment_suppressed") {
+		t.Errorf("Expected enrichment restored, got: %s", buf.String())
+	}
+
+	if !strings.Contains(emergency.String(), "suppressing expensive log enrichments") || !strings.Contains(emergency.String(), "log enrichments restored") {
+		t.Errorf("Expected both transitions reported, got: %s", emergency.String())
+	}
+}
+
+func TestMemoryGovernorSamplesOncePerInterval(t *testing.T) {
+	captureEmergency(t)
+	var samples int32
+	governor := logger.NewMemoryGovernor(logger.GovernorConfig{
+		Signal:   func() float64 { atomic.AddInt32(&samples, 1); return 1 },
+		Interval: time.Minute,
+	})
+	log := logger.New(logger.Config{Output: &bytes.Buffer{}, Clock: tickingClock(time.Second), MemoryGovernor: governor})
+
+	for i := 0; i < 90; i++ {
+		log.Info("entry")
+	}
+	if n := atomic.LoadInt32(&samples); n != 2 {
+		t.Errorf("Expected 2 samples in 90 seconds, got %d", n)
+	}
+}
+
+func TestMemoryGovernorGovernedSelection(t *testing.T) {
+	captureEmergency(t)
+	governor := logger.NewMemoryGovernor(logger.GovernorConfig{
+		Signal:   func() float64 { return 1 },
+		Governed: []string{"goroutines"},
+	})
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{
+		Output:         &buf,
+		AddStackTrace:  true,
+		Enrichers:      []logger.EnrichRule{logger.EnrichAt(logger.InfoLevel, logger.GoroutineCountEnricher())},
+		MemoryGovernor: governor,
+	})
+
+	log.Error("entry", logger.Field{Key: "body", Value: strings.Repeat("x", 5000)})
+
+	output := buf.String()
+	if strings.Contains(output, "goroutines=") || !strings.Contains(output, "stack=") || !strings.Contains(output, strings.Repeat("x", 5000)) {
+		t.Errorf("Expected only the goroutine count suppressed, got: %.300s", output)
+	}
+}
+
+func TestMemoryGovernorNoAnnotationWhenNothingSuppressed(t *testing.T) {
+	captureEmergency(t)
+	governor := logger.NewMemoryGovernor(logger.GovernorConfig{Signal: func() float64 { return 1 }})
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, MemoryGovernor: governor})
+
+	log.Info("small", logger.Field{Key: "k", Value: "v"})
+	if strings.Contains(buf.String(), "enrichment_suppressed") {
+		t.Errorf("Expected unaffected entries to stay unannotated, got: %s", buf.String())
+	}
+}
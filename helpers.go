@@ -2,103 +2,167 @@ package logger
 
 import (
 	"context"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"time"
 )
 
-// Global logger instance
-var defaultLogger Logger
+// Global logger instance, stored as a *loggerHolder so concurrent readers
+// never observe a partially written interface value
+var defaultLogger atomic.Value
+
+// loggerHolder wraps a Logger so atomic.Value always stores the same
+// concrete type
+type loggerHolder struct {
+	logger Logger
+}
 
 func init() {
-	defaultLogger = New(DefaultConfig)
+	defaultLogger.Store(&loggerHolder{logger: New(DefaultConfig)})
 }
 
+// SetDefaultLogger replaces the logger used by the package-level helpers.
+// It is safe to call concurrently with logging.
 func SetDefaultLogger(logger Logger) {
-	defaultLogger = logger
+	defaultLogger.Store(&loggerHolder{logger: logger})
+}
+
+// SwapDefaultLogger replaces the default logger and returns the previous
+// one, which makes restoring it easy:
+//
+//	defer logger.SetDefaultLogger(logger.SwapDefaultLogger(testLogger))
+func SwapDefaultLogger(logger Logger) (old Logger) {
+	return defaultLogger.Swap(&loggerHolder{logger: logger}).(*loggerHolder).logger
 }
 
 func GetDefaultLogger() Logger {
-	return defaultLogger
+	return defaultLogger.Load().(*loggerHolder).logger
 }
 
 func Debug(msg string, fields ...Field) {
-	defaultLogger.Debug(msg, fields...)
+	GetDefaultLogger().Debug(msg, fields...)
 }
 
 func Info(msg string, fields ...Field) {
-	defaultLogger.Info(msg, fields...)
+	GetDefaultLogger().Info(msg, fields...)
 }
 
 func Warn(msg string, fields ...Field) {
-	defaultLogger.Warn(msg, fields...)
+	GetDefaultLogger().Warn(msg, fields...)
 }
 
 func Error(msg string, fields ...Field) {
-	defaultLogger.Error(msg, fields...)
+	GetDefaultLogger().Error(msg, fields...)
 }
 
 func Fatal(msg string, fields ...Field) {
-	defaultLogger.Fatal(msg, fields...)
+	GetDefaultLogger().Fatal(msg, fields...)
+}
+
+func WarnCode(code, msg string, fields ...Field) {
+	logCode(GetDefaultLogger(), WarnLevel, code, msg, fields)
+}
+
+func ErrorCode(code, msg string, fields ...Field) {
+	logCode(GetDefaultLogger(), ErrorLevel, code, msg, fields)
 }
 
 func WithContext(ctx context.Context) Logger {
-	return defaultLogger.WithContext(ctx)
+	return GetDefaultLogger().WithContext(ctx)
 }
 
+// CreateFileLogger returns a logger appending to the file at filePath,
+// creating it and its directory when needed. Close closes the file.
 func CreateFileLogger(filePath string, level Level) (Logger, error) {
+	return CreateFileLoggerFS(OSFileSystem, filePath, level)
+}
+
+// CreateFileLoggerFS is CreateFileLogger on fsys instead of the disk
+func CreateFileLoggerFS(fsys FileSystem, filePath string, level Level) (Logger, error) {
+	return createFileLogger(fsys, Config{TimeFormat: DefaultConfig.TimeFormat}, filePath, level)
+}
+
+// createFileLogger opens filePath on fsys and returns a logger for cfg
+// writing to it at level
+func createFileLogger(fsys FileSystem, cfg Config, filePath string, level Level) (Logger, error) {
 	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	if err := fsys.MkdirAll(dir, 0755); err != nil {
 		return nil, err
 	}
 
-	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	file, err := fsys.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
 		return nil, err
 	}
 
 	// Create logger with file output
-	cfg := Config{
-		Level:      level,
-		Output:     file,
-		TimeFormat: DefaultConfig.TimeFormat,
-	}
+	cfg.Level = level
+	cfg.Output = file
+	cfg.Destination = DestinationFile
 
-	return New(cfg), nil
+	logger := New(cfg).(*standardLogger)
+	logger.out.owned = file
+	return logger, nil
 }
 
 func MultiLogger(loggers ...Logger) Logger {
-	return &multiLogger{loggers: loggers}
+	children := make([]Logger, len(loggers))
+	for i, logger := range loggers {
+		if i == 0 {
+			children[i] = logger
+			continue
+		}
+		children[i] = withoutAccounting(logger)
+	}
+	return &multiLogger{loggers: children}
 }
 
 type multiLogger struct {
 	loggers []Logger
 }
 
+// now reads the clock of the first child
+func (m *multiLogger) now() time.Time {
+	if len(m.loggers) == 0 {
+		return time.Now()
+	}
+	return clockOf(m.loggers[0])()
+}
+
 func (m *multiLogger) Debug(msg string, fields ...Field) {
+	retainFieldSets(fields, len(m.loggers)-1)
 	for _, logger := range m.loggers {
 		logger.Debug(msg, fields...)
 	}
 }
 
 func (m *multiLogger) Info(msg string, fields ...Field) {
+	retainFieldSets(fields, len(m.loggers)-1)
 	for _, logger := range m.loggers {
 		logger.Info(msg, fields...)
 	}
 }
 
 func (m *multiLogger) Warn(msg string, fields ...Field) {
+	retainFieldSets(fields, len(m.loggers)-1)
 	for _, logger := range m.loggers {
 		logger.Warn(msg, fields...)
 	}
 }
 
 func (m *multiLogger) Error(msg string, fields ...Field) {
+	retainFieldSets(fields, len(m.loggers)-1)
 	for _, logger := range m.loggers {
 		logger.Error(msg, fields...)
 	}
 }
 
 func (m *multiLogger) Fatal(msg string, fields ...Field) {
+	retainFieldSets(fields, len(m.loggers)-1)
 	// Only the last logger will exit
 	for i, logger := range m.loggers {
 		if i == len(m.loggers)-1 {
@@ -109,6 +173,20 @@ func (m *multiLogger) Fatal(msg string, fields ...Field) {
 	}
 }
 
+func (m *multiLogger) WarnCode(code, msg string, fields ...Field) {
+	retainFieldSets(fields, len(m.loggers)-1)
+	for _, logger := range m.loggers {
+		logCode(logger, WarnLevel, code, msg, fields)
+	}
+}
+
+func (m *multiLogger) ErrorCode(code, msg string, fields ...Field) {
+	retainFieldSets(fields, len(m.loggers)-1)
+	for _, logger := range m.loggers {
+		logCode(logger, ErrorLevel, code, msg, fields)
+	}
+}
+
 func (m *multiLogger) With(fields ...Field) Logger {
 	newLoggers := make([]Logger, len(m.loggers))
 	for i, logger := range m.loggers {
@@ -117,6 +195,34 @@ func (m *multiLogger) With(fields ...Field) Logger {
 	return &multiLogger{loggers: newLoggers}
 }
 
+func (m *multiLogger) WithLazy(fields ...Field) Logger {
+	return newLazy(m, fields)
+}
+
+func (m *multiLogger) WithContextExtractors(extractors ...ContextExtractor) Logger {
+	newLoggers := make([]Logger, len(m.loggers))
+	for i, logger := range m.loggers {
+		newLoggers[i] = WithContextExtractors(logger, extractors...)
+	}
+	return &multiLogger{loggers: newLoggers}
+}
+
+func (m *multiLogger) ExcludeContextFields(names ...string) Logger {
+	newLoggers := make([]Logger, len(m.loggers))
+	for i, logger := range m.loggers {
+		newLoggers[i] = ExcludeContextFields(logger, names...)
+	}
+	return &multiLogger{loggers: newLoggers}
+}
+
+func (m *multiLogger) Named(name string) Logger {
+	newLoggers := make([]Logger, len(m.loggers))
+	for i, logger := range m.loggers {
+		newLoggers[i] = Named(logger, name)
+	}
+	return &multiLogger{loggers: newLoggers}
+}
+
 func (m *multiLogger) WithContext(ctx context.Context) Logger {
 	newLoggers := make([]Logger, len(m.loggers))
 	for i, logger := range m.loggers {
@@ -124,3 +230,134 @@ func (m *multiLogger) WithContext(ctx context.Context) Logger {
 	}
 	return &multiLogger{loggers: newLoggers}
 }
+
+func (m *multiLogger) PushFields(fields ...Field) (undo func()) {
+	undos := make([]func(), len(m.loggers))
+	for i, logger := range m.loggers {
+		undos[i] = PushFields(logger, fields...)
+	}
+	return func() {
+		for _, undo := range undos {
+			undo()
+		}
+	}
+}
+
+// Enabled reports whether any child would write entries at level
+func (m *multiLogger) Enabled(level Level) bool {
+	for _, logger := range m.loggers {
+		if logger.Enabled(level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *multiLogger) SetLevel(level Level) {
+	for _, logger := range m.loggers {
+		logger.SetLevel(level)
+	}
+}
+
+// Flush flushes every child and returns the first error encountered
+func (m *multiLogger) Flush() error {
+	var firstErr error
+	for _, logger := range m.loggers {
+		if err := logger.Flush(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Close closes every child and returns the first error encountered
+func (m *multiLogger) Close() error {
+	var firstErr error
+	for _, logger := range m.loggers {
+		if err := logger.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// SetOutput replaces the output of every child that implements
+// OutputSetter when they all write to the same writer, and returns the
+// first error encountered. Children writing to different outputs, such as
+// the console and file children of Combined, are left alone and an error
+// is returned; set the output of the child that needs it instead.
+func (m *multiLogger) SetOutput(w io.Writer) error {
+	if _, ok := m.currentOutput(); !ok {
+		return errors.New("children write to different outputs; set the output of each child instead")
+	}
+
+	var firstErr error
+	for _, logger := range m.loggers {
+		setter, ok := logger.(OutputSetter)
+		if !ok {
+			continue
+		}
+		if err := setter.SetOutput(w); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// currentOutput returns the writer shared by every child that implements
+// OutputSetter, reporting false when they write to different writers or
+// one cannot tell
+func (m *multiLogger) currentOutput() (io.Writer, bool) {
+	var shared io.Writer
+	found := false
+	for _, logger := range m.loggers {
+		if _, ok := logger.(OutputSetter); !ok {
+			continue
+		}
+		current, ok := logger.(outputReporter)
+		if !ok {
+			return nil, false
+		}
+		w, ok := current.currentOutput()
+		if !ok || (found && !sameWriter(w, shared)) {
+			return nil, false
+		}
+		shared, found = w, true
+	}
+	return shared, true
+}
+
+// sameWriter reports whether a and b are the same writer. Writers whose
+// type cannot be compared with == are treated as different.
+func sameWriter(a, b io.Writer) (same bool) {
+	if a == nil || b == nil {
+		return a == b
+	}
+	t := reflect.TypeOf(a)
+	if t != reflect.TypeOf(b) || !t.Comparable() {
+		return false
+	}
+	// A comparable struct can still hold an uncomparable value in an
+	// interface field
+	defer func() {
+		if recover() != nil {
+			same = false
+		}
+	}()
+	return a == b
+}
+
+// ChildrenHealth reports the write health of every child output, in the
+// order the children were given. Nested multi loggers are flattened.
+func (m *multiLogger) ChildrenHealth() []SinkHealth {
+	var health []SinkHealth
+	for _, logger := range m.loggers {
+		switch reporter := logger.(type) {
+		case ChildrenHealthReporter:
+			health = append(health, reporter.ChildrenHealth()...)
+		case HealthReporter:
+			health = append(health, reporter.Health())
+		}
+	}
+	return health
+}
@@ -0,0 +1,132 @@
+package logger_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestSwapDefaultLogger(t *testing.T) {
+	var buf bytes.Buffer
+	replacement := logger.New(logger.Config{Output: &buf})
+
+	previous := logger.SwapDefaultLogger(replacement)
+	defer logger.SetDefaultLogger(previous)
+
+	if logger.GetDefaultLogger() != replacement {
+		t.Fatal("Expected the replacement to be the default logger")
+	}
+
+	logger.Info("through the default logger")
+	if !strings.Contains(buf.String(), "through the default logger") {
+		t.Errorf("Expected package-level helpers to use the replacement, got: %s", buf.String())
+	}
+
+	if restored := logger.SwapDefaultLogger(previous); restored != replacement {
+		t.Error("Expected SwapDefaultLogger to return the logger it replaced")
+	}
+}
+
+func TestSetDefaultLoggerConcurrent(t *testing.T) {
+	previous := logger.GetDefaultLogger()
+	defer logger.SetDefaultLogger(previous)
+
+	first := logger.New(logger.Config{Output: io.Discard})
+	second := logger.MultiLogger(logger.New(logger.Config{Output: io.Discard}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if (i+j)%2 == 0 {
+					logger.SetDefaultLogger(first)
+				} else {
+					logger.SetDefaultLogger(second)
+				}
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.Info("concurrent", logger.Field{Key: "iteration", Value: j})
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestNoBackgroundTasksAfterLogging(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.MultiLogger(
+		logger.New(logger.Config{Output: &buf}),
+		logger.New(logger.Config{Output: io.Discard}),
+	)
+	log.With(logger.Field{Key: "k", Value: "v"}).Info("message")
+
+	if n := logger.RunningBackgroundTasks(); n != 0 {
+		t.Errorf("Expected no background tasks, got %d", n)
+	}
+}
+
+// libraryLog stands in for a library logging through the package-level
+// helpers instead of a logger it was given
+func libraryLog() {
+	logger.Info("library message")
+}
+
+func TestRequireExplicitDefault(t *testing.T) {
+	previous := logger.GetDefaultLogger()
+	defer logger.SetDefaultLogger(previous)
+
+	logger.RequireExplicitDefault()
+	libraryLog()
+	libraryLog()
+	logger.GetDefaultLogger().With(logger.Field{Key: "k", Value: "v"}).Warn("derived")
+
+	report := logger.UninitializedUsage()
+	if report.Calls != 3 {
+		t.Errorf("Expected 3 recorded calls, got %d", report.Calls)
+	}
+	if len(report.CallSites) != 2 {
+		t.Fatalf("Expected 2 distinct call sites, got %v", report.CallSites)
+	}
+	if !strings.HasPrefix(report.CallSites[0], "github.com/MichaelAJay/go-logger_test.libraryLog (") {
+		t.Errorf("Expected the library function as first call site, got %q", report.CallSites[0])
+	}
+
+	// Installing a real logger ends the recording
+	var buf bytes.Buffer
+	logger.SetDefaultLogger(logger.New(logger.Config{Output: &buf}))
+	libraryLog()
+	if !strings.Contains(buf.String(), "library message") {
+		t.Errorf("Expected the installed logger to be used, got: %s", buf.String())
+	}
+	if calls := logger.UninitializedUsage().Calls; calls != 3 {
+		t.Errorf("Expected no more recorded calls, got %d", calls)
+	}
+}
+
+func TestRequireExplicitDefaultPanicOnUse(t *testing.T) {
+	previous := logger.GetDefaultLogger()
+	defer logger.SetDefaultLogger(previous)
+
+	logger.RequireExplicitDefault(logger.PanicOnUse())
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("Expected a panic when logging before the default is set")
+		}
+		if !strings.Contains(fmt.Sprint(r), "libraryLog") {
+			t.Errorf("Expected the panic to name the call site, got %v", r)
+		}
+	}()
+	libraryLog()
+}
@@ -0,0 +1,285 @@
+//go:build !logger_minimal
+
+package logger
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// redactedValue replaces sensitive header and query values
+const redactedValue = "[REDACTED]"
+
+// sensitiveHeaders are never logged verbatim, even when requested with
+// HTTPIncludeHeaders
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"Proxy-Authorization": true,
+	"X-Api-Key":           true,
+}
+
+// HTTPOption configures the fields produced by HTTPRequest
+type HTTPOption func(*httpOptions)
+
+type httpOptions struct {
+	redactQuery    map[string]bool
+	headers        []string
+	trustForwarded bool
+}
+
+// HTTPRedactQuery replaces the values of the named query parameters
+func HTTPRedactQuery(params ...string) HTTPOption {
+	return func(o *httpOptions) {
+		if o.redactQuery == nil {
+			o.redactQuery = make(map[string]bool)
+		}
+		for _, param := range params {
+			o.redactQuery[param] = true
+		}
+	}
+}
+
+// HTTPIncludeHeaders adds the named request headers to the group.
+// Sensitive headers such as Authorization and Cookie are redacted.
+func HTTPIncludeHeaders(names ...string) HTTPOption {
+	return func(o *httpOptions) {
+		o.headers = append(o.headers, names...)
+	}
+}
+
+// HTTPTrustForwardedFor takes remote_ip from the first address in the
+// X-Forwarded-For header when present. Only enable it behind a proxy that
+// sets the header.
+func HTTPTrustForwardedFor() HTTPOption {
+	return func(o *httpOptions) {
+		o.trustForwarded = true
+	}
+}
+
+// HTTPRequest returns a group field named http summarizing the request:
+// method, path, query, host, proto, remote_ip and user_agent. Empty values
+// are omitted and headers are only included when requested.
+func HTTPRequest(r *http.Request, opts ...HTTPOption) Field {
+	var o httpOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	fields := []Field{
+		{Key: "method", Value: r.Method},
+	}
+	if r.URL != nil {
+		fields = append(fields, Field{Key: "path", Value: r.URL.Path})
+		if r.URL.RawQuery != "" {
+			fields = append(fields, Field{Key: "query", Value: redactQuery(r.URL.Query(), o.redactQuery)})
+		}
+	}
+	if r.Host != "" {
+		fields = append(fields, Field{Key: "host", Value: r.Host})
+	}
+	fields = append(fields, Field{Key: "proto", Value: r.Proto})
+	if ip := remoteIP(r, o.trustForwarded); ip != "" {
+		fields = append(fields, Field{Key: "remote_ip", Value: ip})
+	}
+	if ua := r.UserAgent(); ua != "" {
+		fields = append(fields, Field{Key: "user_agent", Value: ua})
+	}
+	if len(o.headers) > 0 {
+		var headers []Field
+		for _, name := range o.headers {
+			name = http.CanonicalHeaderKey(name)
+			value := r.Header.Get(name)
+			if value == "" {
+				continue
+			}
+			if sensitiveHeaders[name] {
+				value = redactedValue
+			}
+			headers = append(headers, Field{Key: name, Value: value})
+		}
+		if len(headers) > 0 {
+			fields = append(fields, Group("headers", headers...))
+		}
+	}
+
+	return Group("http", fields...)
+}
+
+// HTTPResponse returns a group field named http_response with the status
+// code, body size in bytes and handling duration
+func HTTPResponse(status int, size int64, dur time.Duration) Field {
+	return Group("http_response",
+		Field{Key: "status", Value: status},
+		Field{Key: "size", Value: size},
+		Field{Key: "duration", Value: dur},
+	)
+}
+
+// redactQuery encodes query parameters, replacing the values of the
+// redacted parameters
+func redactQuery(query url.Values, redacted map[string]bool) string {
+	for param := range redacted {
+		if values, ok := query[param]; ok {
+			for i := range values {
+				values[i] = redactedValue
+			}
+		}
+	}
+	return query.Encode()
+}
+
+// remoteIP extracts the client address without its port
+func remoteIP(r *http.Request, trustForwarded bool) string {
+	if trustForwarded {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first := strings.TrimSpace(strings.Split(forwarded, ",")[0])
+			if first != "" {
+				return first
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// DebugLogHeader is the request header DebugLogMiddleware looks for
+const DebugLogHeader = "X-Debug-Log"
+
+// DebugLogMiddleware lowers the minimum level to DebugLevel for requests
+// carrying "X-Debug-Log: true" that authorize accepts, by storing
+// WithMinLevel in the request context. Loggers derived from the request
+// context with WithContext then log that request at Debug level. The
+// header is ignored when authorize is nil or returns false.
+func DebugLogMiddleware(authorize func(r *http.Request) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.EqualFold(r.Header.Get(DebugLogHeader), "true") && authorize != nil && authorize(r) {
+				r = r.WithContext(WithMinLevel(r.Context(), DebugLevel))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// DescribeHandler serves the description of l as JSON on GET requests.
+// Mount it on an admin endpoint such as /logconfig.
+func DescribeHandler(l Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Describe(l))
+	})
+}
+
+// SchemaHandler serves the observer's report as JSON on GET requests.
+// Mount it next to DescribeHandler on an admin endpoint.
+func SchemaHandler(o *SchemaObserver) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(o.Report())
+	})
+}
+
+// CanonicalMiddleware gives every request a Canonical, stored in the
+// request context and seeded with the HTTPRequest group. With autoEmit,
+// the middleware adds the HTTPResponse group once the handler returns and
+// emits "request completed", at Error for 5xx responses and Info
+// otherwise. Without it, handlers call Emit themselves. Either way, an
+// accumulator not emitted when the request ends, for example because the
+// handler panicked, is emitted with canonical_incomplete=true. The
+// duration is measured with the clock of log, Config.Clock for loggers
+// created by New.
+func CanonicalMiddleware(log Logger, autoEmit bool, opts ...HTTPOption) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			clock := clockOf(log)
+			start := clock()
+			c := NewCanonical(log.WithContext(r.Context()))
+			c.Add(HTTPRequest(r, opts...))
+			rw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
+			defer func() {
+				if c.Emitted() {
+					return
+				}
+				c.Add(HTTPResponse(rw.status, rw.size, clock().Sub(start)))
+				c.emit(WarnLevel, "request completed", Field{Key: CanonicalIncompleteKey, Value: true})
+			}()
+
+			next.ServeHTTP(rw, r.WithContext(WithCanonical(r.Context(), c)))
+
+			if autoEmit {
+				c.Add(HTTPResponse(rw.status, rw.size, clock().Sub(start)))
+				level := InfoLevel
+				if rw.status >= http.StatusInternalServerError {
+					level = ErrorLevel
+				}
+				c.Emit(level, "request completed")
+			}
+		})
+	}
+}
+
+// statusWriter records the status code and body size of a response
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	size   int64
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// HeaderCarrier adapts http.Header to TextMapCarrier
+type HeaderCarrier http.Header
+
+// Get returns the first value of the header key
+func (c HeaderCarrier) Get(key string) string {
+	return http.Header(c).Get(key)
+}
+
+// Set replaces the values of the header key
+func (c HeaderCarrier) Set(key, value string) {
+	http.Header(c).Set(key, value)
+}
+
+// Keys returns the header names
+func (c HeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
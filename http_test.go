@@ -0,0 +1,287 @@
+//go:build !logger_minimal
+
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestHTTPRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		setup    func(r *http.Request)
+		opts     []logger.HTTPOption
+		expected []string
+		absent   []string
+	}{
+		{
+			name:   "basic request",
+			target: "http://example.com/users?page=2",
+			setup: func(r *http.Request) {
+				r.RemoteAddr = "192.0.2.1:1234"
+				r.Header.Set("User-Agent", "curl/8.0")
+			},
+			expected: []string{
+				"http.method=GET", "http.path=/users", `http.query="page=2"`, "http.host=example.com",
+				"http.proto=HTTP/1.1", "http.remote_ip=192.0.2.1", "http.user_agent=curl/8.0",
+			},
+		},
+		{
+			name:   "IPv6 remote address",
+			target: "http://example.com/",
+			setup: func(r *http.Request) {
+				r.RemoteAddr = "[2001:db8::1]:443"
+			},
+			expected: []string{"http.remote_ip=2001:db8::1"},
+		},
+		{
+			name:   "missing user agent",
+			target: "http://example.com/",
+			setup: func(r *http.Request) {
+				r.Header.Del("User-Agent")
+			},
+			absent: []string{"user_agent"},
+		},
+		{
+			name:   "forwarded for is ignored by default",
+			target: "http://example.com/",
+			setup: func(r *http.Request) {
+				r.RemoteAddr = "10.0.0.1:80"
+				r.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+			},
+			expected: []string{"http.remote_ip=10.0.0.1"},
+		},
+		{
+			name:   "forwarded for when trusted",
+			target: "http://example.com/",
+			setup: func(r *http.Request) {
+				r.RemoteAddr = "10.0.0.1:80"
+				r.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+			},
+			opts:     []logger.HTTPOption{logger.HTTPTrustForwardedFor()},
+			expected: []string{"http.remote_ip=203.0.113.7"},
+		},
+		{
+			name:     "redacted query parameter",
+			target:   "http://example.com/login?token=secret&next=home",
+			opts:     []logger.HTTPOption{logger.HTTPRedactQuery("token")},
+			expected: []string{"next=home", "token=%5BREDACTED%5D"},
+			absent:   []string{"secret"},
+		},
+		{
+			name:   "sensitive headers are redacted",
+			target: "http://example.com/",
+			setup: func(r *http.Request) {
+				r.Header.Set("Authorization", "Bearer secret")
+				r.Header.Set("Accept", "application/json")
+			},
+			opts:     []logger.HTTPOption{logger.HTTPIncludeHeaders("authorization", "accept")},
+			expected: []string{"http.headers.Authorization=[REDACTED]", "http.headers.Accept=application/json"},
+			absent:   []string{"secret"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.target, nil)
+			if tt.setup != nil {
+				tt.setup(r)
+			}
+
+			var buf bytes.Buffer
+			log := logger.New(logger.Config{Output: &buf})
+			log.Info("request", logger.HTTPRequest(r, tt.opts...))
+
+			output := buf.String()
+			for _, expected := range tt.expected {
+				if !strings.Contains(output, expected) {
+					t.Errorf("Expected log output to contain %q, got: %s", expected, output)
+				}
+			}
+			for _, absent := range tt.absent {
+				if strings.Contains(output, absent) {
+					t.Errorf("Expected log output not to contain %q, got: %s", absent, output)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPResponse(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+
+	log.Info("response", logger.HTTPResponse(404, 512, 1500*time.Microsecond))
+
+	expected := "{http_response.status=404 http_response.size=512 http_response.duration=1.5ms}"
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("Expected log output to contain %q, got: %s", expected, buf.String())
+	}
+}
+
+func TestDebugLogMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Level: logger.InfoLevel})
+
+	handler := logger.DebugLogMiddleware(func(r *http.Request) bool {
+		return r.Header.Get("X-Support-Token") == "secret"
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.WithContext(r.Context()).Debug("handling")
+	}))
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		logged  bool
+	}{
+		{name: "no header", headers: nil, logged: false},
+		{name: "unauthorized", headers: map[string]string{"X-Debug-Log": "true"}, logged: false},
+		{name: "authorized", headers: map[string]string{"X-Debug-Log": "true", "X-Support-Token": "secret"}, logged: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if logged := strings.Contains(buf.String(), "handling {level_override=debug}"); logged != tt.logged {
+				t.Errorf("Expected logged=%v, got: %s", tt.logged, buf.String())
+			}
+		})
+	}
+}
+
+func TestCanonicalMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		autoEmit bool
+		handler  http.HandlerFunc
+		expected []string
+	}{
+		{
+			name:     "auto emit",
+			autoEmit: true,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				logger.CanonicalFromContext(r.Context()).Add(logger.Field{Key: "user", Value: "u1"})
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte("created"))
+			},
+			expected: []string{"[INFO] request completed {http.method=POST http.path=/orders", "user=u1 http_response.status=201 http_response.size=7"},
+		},
+		{
+			name:     "auto emit server error",
+			autoEmit: true,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+			expected: []string{"[ERROR] request completed", "http_response.status=502"},
+		},
+		{
+			name: "handler emits",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				cl := logger.CanonicalFromContext(r.Context())
+				cl.Add(logger.Field{Key: "order", Value: 42})
+				cl.Emit(logger.InfoLevel, "order placed")
+			},
+			expected: []string{"[INFO] order placed {http.method=POST", "order=42}"},
+		},
+		{
+			name: "handler forgets",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				logger.CanonicalFromContext(r.Context()).Add(logger.Field{Key: "order", Value: 42})
+			},
+			expected: []string{"[WARN] request completed", "order=42 http_response.status=200", "canonical_incomplete=true}"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := logger.New(logger.Config{Output: &buf})
+			handler := logger.CanonicalMiddleware(log, tt.autoEmit)(tt.handler)
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/orders", nil))
+
+			if n := strings.Count(buf.String(), "\n"); n != 1 {
+				t.Errorf("Expected exactly one entry, got %d: %s", n, buf.String())
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(buf.String(), expected) {
+					t.Errorf("Expected output to contain %q, got: %s", expected, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestCanonicalMiddlewarePanic(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+	handler := logger.CanonicalMiddleware(log, true)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("handler failed")
+	}))
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected the panic to propagate")
+			}
+		}()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}()
+
+	if !strings.Contains(buf.String(), "canonical_incomplete=true") {
+		t.Errorf("Expected an incomplete entry, got: %s", buf.String())
+	}
+}
+
+func TestDescribeHandler(t *testing.T) {
+	log := logger.New(logger.Config{Level: logger.InfoLevel, Output: &bytes.Buffer{}})
+	handler := logger.DescribeHandler(log)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/logconfig", nil))
+
+	var d logger.LoggerDescription
+	if err := json.NewDecoder(rec.Body).Decode(&d); err != nil {
+		t.Fatalf("Failed to decode description: %v", err)
+	}
+	if d.Level != "INFO" {
+		t.Errorf("Expected INFO level, got: %+v", d)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/logconfig", nil))
+	if rec.Code != 405 {
+		t.Errorf("Expected 405 for POST, got %d", rec.Code)
+	}
+}
+
+func TestSchemaHandler(t *testing.T) {
+	observer := logger.NewSchemaObserver(0, 0)
+	logger.New(logger.Config{Output: io.Discard, SchemaObserver: observer}).Info("x", logger.Field{Key: "n", Value: 1})
+
+	rec := httptest.NewRecorder()
+	logger.SchemaHandler(observer).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/logschema", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"key":"n"`) {
+		t.Errorf("Expected the report from the handler, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHeaderCarrierRoundTrip(t *testing.T) {
+	testPropagationRoundTrip(t, logger.HeaderCarrier(http.Header{}))
+}
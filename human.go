@@ -0,0 +1,153 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// byteSize is the value of fields created with Bytes
+type byteSize int64
+
+// Bytes returns a field holding a byte count. Text output renders it with
+// binary units when Config.HumanReadable is set; otherwise, and in
+// machine-readable formats, the raw number is written.
+func Bytes(key string, n int64) Field {
+	return Field{Key: key, Value: byteSize(n)}
+}
+
+// durationValue is the value of fields created with Duration
+type durationValue time.Duration
+
+// Duration returns a field holding a duration meant for people and
+// dashboards alike. Text output rounds it to Config.DurationPrecision, or
+// to three significant digits, such as 1.23s. JSON, GELF and ECS output
+// write the number of milliseconds, such as 1234.567.
+func Duration(key string, d time.Duration) Field {
+	return Field{Key: key, Value: durationValue(d)}
+}
+
+// String rounds the duration to three significant digits
+func (d durationValue) String() string {
+	return roundDuration(time.Duration(d)).String()
+}
+
+// MarshalJSON writes the duration as a number of milliseconds
+func (d durationValue) MarshalJSON() ([]byte, error) {
+	return []byte(d.millis()), nil
+}
+
+// millis renders the duration as a number of milliseconds
+func (d durationValue) millis() string {
+	return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', -1, 64)
+}
+
+// rawUnit returns the machine-readable form of Duration and Bytes values
+// and the suffix of the key it is written under with Config.RawUnits
+func rawUnit(value any) (suffix, raw string, ok bool) {
+	switch v := value.(type) {
+	case durationValue:
+		return "_ms", v.millis(), true
+	case byteSize:
+		return "_bytes", strconv.FormatInt(int64(v), 10), true
+	}
+	return "", "", false
+}
+
+// formatTextValue renders a value for text output, applying the human
+// readable presentation options
+func (l *standardLogger) formatTextValue(value any) (string, error) {
+	switch v := value.(type) {
+	case byteSize:
+		if l.human {
+			return humanBytes(int64(v)), nil
+		}
+		return l.formatInt(int64(v)), nil
+	case durationValue:
+		if l.durPrecision > 0 {
+			return time.Duration(v).Round(l.durPrecision).String(), nil
+		}
+		return v.String(), nil
+	case time.Duration:
+		if l.human {
+			return roundDuration(v).String(), nil
+		}
+	case stackTrace:
+		return v.join("; "), nil
+	case int:
+		return l.formatInt(int64(v)), nil
+	case int64:
+		return l.formatInt(v), nil
+	case int32:
+		return l.formatInt(int64(v)), nil
+	}
+	return formatValue(value)
+}
+
+// formatInt renders n, grouping digits when configured
+func (l *standardLogger) formatInt(n int64) string {
+	s := strconv.FormatInt(n, 10)
+	if !l.grouping {
+		return s
+	}
+	return groupDigits(s)
+}
+
+// groupDigits inserts an underscore between every group of three digits
+func groupDigits(s string) string {
+	sign := ""
+	if len(s) > 0 && s[0] == '-' {
+		sign, s = "-", s[1:]
+	}
+	if len(s) <= 3 {
+		return sign + s
+	}
+
+	grouped := make([]byte, 0, len(s)+len(s)/3)
+	for i := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			grouped = append(grouped, '_')
+		}
+		grouped = append(grouped, s[i])
+	}
+	return sign + string(grouped)
+}
+
+// humanBytes renders a byte count with binary units
+func humanBytes(n int64) string {
+	const unit = 1024
+	abs := n
+	if abs < 0 {
+		abs = -abs
+	}
+	if abs < unit {
+		return strconv.FormatInt(n, 10) + "B"
+	}
+
+	value := float64(n)
+	suffixes := []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	i := -1
+	for i < len(suffixes)-1 && (value >= unit || value <= -unit) {
+		value /= unit
+		i++
+	}
+	return fmt.Sprintf("%.1f%s", value, suffixes[i])
+}
+
+// roundDuration rounds d to three significant digits
+func roundDuration(d time.Duration) time.Duration {
+	abs := d
+	if abs < 0 {
+		abs = -abs
+	}
+	digits := len(strconv.FormatInt(int64(abs), 10))
+	if digits <= 3 {
+		return d
+	}
+
+	precision := time.Duration(1)
+	for i := 0; i < digits-3; i++ {
+		precision *= 10
+	}
+	return d.Round(precision)
+}
@@ -0,0 +1,145 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestHumanReadableValues(t *testing.T) {
+	fields := []logger.Field{
+		logger.Bytes("body", 10485760),
+		logger.Bytes("small", 512),
+		{Key: "latency", Value: 1234875221 * time.Nanosecond},
+		{Key: "count", Value: 1048576},
+	}
+
+	tests := []struct {
+		name     string
+		cfg      logger.Config
+		expected string
+	}{
+		{
+			name:     "raw",
+			expected: "{body=10485760 small=512 latency=1.234875221s count=1048576}",
+		},
+		{
+			name:     "human readable",
+			cfg:      logger.Config{HumanReadable: true},
+			expected: "{body=10.0MiB small=512B latency=1.23s count=1048576}",
+		},
+		{
+			name:     "grouped digits",
+			cfg:      logger.Config{GroupDigits: true},
+			expected: "{body=10_485_760 small=512 latency=1.234875221s count=1_048_576}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.cfg.Output = &buf
+			logger.New(tt.cfg).Info("sizes", fields...)
+
+			if !strings.Contains(buf.String(), tt.expected) {
+				t.Errorf("Expected log output to contain %q, got: %s", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestHumanReadableEdgeCases(t *testing.T) {
+	tests := []struct {
+		field    logger.Field
+		expected string
+	}{
+		{field: logger.Bytes("b", 1024), expected: "b=1.0KiB"},
+		{field: logger.Bytes("b", -2048), expected: "b=-2.0KiB"},
+		{field: logger.Bytes("b", 5<<40), expected: "b=5.0TiB"},
+		{field: logger.Field{Key: "d", Value: 999 * time.Nanosecond}, expected: "d=999ns"},
+		{field: logger.Field{Key: "d", Value: 1500 * time.Microsecond}, expected: "d=1.5ms"},
+		{field: logger.Field{Key: "d", Value: -1234567 * time.Microsecond}, expected: "d=-1.23s"},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		logger.New(logger.Config{Output: &buf, HumanReadable: true}).Info("edge", tt.field)
+
+		if !strings.Contains(buf.String(), tt.expected) {
+			t.Errorf("Expected log output to contain %q, got: %s", tt.expected, buf.String())
+		}
+	}
+}
+
+func TestDurationField(t *testing.T) {
+	d := 1234567891 * time.Nanosecond
+
+	tests := []struct {
+		name     string
+		cfg      logger.Config
+		fields   []logger.Field
+		expected string
+	}{
+		{
+			name:     "three significant digits by default",
+			fields:   []logger.Field{logger.Duration("latency", d)},
+			expected: "{latency=1.23s}",
+		},
+		{
+			name:     "configured precision",
+			cfg:      logger.Config{DurationPrecision: time.Millisecond},
+			fields:   []logger.Field{logger.Duration("latency", d)},
+			expected: "{latency=1.235s}",
+		},
+		{
+			name:     "raw units next to the rounded values",
+			cfg:      logger.Config{RawUnits: true, HumanReadable: true},
+			fields:   []logger.Field{logger.Duration("latency", d), logger.Bytes("size", 13002342)},
+			expected: "{latency=1.23s latency_ms=1234.567891 size=12.4MiB size_bytes=13002342}",
+		},
+		{
+			name:     "raw bytes without HumanReadable",
+			fields:   []logger.Field{logger.Bytes("size", 13002342)},
+			expected: "{size=13002342}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.cfg.Output = &buf
+			log := logger.New(tt.cfg)
+
+			log.Info("units", tt.fields...)
+
+			if !strings.Contains(buf.String(), "units "+tt.expected) {
+				t.Errorf("Expected fields %s, got: %s", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestDurationFieldMachineFormats(t *testing.T) {
+	field := logger.Duration("latency", 1500*time.Microsecond)
+
+	var jsonBuf bytes.Buffer
+	logger.New(logger.Config{Output: &jsonBuf, Format: logger.FormatJSON, RawUnits: true}).Info("units", field)
+	if entry := decodeEntry(t, jsonBuf.String()); entry["latency"] != 1.5 || entry["latency_ms"] != nil {
+		t.Errorf("Expected latency as milliseconds in JSON, got: %s", jsonBuf.String())
+	}
+
+	var gelfBuf bytes.Buffer
+	logger.New(logger.Config{Output: &gelfBuf, Format: logger.FormatGELF}).Info("units", field)
+	if !strings.Contains(gelfBuf.String(), `"_latency":1.5`) {
+		t.Errorf("Expected latency as milliseconds in GELF, got: %s", gelfBuf.String())
+	}
+
+	var logfmtBuf bytes.Buffer
+	logger.New(logger.Config{Output: &logfmtBuf, Format: logger.FormatLogfmt}).Info("units", field)
+	if !strings.Contains(logfmtBuf.String(), "latency=1.5ms") {
+		t.Errorf("Expected the rounded duration in logfmt, got: %s", logfmtBuf.String())
+	}
+}
@@ -0,0 +1,173 @@
+package logger
+
+import (
+	"os"
+	"runtime/debug"
+)
+
+// InitOption configures the logger built by Init
+type InitOption func(*initConfig)
+
+type initConfig struct {
+	level    Level
+	format   Format
+	filePath string
+	fs       FileSystem
+	fields   []Field
+	problems []string
+
+	ignoreSIGPIPE  bool
+	startupSummary bool
+
+	signalSafeFile *os.File
+}
+
+// InitLevel sets the minimum level of the logger built by Init
+func InitLevel(level Level) InitOption {
+	return func(c *initConfig) {
+		c.level = level
+	}
+}
+
+// LevelFromEnv reads the minimum level from the LOG_LEVEL environment
+// variable using ParseLevel. An unset variable keeps the current level and
+// an invalid one is reported on standard error.
+func LevelFromEnv() InitOption {
+	return func(c *initConfig) {
+		value, ok := os.LookupEnv("LOG_LEVEL")
+		if !ok {
+			return
+		}
+		level, err := ParseLevel(value)
+		if err != nil {
+			c.problems = append(c.problems, "LOG_LEVEL: "+err.Error())
+			return
+		}
+		c.level = level
+	}
+}
+
+// InitFormat sets the format of the console and file entries, for example
+// FormatJSON for collectors that parse standard output
+func InitFormat(format Format) InitOption {
+	return func(c *initConfig) {
+		c.format = format
+	}
+}
+
+// InitStartupSummary makes each output begin with an entry describing its
+// effective configuration, as Config.LogStartupSummary does
+func InitStartupSummary() InitOption {
+	return func(c *initConfig) {
+		c.startupSummary = true
+	}
+}
+
+// WithFile additionally writes entries to the file at path
+func WithFile(path string) InitOption {
+	return func(c *initConfig) {
+		c.filePath = path
+	}
+}
+
+// InitFileSystem makes WithFile open its file on fsys instead of the disk
+func InitFileSystem(fsys FileSystem) InitOption {
+	return func(c *initConfig) {
+		c.fs = fsys
+	}
+}
+
+// InitFields stamps the given fields on every entry
+func InitFields(fields ...Field) InitOption {
+	return func(c *initConfig) {
+		c.fields = append(c.fields, fields...)
+	}
+}
+
+// InitIgnoreSIGPIPE keeps a broken standard output from terminating the
+// process, as Config.IgnoreSIGPIPE does
+func InitIgnoreSIGPIPE() InitOption {
+	return func(c *initConfig) {
+		c.ignoreSIGPIPE = true
+	}
+}
+
+// FieldsFromEnv stamps the fields in the LOG_FIELDS environment variable,
+// parsed with ParseFields, on every entry. An invalid value is reported on
+// standard error and adds no fields.
+func FieldsFromEnv() InitOption {
+	return func(c *initConfig) {
+		fields, err := ParseFields(os.Getenv("LOG_FIELDS"))
+		if err != nil {
+			c.problems = append(c.problems, "LOG_FIELDS: "+err.Error())
+			return
+		}
+		c.fields = append(c.fields, fields...)
+	}
+}
+
+// Init builds a production logger for service, installs it as the package
+// default logger and returns it. Entries go to the console and optionally
+// to a file, and carry service and build information fields.
+//
+// Init never fails: configuration problems, such as a log file that cannot
+// be opened, are reported on standard error and the logger falls back to
+// console output. Init also registers the file SignalSafeWrite writes to.
+// Everything else Init does can also be done with New, NewFactory and
+// SetDefaultLogger.
+func Init(service string, opts ...InitOption) Logger {
+	cfg := initConfig{level: InfoLevel, format: DefaultConfig.Format, signalSafeFile: os.Stderr}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	registerSignalSafe(cfg.signalSafeFile, service)
+
+	base := DefaultConfig
+	base.Format = cfg.format
+	base.LogStartupSummary = cfg.startupSummary
+	base.IgnoreSIGPIPE = cfg.ignoreSIGPIPE
+	factory := NewFactory(base)
+	if cfg.fs != nil {
+		factory = factory.FileSystem(cfg.fs)
+	}
+	log := factory.Console(cfg.level)
+	if cfg.filePath != "" {
+		fileLogger, err := factory.File(cfg.filePath, cfg.level)
+		if err != nil {
+			cfg.problems = append(cfg.problems, "log file: "+err.Error()+"; logging to console only")
+		} else {
+			log = MultiLogger(log, fileLogger)
+		}
+	}
+
+	fields := append([]Field{{Key: "service", Value: service}}, buildInfoFields()...)
+	log = log.With(append(fields, cfg.fields...)...)
+
+	if len(cfg.problems) > 0 {
+		for _, problem := range cfg.problems {
+			Emergency().Warn("logger initialization problem", Field{Key: "service", Value: service}, Field{Key: "problem", Value: problem})
+		}
+	}
+
+	SetDefaultLogger(log)
+	return log
+}
+
+// buildInfoFields describes the running binary
+func buildInfoFields() []Field {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+
+	fields := []Field{{Key: "go_version", Value: info.GoVersion}}
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		fields = append(fields, Field{Key: "version", Value: info.Main.Version})
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			fields = append(fields, Field{Key: "revision", Value: setting.Value})
+		}
+	}
+	return fields
+}
@@ -0,0 +1,127 @@
+package logger_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestInit(t *testing.T) {
+	previous := logger.GetDefaultLogger()
+	defer logger.SetDefaultLogger(previous)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	t.Setenv("LOG_LEVEL", "warn")
+
+	log := logger.Init("checkout-service",
+		logger.LevelFromEnv(),
+		logger.WithFile(path),
+		logger.InitFields(logger.Field{Key: "region", Value: "eu"}),
+	)
+
+	if logger.GetDefaultLogger() != log {
+		t.Fatal("Expected Init to install the default logger")
+	}
+
+	logger.Info("filtered by LOG_LEVEL")
+	logger.Warn("written")
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	output := string(content)
+	if strings.Contains(output, "filtered by LOG_LEVEL") {
+		t.Errorf("Expected info entries to be filtered, got: %s", output)
+	}
+	for _, expected := range []string{"[WARN] written", "service=checkout-service", "go_version=", "region=eu"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected log file to contain %q, got: %s", expected, output)
+		}
+	}
+}
+
+func TestInitFallsBackToConsole(t *testing.T) {
+	previous := logger.GetDefaultLogger()
+	defer logger.SetDefaultLogger(previous)
+
+	// A path below a regular file cannot be created
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	log := logger.Init("svc", logger.WithFile(filepath.Join(blocker, "app.log")))
+	if log == nil {
+		t.Fatal("Expected Init to return a console logger")
+	}
+	if d := logger.Describe(log); d.Destination != "console" {
+		t.Errorf("Expected a console logger, got: %+v", d)
+	}
+}
+
+func TestInitFieldsFromEnv(t *testing.T) {
+	previous := logger.GetDefaultLogger()
+	defer logger.SetDefaultLogger(previous)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	t.Setenv("LOG_FIELDS", `env=prod,replica=3,team="core, infra"`)
+
+	logger.Init("svc", logger.FieldsFromEnv(), logger.WithFile(path))
+	logger.Info("with env fields")
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(content), "env=prod replica=3 team=\"core, infra\"") {
+		t.Errorf("Expected LOG_FIELDS to be stamped on entries, got: %s", content)
+	}
+}
+
+func TestInitFormat(t *testing.T) {
+	previous := logger.GetDefaultLogger()
+	defer logger.SetDefaultLogger(previous)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger.Init("svc", logger.InitFormat(logger.FormatJSON), logger.WithFile(path))
+	logger.Info("as json")
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal(content, &entry); err != nil {
+		t.Fatalf("Expected one JSON entry, got %q: %v", content, err)
+	}
+	if entry["msg"] != "as json" || entry["service"] != "svc" {
+		t.Errorf("Unexpected entry: %v", entry)
+	}
+}
+
+func TestInitStartupSummary(t *testing.T) {
+	previous := logger.GetDefaultLogger()
+	defer logger.SetDefaultLogger(previous)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger.Init("svc", logger.InitStartupSummary(), logger.InitLevel(logger.ErrorLevel), logger.WithFile(path))
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	output := string(content)
+	if strings.Count(output, "logger_startup=true") != 1 {
+		t.Fatalf("Expected exactly one startup entry, got: %s", output)
+	}
+	for _, expected := range []string{"level=ERROR", "destination=file"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected startup entry to contain %q, got: %s", expected, output)
+		}
+	}
+}
@@ -0,0 +1,493 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Format selects how entries are encoded
+type Format int
+
+const (
+	// FormatText writes entries as timestamp [LEVEL] msg {key=value ...}
+	FormatText Format = iota
+	// FormatJSON writes one JSON object per line with time, level and msg
+	// keys followed by one key per field. Groups become nested objects and
+	// maps, slices and structs are marshaled as nested JSON.
+	FormatJSON
+	// FormatLogfmt writes ts, level and msg followed by one key=value pair
+	// per field. Values are quoted when they need to be and groups are
+	// expanded with dotted keys.
+	FormatLogfmt
+	// FormatGELF writes GELF 1.1 messages for Graylog, one per line. Fields
+	// become additional fields prefixed with an underscore.
+	FormatGELF
+	// FormatECS writes Elastic Common Schema documents, one per line,
+	// with @timestamp, log.level and message and well-known fields such
+	// as request_id and error mapped onto their ECS names
+	FormatECS
+	// FormatSyslog writes RFC 5424 syslog messages, one per line, with
+	// fields as structured data parameters
+	FormatSyslog
+	// FormatConsole writes aligned entries for reading in a terminal
+	// during development: short timestamp, four character level, padded
+	// message and dim key=value fields
+	FormatConsole
+)
+
+// FormatVersion is the version of the bytes written for an entry, such as
+// the timestamp [LEVEL] msg {key=value ...} layout of FormatText. It is
+// bumped whenever a format changes in a way that can break parsers, and
+// is reported by Describe and the startup summary.
+const FormatVersion = 1
+
+// String returns the name of the format
+func (f Format) String() string {
+	switch f {
+	case FormatText:
+		return "text"
+	case FormatJSON:
+		return "json"
+	case FormatLogfmt:
+		return "logfmt"
+	case FormatGELF:
+		return "gelf"
+	case FormatECS:
+		return "ecs"
+	case FormatSyslog:
+		return "syslog"
+	case FormatConsole:
+		return "console"
+	default:
+		return "unknown"
+	}
+}
+
+// encodeJSON renders an entry as a single line JSON object. Rendering
+// failures are reported in a _log_internal_error key rather than aborting
+// the entry.
+func (l *standardLogger) encodeJSON(now time.Time, level Level, msg string, fields []Field) string {
+	var b bytes.Buffer
+	var failures []string
+
+	timeKey, levelKey, msgKey := l.entryKeys("time")
+	b.WriteString("{")
+	writeJSONString(&b, timeKey)
+	b.WriteString(":")
+	if isEpochFormat(l.timeFormat) {
+		b.WriteString(formatTime(now, l.timeFormat))
+	} else {
+		writeJSONString(&b, formatTime(now, l.timeFormat))
+	}
+	b.WriteString(",")
+	writeJSONString(&b, levelKey)
+	b.WriteString(":")
+	writeJSONString(&b, l.encodeLvl(level))
+	b.WriteString(",")
+	writeJSONString(&b, msgKey)
+	b.WriteString(":")
+	writeJSONString(&b, msg)
+	if len(fields) > 0 {
+		b.WriteString(",")
+		writeJSONFields(&b, "", renameReserved(fields, timeKey, levelKey, msgKey), &failures)
+	}
+	if len(failures) > 0 {
+		b.WriteString(",")
+		writeJSONString(&b, internalErrorKey)
+		b.WriteString(":")
+		writeJSONString(&b, strings.Join(failures, "; "))
+		l.formatFailed(failures)
+	}
+	b.WriteString("}")
+
+	return b.String()
+}
+
+// indentJSON indents an encoded entry for Config.PrettyJSON and ends it
+// with a blank line, which separates it from the next entry. The output
+// does not add a newline to an entry that already ends with one.
+func indentJSON(line string) string {
+	var b bytes.Buffer
+	if err := json.Indent(&b, []byte(line), "", "  "); err != nil {
+		return line
+	}
+	b.WriteString("\n\n")
+	return b.String()
+}
+
+// reservedSuffix is appended to the key of a field that collides with the
+// time, level or message key
+const reservedSuffix = "_field"
+
+// entryKeys returns the keys of the time, level and message, applying the
+// format's default time key and "level" and "msg" where Config leaves
+// them empty
+func (l *standardLogger) entryKeys(defaultTimeKey string) (timeKey, levelKey, msgKey string) {
+	timeKey, levelKey, msgKey = l.timeKey, l.levelKey, l.msgKey
+	if timeKey == "" {
+		timeKey = defaultTimeKey
+	}
+	if levelKey == "" {
+		levelKey = "level"
+	}
+	if msgKey == "" {
+		msgKey = "msg"
+	}
+	return timeKey, levelKey, msgKey
+}
+
+// renameReserved returns fields with the keys of fields colliding with one
+// of reserved suffixed with _field, so that an entry never has two values
+// for one key. The input slice is not modified.
+func renameReserved(fields []Field, reserved ...string) []Field {
+	var renamed []Field
+	for i, field := range fields {
+		for _, key := range reserved {
+			if field.Key != key {
+				continue
+			}
+			if renamed == nil {
+				renamed = append([]Field(nil), fields...)
+			}
+			renamed[i].Key = field.Key + reservedSuffix
+			break
+		}
+	}
+	if renamed == nil {
+		return fields
+	}
+	return renamed
+}
+
+// writeJSONFields writes fields as comma separated object members. Groups
+// are written as nested objects.
+func writeJSONFields(b *bytes.Buffer, prefix string, fields []Field, failures *[]string) {
+	for i, field := range fields {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		writeJSONString(b, field.Key)
+		b.WriteString(":")
+
+		if group, ok := field.Value.(groupValue); ok {
+			b.WriteString("{")
+			writeJSONFields(b, prefix+field.Key+".", group, failures)
+			b.WriteString("}")
+			continue
+		}
+
+		if err := writeJSONValue(b, field.Value); err != nil {
+			*failures = append(*failures, fmt.Sprintf("%s%s: %v", prefix, field.Key, err))
+		}
+	}
+}
+
+// jsonValue marshals a field value the way writeJSONValue writes it
+func jsonValue(value any) ([]byte, error) {
+	var b bytes.Buffer
+	err := writeJSONValue(&b, value)
+	return b.Bytes(), err
+}
+
+// writeJSONValue writes a field value as JSON. Errors and Stringers are
+// written as their string, values that cannot be marshaled fall back to
+// their text rendering, and panics are recovered.
+func writeJSONValue(b *bytes.Buffer, value any) (err error) {
+	start := b.Len()
+	defer func() {
+		if r := recover(); r != nil {
+			b.Truncate(start)
+			b.WriteString(`"<panic>"`)
+			err = fmt.Errorf("panic rendering value of type %T: %v", value, r)
+		}
+	}()
+
+	if appendJSON(b, value, 0) {
+		return nil
+	}
+	b.Truncate(start)
+
+	if rv := reflect.ValueOf(value); !rv.IsValid() || (rv.Kind() == reflect.Ptr && rv.IsNil()) {
+		b.WriteString("null")
+		return nil
+	}
+
+	switch v := value.(type) {
+	case json.Marshaler:
+		// Handled by marshal below
+	case error:
+		writeJSONString(b, v.Error())
+		return nil
+	case fmt.Stringer:
+		writeJSONString(b, v.String())
+		return nil
+	}
+
+	if s, ok := formatUnloggable(value); ok {
+		writeJSONString(b, s)
+		return nil
+	}
+	data, err := marshalJSON(value)
+	if err != nil {
+		s, _ := formatValue(value)
+		writeJSONString(b, s)
+		return fmt.Errorf("marshal %T: %w", value, err)
+	}
+	b.Write(data)
+	return nil
+}
+
+// appendJSON writes values built from strings, numbers, bools, nil,
+// []byte and the common slice and map types without encoding/json,
+// producing the bytes encoding/json would. It reports false, having
+// written part of the value, for anything else, which then takes the
+// encoding/json path.
+func appendJSON(b *bytes.Buffer, value any, depth int) bool {
+	if depth > maxValueDepth {
+		// A []any or map[string]any that contains itself
+		return false
+	}
+
+	var scratch [64]byte
+	switch v := value.(type) {
+	case nil:
+		b.WriteString("null")
+	case string:
+		writeJSONString(b, v)
+	case bool:
+		b.Write(strconv.AppendBool(scratch[:0], v))
+	case int:
+		b.Write(strconv.AppendInt(scratch[:0], int64(v), 10))
+	case int8:
+		b.Write(strconv.AppendInt(scratch[:0], int64(v), 10))
+	case int16:
+		b.Write(strconv.AppendInt(scratch[:0], int64(v), 10))
+	case int32:
+		b.Write(strconv.AppendInt(scratch[:0], int64(v), 10))
+	case int64:
+		b.Write(strconv.AppendInt(scratch[:0], v, 10))
+	case uint:
+		b.Write(strconv.AppendUint(scratch[:0], uint64(v), 10))
+	case uint8:
+		b.Write(strconv.AppendUint(scratch[:0], uint64(v), 10))
+	case uint16:
+		b.Write(strconv.AppendUint(scratch[:0], uint64(v), 10))
+	case uint32:
+		b.Write(strconv.AppendUint(scratch[:0], uint64(v), 10))
+	case uint64:
+		b.Write(strconv.AppendUint(scratch[:0], v, 10))
+	case float32:
+		return appendJSONFloat(b, float64(v), 32, depth)
+	case float64:
+		return appendJSONFloat(b, v, 64, depth)
+	case []byte:
+		if v == nil {
+			b.WriteString("null")
+			break
+		}
+		b.WriteByte('"')
+		encoded := make([]byte, base64.StdEncoding.EncodedLen(len(v)))
+		base64.StdEncoding.Encode(encoded, v)
+		b.Write(encoded)
+		b.WriteByte('"')
+	case []string:
+		if v == nil {
+			b.WriteString("null")
+			break
+		}
+		b.WriteByte('[')
+		for i, s := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeJSONString(b, s)
+		}
+		b.WriteByte(']')
+	case []int:
+		if v == nil {
+			b.WriteString("null")
+			break
+		}
+		b.WriteByte('[')
+		for i, n := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.Write(strconv.AppendInt(scratch[:0], int64(n), 10))
+		}
+		b.WriteByte(']')
+	case []any:
+		if v == nil {
+			b.WriteString("null")
+			break
+		}
+		b.WriteByte('[')
+		for i, elem := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if !appendJSON(b, elem, depth+1) {
+				return false
+			}
+		}
+		b.WriteByte(']')
+	case map[string]any:
+		if v == nil {
+			b.WriteString("null")
+			break
+		}
+		// encoding/json sorts map keys
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		b.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeJSONString(b, key)
+			b.WriteByte(':')
+			if !appendJSON(b, v[key], depth+1) {
+				return false
+			}
+		}
+		b.WriteByte('}')
+	default:
+		return false
+	}
+	return true
+}
+
+// appendJSONFloat writes f as encoding/json does: without an exponent
+// unless it is very small or very large, and with the exponent's leading
+// zero removed. NaN and infinities, which JSON cannot represent, are
+// written as strings such as "NaN" for a field value and reported false
+// inside a slice or map.
+func appendJSONFloat(b *bytes.Buffer, f float64, bits int, depth int) bool {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		if depth > 0 {
+			return false
+		}
+		writeJSONString(b, strconv.FormatFloat(f, 'g', -1, bits))
+		return true
+	}
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	var scratch [64]byte
+	data := strconv.AppendFloat(scratch[:0], f, format, -1, bits)
+	if format == 'e' {
+		// Write e-07 as e-7
+		if n := len(data); n >= 4 && data[n-4] == 'e' && data[n-3] == '-' && data[n-2] == '0' {
+			data[n-2] = data[n-1]
+			data = data[:n-1]
+		}
+	}
+	b.Write(data)
+	return true
+}
+
+// marshalJSON encodes v without escaping HTML characters
+func marshalJSON(v any) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+const hexDigits = "0123456789abcdef"
+
+// shortControlEscapes reports whether encoding/json writes backspace and
+// form feed as \b and \f, as it does since Go 1.22, rather than \u0008 and
+// \u000c. writeJSONString follows suit so both agree byte for byte.
+var shortControlEscapes = func() bool {
+	data, _ := json.Marshal("\b")
+	return string(data) == `"\b"`
+}()
+
+// writeJSONString writes s as a JSON string that encoding/json and
+// JavaScript parsers accept, with the same bytes encoding/json writes.
+// Control characters, U+2028 and U+2029 are escaped, invalid UTF-8 is
+// replaced by U+FFFD and HTML characters are not escaped.
+func writeJSONString(b *bytes.Buffer, s string) {
+	b.WriteByte('"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			b.WriteString(s[start:i])
+			switch c {
+			case '"', '\\':
+				b.WriteByte('\\')
+				b.WriteByte(c)
+			case '\n':
+				b.WriteString(`\n`)
+			case '\r':
+				b.WriteString(`\r`)
+			case '\t':
+				b.WriteString(`\t`)
+			case '\b':
+				if shortControlEscapes {
+					b.WriteString(`\b`)
+					break
+				}
+				b.WriteString(`\u0008`)
+			case '\f':
+				if shortControlEscapes {
+					b.WriteString(`\f`)
+					break
+				}
+				b.WriteString(`\u000c`)
+			default:
+				b.WriteString(`\u00`)
+				b.WriteByte(hexDigits[c>>4])
+				b.WriteByte(hexDigits[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b.WriteString(s[start:i])
+			b.WriteString("\ufffd")
+			i += size
+			start = i
+			continue
+		}
+		// U+2028 and U+2029 break JavaScript parsers
+		if r == '\u2028' || r == '\u2029' {
+			b.WriteString(s[start:i])
+			b.WriteString(`\u202`)
+			b.WriteByte(hexDigits[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	b.WriteString(s[start:])
+	b.WriteByte('"')
+}
@@ -0,0 +1,401 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+// decodeEntry parses a single JSON log line
+func decodeEntry(t *testing.T, line string) map[string]any {
+	t.Helper()
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		t.Fatalf("Expected a JSON object, got %q: %v", line, err)
+	}
+	return entry
+}
+
+func TestJSONFormat(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatJSON, Clock: fixedClock(now)})
+
+	ctx := logger.WithRequestID(context.Background(), "req-1")
+	log.With(logger.Field{Key: "service", Value: "api"}).WithContext(ctx).Info("User <login>",
+		logger.Field{Key: "attempt", Value: 3},
+		logger.Field{Key: "tags", Value: []string{"a", "b"}},
+		logger.Field{Key: "meta", Value: map[string]int{"x": 1}},
+		logger.Field{Key: "point", Value: struct {
+			X int `json:"x"`
+			Y int `json:"y"`
+		}{1, 2}},
+		logger.Field{Key: "err", Value: errors.New("boom")},
+		logger.Field{Key: "missing", Value: nil},
+		logger.Group("http", logger.Field{Key: "method", Value: "GET"}),
+	)
+
+	line := buf.String()
+	if !strings.HasPrefix(line, "{") || strings.Count(line, "\n") != 1 {
+		t.Fatalf("Expected one JSON object per line without a prefix, got %q", line)
+	}
+
+	entry := decodeEntry(t, line)
+	expected := map[string]any{
+		"time":       "2024-03-01T12:00:00Z",
+		"level":      "INFO",
+		"msg":        "User <login>",
+		"service":    "api",
+		"request_id": "req-1",
+		"attempt":    float64(3),
+		"tags":       []any{"a", "b"},
+		"meta":       map[string]any{"x": float64(1)},
+		"point":      map[string]any{"x": float64(1), "y": float64(2)},
+		"err":        "boom",
+		"missing":    nil,
+		"http":       map[string]any{"method": "GET"},
+	}
+	if !reflect.DeepEqual(entry, expected) {
+		t.Errorf("Unexpected entry:\n got  %v\n want %v", entry, expected)
+	}
+
+	// Keys keep the documented field order
+	if !strings.Contains(line, `"msg":"User <login>","service":"api","request_id":"req-1","attempt":3`) {
+		t.Errorf("Expected fields in base, context, call-site order, got %s", line)
+	}
+}
+
+func TestJSONFormatFailures(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatJSON})
+
+	log.Warn("bad values",
+		logger.Field{Key: "bad", Value: panickingStringer{}},
+		logger.Field{Key: "ch", Value: make(chan int)},
+	)
+
+	entry := decodeEntry(t, buf.String())
+	if entry["bad"] != "<panic>" || entry["ch"] != "<chan int>" {
+		t.Errorf("Expected placeholders for unrenderable values, got %v", entry)
+	}
+	if msg, _ := entry["_log_internal_error"].(string); !strings.Contains(msg, "bad: panic rendering value") {
+		t.Errorf("Expected the panic in _log_internal_error, got %v", entry["_log_internal_error"])
+	}
+}
+
+func TestJSONFormatDescribe(t *testing.T) {
+	log := logger.New(logger.Config{Output: &bytes.Buffer{}, Format: logger.FormatJSON})
+	if format := logger.Describe(log).Format; format != "json" {
+		t.Errorf("Expected format json, got %q", format)
+	}
+}
+
+func TestJSONFormatEscaping(t *testing.T) {
+	inputs := []string{
+		"line\nbreak\ttab\rreturn",
+		`quote " and backslash \`,
+		"control \x01\x1f",
+		"separators \u2028\u2029",
+		"unicode é 日本 🎉",
+		"<html> & friends",
+	}
+
+	for _, input := range inputs {
+		var buf bytes.Buffer
+		logger.New(logger.Config{Output: &buf, Format: logger.FormatJSON}).Info(input, logger.Field{Key: input, Value: input})
+
+		entry := decodeEntry(t, buf.String())
+		if entry["msg"] != input || entry[input] != input {
+			t.Errorf("Expected %q to round trip, got %v", input, entry)
+		}
+	}
+
+	var buf bytes.Buffer
+	logger.New(logger.Config{Output: &buf, Format: logger.FormatJSON}).Info("invalid \xff utf8")
+	if entry := decodeEntry(t, buf.String()); entry["msg"] != "invalid \ufffd utf8" {
+		t.Errorf("Expected invalid UTF-8 to be replaced, got %q", entry["msg"])
+	}
+}
+
+func TestJSONCustomEntryKeys(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	log := logger.New(logger.Config{
+		Output:     &buf,
+		Format:     logger.FormatJSON,
+		TimeKey:    "ts",
+		LevelKey:   "severity",
+		MessageKey: "event",
+		Clock:      fixedClock(now),
+	})
+
+	log.Info("signup", logger.Field{Key: "plan", Value: "pro"})
+
+	expected := `{"ts":"2024-03-01T12:00:00Z","severity":"INFO","event":"signup","plan":"pro"}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %s, got %s", expected, buf.String())
+	}
+}
+
+func TestJSONFieldCollidingWithEntryKey(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatJSON, MessageKey: "event"})
+
+	log.Info("signup",
+		logger.Field{Key: "event", Value: "user.created"},
+		logger.Field{Key: "level", Value: 3},
+		logger.Field{Key: "msg", Value: "kept"},
+	)
+
+	var keys []string
+	dec := json.NewDecoder(strings.NewReader(buf.String()))
+	dec.Token()
+	for dec.More() {
+		key, _ := dec.Token()
+		keys = append(keys, key.(string))
+		var skip json.RawMessage
+		dec.Decode(&skip)
+	}
+	expected := []string{"time", "level", "event", "event_field", "level_field", "msg"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("Expected keys %v without duplicates, got %v", expected, keys)
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON, got %q: %v", buf.String(), err)
+	}
+	if entry["event"] != "signup" || entry["event_field"] != "user.created" || entry["level_field"] != float64(3) {
+		t.Errorf("Expected the entry's values under the reserved keys, got %v", entry)
+	}
+}
+
+// jsonStringer returns its text from String
+type jsonStringer string
+
+func (s jsonStringer) String() string { return string(s) }
+
+func TestJSONEscapesUnsafeCharacters(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatJSON})
+
+	log.Info("nul\x00 bad\xff sep\u2028\u2029 del\x7f",
+		logger.Field{Key: "k\x00\u2028", Value: "v\x01\xc3\x28"},
+		logger.Field{Key: "err", Value: errors.New("e\u2028\xfe")},
+		logger.Field{Key: "map", Value: map[string]string{"\xff\u2029": "\x1f"}},
+	)
+
+	line := strings.TrimSuffix(buf.String(), "\n")
+	if !json.Valid([]byte(line)) {
+		t.Fatalf("Expected valid JSON, got %q", line)
+	}
+	if strings.ContainsAny(line, "\x00\x01\x1f\u2028\u2029") || !utf8.ValidString(line) {
+		t.Errorf("Expected control characters, line separators and invalid UTF-8 to be escaped, got %q", line)
+	}
+	entry := decodeEntry(t, line)
+	if entry["msg"] != "nul\x00 bad\ufffd sep\u2028\u2029 del\x7f" || entry["k\x00\u2028"] != "v\x01\ufffd(" {
+		t.Errorf("Expected escaped values to decode to the original text, got %q", entry)
+	}
+}
+
+func FuzzJSONValid(f *testing.F) {
+	f.Add("message", "key", "value")
+	f.Add("", "", "")
+	f.Add("nul\x00\x1f\x7f", "k\x00ey", "\u2028\u2029")
+	f.Add("invalid \xff\xfe utf-8", "\xed\xa0\x80", "v\xc3\x28")
+	f.Add(`"quoted\"\\`, "<html>&", "\xf4\x90\x80\x80")
+
+	formats := []logger.Format{logger.FormatJSON, logger.FormatGELF, logger.FormatECS}
+	f.Fuzz(func(t *testing.T, msg, key, value string) {
+		for _, format := range formats {
+			w := &entryWriter{}
+			log := logger.New(logger.Config{Level: logger.DebugLevel, Output: w, Format: format})
+
+			log.With(logger.Field{Key: key, Value: []byte(value)}).Info(msg,
+				logger.Field{Key: key, Value: value},
+				logger.Field{Key: "err", Value: errors.New(value)},
+				logger.Field{Key: "stringer", Value: jsonStringer(value)},
+				logger.Field{Key: "map", Value: map[string]any{key: value}},
+				logger.Group(key, logger.Field{Key: value, Value: msg}),
+			)
+
+			for _, line := range w.writes {
+				line = strings.TrimSuffix(line, "\n")
+				if !json.Valid([]byte(line)) {
+					t.Fatalf("%s: invalid JSON %q", format, line)
+				}
+				if strings.ContainsAny(line, "\n\u2028\u2029") || !utf8.ValidString(line) {
+					t.Fatalf("%s: unescaped line break or invalid UTF-8 in %q", format, line)
+				}
+			}
+		}
+	})
+}
+
+// loggedJSON returns the JSON the logger writes for value
+func loggedJSON(t *testing.T, value any) string {
+	t.Helper()
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatJSON, Clock: fixedClock(time.Unix(0, 0))})
+	log.Info("m", logger.Field{Key: "v", Value: value})
+
+	const prefix = `{"time":"1970-01-01T00:00:00Z","level":"INFO","msg":"m","v":`
+	line := buf.String()
+	if !strings.HasPrefix(line, prefix) || !strings.HasSuffix(line, "}\n") {
+		t.Fatalf("Unexpected entry %q", line)
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(line, prefix), "}\n")
+}
+
+// encodingJSON returns what encoding/json writes for value, without HTML
+// escaping
+func encodingJSON(t *testing.T, value any) string {
+	t.Helper()
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		t.Fatalf("encoding/json failed for %#v: %v", value, err)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func TestJSONEncoderMatchesEncodingJSON(t *testing.T) {
+	values := []any{
+		"plain", "", "\b\f\n\r\t\x00\x1f\x7f", "<a & b>", "\u2028\u2029", "bad \xff utf-8", "héllo 🎉",
+		true, false, nil,
+		0, -1, int8(-128), int16(300), int32(-7), int64(1 << 62), uint(7), uint8(255), uint16(65535), uint32(1 << 31), uint64(1 << 63),
+		0.0, -0.0, 1.5, 100.0, 1e20, 1e21, 1e-6, 1e-7, 123456789.125, -2.5e-10, float32(0.1), float32(3e38), float32(1e-7),
+		[]byte(nil), []byte{}, []byte("bytes\x00"),
+		[]string(nil), []string{}, []string{"a", "b\n"},
+		[]int(nil), []int{1, -2},
+		[]any(nil), []any{}, []any{"a", 1, 2.5, false, nil, []any{"nested"}, map[string]any{"k": "v"}},
+		map[string]any(nil), map[string]any{}, map[string]any{"b": 1, "a": []string{"x"}, "": nil, "\x01": "ctl"},
+		// Values the encoder leaves to encoding/json
+		time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), struct{ A int }{1}, map[string]int{"z": 1, "a": 2}, []any{struct{}{}, errors.New("e")},
+	}
+
+	for _, value := range values {
+		if got, expected := loggedJSON(t, value), encodingJSON(t, value); got != expected {
+			t.Errorf("Expected %#v to be written as %s, got %s", value, expected, got)
+		}
+	}
+
+	// encoding/json cannot write a value that contains itself
+	self := []any{1, nil}
+	self[1] = self
+	if got := loggedJSON(t, self); !strings.Contains(got, "<cycle>") {
+		t.Errorf("Expected a self-referencing slice to be cut off, got %s", got)
+	}
+}
+
+func TestJSONNonFiniteFloats(t *testing.T) {
+	tests := []struct {
+		value    any
+		expected string
+	}{
+		{math.NaN(), `"NaN"`},
+		{math.Inf(-1), `"-Inf"`},
+		{float32(math.NaN()), `"NaN"`},
+		{float32(math.Inf(1)), `"+Inf"`},
+	}
+	for _, tt := range tests {
+		if got := loggedJSON(t, tt.value); got != tt.expected {
+			t.Errorf("Expected %v to be written as %s, got %s", tt.value, tt.expected, got)
+		}
+	}
+}
+
+func FuzzJSONEncoderMatchesEncodingJSON(f *testing.F) {
+	f.Add("value", int64(42), 1.5, true)
+	f.Add("\b\f\x00<>&\u2028", int64(-1), 1e21, false)
+	f.Add("\xff\xfe", int64(1<<62), 1e-7, true)
+	f.Add("", int64(0), -0.0, false)
+	f.Add("overflow", int64(1), 1e39, true)
+
+	f.Fuzz(func(t *testing.T, s string, n int64, x float64, flag bool) {
+		if math.IsNaN(x) || math.IsInf(x, 0) || math.Abs(x) > math.MaxFloat32 {
+			// encoding/json rejects these, including float32(x) overflowing
+			// to an infinity; the logger writes them as strings
+			x = 0
+		}
+		values := []any{
+			s, n, x, flag, float32(x), int(n), uint64(n), []byte(s),
+			[]string{s, s}, []int{int(n)},
+			[]any{s, n, x, flag, nil, []any{s}},
+			map[string]any{s: x, "n": n, "nested": map[string]any{s: []any{flag}}},
+		}
+		for _, value := range values {
+			if got, expected := loggedJSON(t, value), encodingJSON(t, value); got != expected {
+				t.Fatalf("Expected %#v to be written as %s, got %s", value, expected, got)
+			}
+		}
+	})
+}
+
+func TestPrettyJSON(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatJSON, PrettyJSON: true, Clock: fixedClock(now)})
+
+	log.Info("first", logger.Group("user", logger.Field{Key: "id", Value: 7}))
+	log.Info("second")
+
+	expected := `{
+  "time": "2024-03-01T12:00:00Z",
+  "level": "INFO",
+  "msg": "first",
+  "user": {
+    "id": 7
+  }
+}
+
+{
+  "time": "2024-03-01T12:00:00Z",
+  "level": "INFO",
+  "msg": "second"
+}
+
+`
+	if buf.String() != expected {
+		t.Errorf("Expected indented entries separated by a blank line:\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	dec := json.NewDecoder(&buf)
+	for i := 0; i < 2; i++ {
+		var entry map[string]any
+		if err := dec.Decode(&entry); err != nil {
+			t.Fatalf("Expected entry %d to decode: %v", i, err)
+		}
+	}
+}
+
+func TestPrettyJSONRejectsIncompatibleOptions(t *testing.T) {
+	for _, cfg := range []logger.Config{
+		{PrettyJSON: true},
+		{PrettyJSON: true, Format: logger.FormatJSON, MaxEntryBytes: 1024},
+		{PrettyJSON: true, Format: logger.FormatJSON, MaxFieldLength: 64},
+	} {
+		if _, err := logger.NewWithError(cfg); err == nil || !strings.Contains(err.Error(), "pretty JSON") {
+			t.Errorf("Expected %+v to be rejected, got %v", cfg, err)
+		}
+	}
+}
+
+func TestFactoryDevJSON(t *testing.T) {
+	log := logger.NewFactory(logger.Config{MaxEntryBytes: 512}).DevJSON()
+
+	d := logger.Describe(log)
+	if d.Format != "json" || d.Level != "DEBUG" || !reflect.DeepEqual(d.Enrichments, []string{"pretty_json"}) {
+		t.Errorf("Expected a debug pretty JSON logger, got %+v", d)
+	}
+}
@@ -0,0 +1,174 @@
+package logger
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultMaxFieldKeys is the limit used by NewKeyGuard for non-positive
+// values
+const DefaultMaxFieldKeys = 1000
+
+// maxKeyOffenders bounds the call sites a KeyGuard keeps counts for
+const maxKeyOffenders = 32
+
+// dynamicKeyField is the key rewritten fields are written under
+const dynamicKeyField = "dynamic_key"
+
+// KeyGuard limits the number of distinct field keys written, protecting
+// log indexes from keys built out of user input such as "count_"+path.
+// Set it as Config.KeyGuard; several loggers may share one guard. It is
+// safe for concurrent use.
+//
+// The guard remembers up to maxKeys distinct keys. Once that many have
+// been seen, fields with any other key are rewritten to a dynamic_key
+// field whose value is "key=value", and the first rewrite is reported to
+// the Emergency logger with its call site. Keys starting with an allowed
+// prefix are never counted or rewritten.
+type KeyGuard struct {
+	maxKeys  int
+	prefixes []string
+
+	mu        sync.Mutex
+	keys      map[string]struct{}
+	rewritten int64
+	warned    bool
+	offenders map[string]*KeyOffender
+}
+
+// KeyOffender describes a call site whose fields were rewritten
+type KeyOffender struct {
+	Caller     string `json:"caller"`
+	Count      int64  `json:"count"`
+	ExampleKey string `json:"example_key"`
+}
+
+// NewKeyGuard returns a guard allowing maxKeys distinct keys besides
+// those starting with one of allowedPrefixes. A non-positive maxKeys
+// selects DefaultMaxFieldKeys.
+func NewKeyGuard(maxKeys int, allowedPrefixes ...string) *KeyGuard {
+	if maxKeys <= 0 {
+		maxKeys = DefaultMaxFieldKeys
+	}
+	return &KeyGuard{
+		maxKeys:   maxKeys,
+		prefixes:  append([]string(nil), allowedPrefixes...),
+		keys:      make(map[string]struct{}),
+		offenders: make(map[string]*KeyOffender),
+	}
+}
+
+// filter returns fields with keys over the limit rewritten into one
+// dynamic_key field, at the position of the first of them. Its value
+// holds the key=value pairs separated by spaces, with values quoted as
+// in logfmt where needed. The input slice is not modified.
+func (g *KeyGuard) filter(fields []Field) []Field {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var result []Field
+	var pairs bytes.Buffer
+	dynamic := -1
+	for i, field := range fields {
+		if g.admit(field.Key) {
+			if result != nil {
+				result = append(result, field)
+			}
+			continue
+		}
+
+		if result == nil {
+			result = make([]Field, i, len(fields))
+			copy(result, fields[:i])
+		}
+		if dynamic < 0 {
+			dynamic = len(result)
+			result = append(result, Field{Key: dynamicKeyField})
+		} else {
+			pairs.WriteByte(' ')
+		}
+		value, _ := formatValue(field.Value)
+		pairs.WriteString(field.Key)
+		pairs.WriteByte('=')
+		writeLogfmtValue(&pairs, value)
+		g.recordOffender(field.Key)
+	}
+	if result == nil {
+		return fields
+	}
+	result[dynamic].Value = pairs.String()
+	return result
+}
+
+// admit reports whether key may be written as is. The caller holds g.mu.
+func (g *KeyGuard) admit(key string) bool {
+	if key == dynamicKeyField {
+		return true
+	}
+	if _, ok := g.keys[key]; ok {
+		return true
+	}
+	for _, prefix := range g.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	if len(g.keys) < g.maxKeys {
+		g.keys[key] = struct{}{}
+		return true
+	}
+	return false
+}
+
+// recordOffender counts a rewrite against the logging call site and
+// reports the first one. The caller holds g.mu.
+func (g *KeyGuard) recordOffender(key string) {
+	g.rewritten++
+	caller := "<unknown>"
+	if frames := callerFrames(1); len(frames) > 0 {
+		caller = shortCaller(frames[0].File, frames[0].Line)
+	}
+
+	if offender, ok := g.offenders[caller]; ok {
+		offender.Count++
+	} else if len(g.offenders) < maxKeyOffenders {
+		g.offenders[caller] = &KeyOffender{Caller: caller, Count: 1, ExampleKey: key}
+	}
+
+	if !g.warned {
+		g.warned = true
+		Emergency().Warn("field key limit reached, rewriting new keys to "+dynamicKeyField,
+			Field{Key: "max_keys", Value: g.maxKeys},
+			Field{Key: "example_key", Value: key},
+			Field{Key: "caller", Value: caller},
+		)
+	}
+}
+
+// Rewritten returns the number of fields rewritten so far
+func (g *KeyGuard) Rewritten() int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.rewritten
+}
+
+// Offenders returns the call sites whose fields were rewritten, most
+// frequent first. At most 32 call sites are tracked.
+func (g *KeyGuard) Offenders() []KeyOffender {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	offenders := make([]KeyOffender, 0, len(g.offenders))
+	for _, offender := range g.offenders {
+		offenders = append(offenders, *offender)
+	}
+	sort.Slice(offenders, func(i, j int) bool {
+		if offenders[i].Count != offenders[j].Count {
+			return offenders[i].Count > offenders[j].Count
+		}
+		return offenders[i].Caller < offenders[j].Caller
+	})
+	return offenders
+}
@@ -0,0 +1,102 @@
+package logger_test
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestKeyGuardContainsExplosion(t *testing.T) {
+	emergency := captureEmergency(t)
+
+	var buf bytes.Buffer
+	guard := logger.NewKeyGuard(4, "http.")
+	log := logger.New(logger.Config{Output: &buf, KeyGuard: guard})
+
+	for i := 0; i < 100; i++ {
+		log.Info("request",
+			logger.Field{Key: "status", Value: 200},
+			logger.Field{Key: fmt.Sprintf("count_/users/%d", i), Value: i},
+			logger.Field{Key: fmt.Sprintf("http.header_%d", i), Value: "allowed"},
+		)
+	}
+
+	keys := map[string]bool{}
+	for _, match := range regexp.MustCompile(`[{ ]([^ {}=]+)=`).FindAllStringSubmatch(buf.String(), -1) {
+		if !strings.HasPrefix(match[1], "http.") {
+			keys[match[1]] = true
+		}
+	}
+	// status and the first three dynamic keys fit under the limit
+	if len(keys) != 5 || !keys["dynamic_key"] || !keys["status"] {
+		t.Errorf("Expected 4 admitted keys plus dynamic_key, got %v", keys)
+	}
+	if !strings.Contains(buf.String(), `dynamic_key="count_/users/99=99"`) {
+		t.Errorf("Expected the original key and value to move into dynamic_key, got: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "http.header_99=allowed") {
+		t.Error("Expected allowed prefixes to pass through")
+	}
+	if got := guard.Rewritten(); got != 97 {
+		t.Errorf("Expected 97 rewrites, got %d", got)
+	}
+
+	warnings := strings.Count(emergency.String(), "field key limit reached")
+	if warnings != 1 || !strings.Contains(emergency.String(), "example_key=count_/users/3") {
+		t.Errorf("Expected one warning naming the first rewritten key, got: %s", emergency.String())
+	}
+
+	offenders := guard.Offenders()
+	if len(offenders) != 1 || offenders[0].Count != 97 || !strings.Contains(offenders[0].Caller, "keyguard_test.go:") {
+		t.Errorf("Expected the test call site as the only offender, got %+v", offenders)
+	}
+	if !strings.Contains(emergency.String(), "caller="+offenders[0].Caller) {
+		t.Errorf("Expected the warning to name the call site, got: %s", emergency.String())
+	}
+}
+
+func TestKeyGuardSharedBetweenLoggers(t *testing.T) {
+	captureEmergency(t)
+
+	var buf bytes.Buffer
+	guard := logger.NewKeyGuard(2)
+	a := logger.New(logger.Config{Output: &buf, KeyGuard: guard})
+	b := logger.New(logger.Config{Output: &buf, KeyGuard: guard}).With(logger.Field{Key: "k1", Value: 1})
+
+	a.Info("a", logger.Field{Key: "k0", Value: 0})
+	b.Info("b")
+	a.Info("c", logger.Field{Key: "k2", Value: 2})
+
+	if !strings.Contains(buf.String(), `{dynamic_key="k2=2"}`) {
+		t.Errorf("Expected the shared limit to apply across loggers, got: %s", buf.String())
+	}
+}
+
+func TestKeyGuardMergesRewrittenKeys(t *testing.T) {
+	captureEmergency(t)
+
+	var buf bytes.Buffer
+	guard := logger.NewKeyGuard(1)
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatJSON, KeyGuard: guard})
+
+	log.Info("entry",
+		logger.Field{Key: "a", Value: 1},
+		logger.Field{Key: "b", Value: 2},
+		logger.Field{Key: "c", Value: "x y"},
+	)
+
+	if strings.Count(buf.String(), `"dynamic_key"`) != 1 {
+		t.Fatalf("Expected a single dynamic_key field, got: %s", buf.String())
+	}
+	entry := decodeEntry(t, buf.String())
+	if entry["a"] != float64(1) || entry["dynamic_key"] != `b=2 c="x y"` {
+		t.Errorf("Expected both rewritten pairs in dynamic_key, got %v", entry)
+	}
+	if got := guard.Rewritten(); got != 2 {
+		t.Errorf("Expected 2 rewrites, got %d", got)
+	}
+}
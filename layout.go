@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// layoutPlaceholder identifies what a part of a layout renders
+type layoutPlaceholder int
+
+const (
+	layoutLiteral layoutPlaceholder = iota
+	layoutTime
+	layoutLevel
+	layoutPrefix
+	layoutMessage
+	layoutFields
+)
+
+// layoutPlaceholders maps the names usable in Config.Layout
+var layoutPlaceholders = map[string]layoutPlaceholder{
+	"Time":    layoutTime,
+	"Level":   layoutLevel,
+	"Prefix":  layoutPrefix,
+	"Message": layoutMessage,
+	"Fields":  layoutFields,
+}
+
+// layoutPart is literal text or a placeholder of a parsed layout
+type layoutPart struct {
+	placeholder layoutPlaceholder
+	literal     string
+}
+
+// parseLayout splits a layout into literal text and {{.Name}}
+// placeholders. Spaces inside the braces are allowed.
+func parseLayout(layout string) ([]layoutPart, error) {
+	var parts []layoutPart
+	for rest := layout; rest != ""; {
+		open := strings.Index(rest, "{{")
+		if open < 0 {
+			parts = append(parts, layoutPart{literal: rest})
+			break
+		}
+		if open > 0 {
+			parts = append(parts, layoutPart{literal: rest[:open]})
+		}
+		closing := strings.Index(rest[open:], "}}")
+		if closing < 0 {
+			return nil, fmt.Errorf("layout: unterminated placeholder %q", rest[open:])
+		}
+		token := rest[open : open+closing+2]
+		name := strings.TrimSpace(token[2 : len(token)-2])
+		placeholder, ok := layoutPlaceholders[strings.TrimPrefix(name, ".")]
+		if !ok || !strings.HasPrefix(name, ".") {
+			return nil, fmt.Errorf("layout: unknown placeholder %q", token)
+		}
+		parts = append(parts, layoutPart{placeholder: placeholder})
+		rest = rest[open+closing+2:]
+	}
+	return parts, nil
+}
+
+// encodeLayout renders a text entry with the configured layout. Spaces
+// left at the end of the line by empty placeholders are trimmed.
+func (l *standardLogger) encodeLayout(now time.Time, level Level, msg string, fields []Field) string {
+	var b strings.Builder
+	for _, part := range l.layout {
+		switch part.placeholder {
+		case layoutLiteral:
+			b.WriteString(part.literal)
+		case layoutTime:
+			b.WriteString(formatTime(now, l.timeFormat))
+		case layoutLevel:
+			b.WriteString(l.colorLevel(level, l.encodeLvl(level)))
+		case layoutPrefix:
+			b.WriteString(l.prefix)
+		case layoutMessage:
+			b.WriteString(msg)
+		case layoutFields:
+			b.WriteString(l.formatFields(fields))
+		}
+	}
+	return strings.TrimRight(b.String(), " ")
+}
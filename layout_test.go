@@ -0,0 +1,75 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestLayout(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	log, err := logger.NewWithError(logger.Config{
+		Output: &buf,
+		Prefix: "api",
+		Layout: "{{.Level}} {{ .Time }} [{{.Prefix}}] {{.Message}} {{.Fields}}",
+		Clock:  fixedClock(now),
+	})
+	if err != nil {
+		t.Fatalf("NewWithError failed: %v", err)
+	}
+
+	log.Warn("slow", logger.Field{Key: "ms", Value: 900})
+	log.Info("no fields")
+
+	expected := "WARN 2024-03-01T12:00:00Z [api] slow {ms=900}\n" +
+		"INFO 2024-03-01T12:00:00Z [api] no fields\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestLayoutWithoutTime(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Layout: "{{.Level}}: {{.Message}}"})
+
+	log.Error("failed", logger.Field{Key: "ignored", Value: true})
+
+	if buf.String() != "ERROR: failed\n" {
+		t.Errorf("Expected only the level and message, got %q", buf.String())
+	}
+}
+
+func TestLayoutErrors(t *testing.T) {
+	tests := []struct {
+		cfg      logger.Config
+		expected string
+	}{
+		{logger.Config{Layout: "{{.Time}} {{.Lvl}}"}, `unknown placeholder "{{.Lvl}}"`},
+		{logger.Config{Layout: "{{Time}}"}, `unknown placeholder "{{Time}}"`},
+		{logger.Config{Layout: "{{.Message"}, "unterminated placeholder"},
+		{logger.Config{Layout: "{{.Message}}", Format: logger.FormatJSON}, "not supported with format json"},
+	}
+
+	for _, tt := range tests {
+		log, err := logger.NewWithError(tt.cfg)
+		if err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("Expected an error containing %q for %q, got %v", tt.expected, tt.cfg.Layout, err)
+		}
+		if log != nil {
+			t.Errorf("Expected no logger for %q", tt.cfg.Layout)
+		}
+	}
+}
+
+func TestNewPanicsOnInvalidLayout(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "unknown placeholder") {
+			t.Errorf("Expected New to panic with the layout error, got %v", r)
+		}
+	}()
+	logger.New(logger.Config{Layout: "{{.Nope}}"})
+}
@@ -0,0 +1,146 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// lazyLogger defers With until an entry passes the level check. Level,
+// output and floor state is shared between a logger and its children, so
+// everything that does not need the fields is answered by the parent.
+type lazyLogger struct {
+	parent Logger
+	fields []Field
+
+	once  sync.Once
+	child Logger
+}
+
+// newLazy returns a handle deriving parent.With(fields...) on first use.
+// The fields are copied since the caller may reuse the slice.
+func newLazy(parent Logger, fields []Field) *lazyLogger {
+	return &lazyLogger{parent: parent, fields: append([]Field(nil), fields...)}
+}
+
+// materialize derives the real child exactly once
+func (z *lazyLogger) materialize() Logger {
+	z.once.Do(func() {
+		z.child = z.parent.With(z.fields...)
+	})
+	return z.child
+}
+
+func (z *lazyLogger) Debug(msg string, fields ...Field) {
+	if z.parent.Enabled(DebugLevel) {
+		z.materialize().Debug(msg, fields...)
+	}
+}
+
+func (z *lazyLogger) Info(msg string, fields ...Field) {
+	if z.parent.Enabled(InfoLevel) {
+		z.materialize().Info(msg, fields...)
+	}
+}
+
+func (z *lazyLogger) Warn(msg string, fields ...Field) {
+	if z.parent.Enabled(WarnLevel) {
+		z.materialize().Warn(msg, fields...)
+	}
+}
+
+func (z *lazyLogger) Error(msg string, fields ...Field) {
+	if z.parent.Enabled(ErrorLevel) {
+		z.materialize().Error(msg, fields...)
+	}
+}
+
+// Fatal always materializes: the child decides whether to exit
+func (z *lazyLogger) Fatal(msg string, fields ...Field) {
+	z.materialize().Fatal(msg, fields...)
+}
+
+func (z *lazyLogger) WarnCode(code, msg string, fields ...Field) {
+	if z.parent.Enabled(WarnLevel) {
+		logCode(z.materialize(), WarnLevel, code, msg, fields)
+	}
+}
+
+func (z *lazyLogger) ErrorCode(code, msg string, fields ...Field) {
+	if z.parent.Enabled(ErrorLevel) {
+		logCode(z.materialize(), ErrorLevel, code, msg, fields)
+	}
+}
+
+// With stays lazy, accumulating fields in the order With would add them
+func (z *lazyLogger) With(fields ...Field) Logger {
+	merged := make([]Field, 0, len(z.fields)+len(fields))
+	merged = append(merged, z.fields...)
+	return newLazy(z.parent, append(merged, fields...))
+}
+
+func (z *lazyLogger) WithLazy(fields ...Field) Logger {
+	return z.With(fields...)
+}
+
+func (z *lazyLogger) WithContext(ctx context.Context) Logger {
+	return z.materialize().WithContext(ctx)
+}
+
+func (z *lazyLogger) WithContextExtractors(extractors ...ContextExtractor) Logger {
+	return WithContextExtractors(z.materialize(), extractors...)
+}
+
+func (z *lazyLogger) ExcludeContextFields(names ...string) Logger {
+	return ExcludeContextFields(z.materialize(), names...)
+}
+
+func (z *lazyLogger) Named(name string) Logger {
+	return Named(z.materialize(), name)
+}
+
+func (z *lazyLogger) PushFields(fields ...Field) (undo func()) {
+	return PushFields(z.materialize(), fields...)
+}
+
+func (z *lazyLogger) Enabled(level Level) bool { return z.parent.Enabled(level) }
+func (z *lazyLogger) SetLevel(level Level)     { z.parent.SetLevel(level) }
+func (z *lazyLogger) Flush() error             { return z.parent.Flush() }
+func (z *lazyLogger) Close() error             { return z.parent.Close() }
+
+func (z *lazyLogger) Describe() LoggerDescription {
+	return Describe(z.materialize())
+}
+
+func (z *lazyLogger) SetOutput(w io.Writer) error {
+	setter, ok := z.parent.(OutputSetter)
+	if !ok {
+		return errors.New("output cannot be replaced")
+	}
+	return setter.SetOutput(w)
+}
+
+func (z *lazyLogger) currentOutput() (io.Writer, bool) {
+	current, ok := z.parent.(outputReporter)
+	if !ok {
+		return nil, false
+	}
+	return current.currentOutput()
+}
+
+func (z *lazyLogger) SetTemporaryFloor(destName string, level Level, d time.Duration) error {
+	setter, ok := z.parent.(TemporaryFloorSetter)
+	if !ok {
+		return errors.New("temporary floors are not supported")
+	}
+	return setter.SetTemporaryFloor(destName, level, d)
+}
+
+func (z *lazyLogger) Health() SinkHealth {
+	if reporter, ok := z.parent.(HealthReporter); ok {
+		return reporter.Health()
+	}
+	return SinkHealth{}
+}
@@ -0,0 +1,100 @@
+package logger_test
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func requestFields() []logger.Field {
+	return []logger.Field{
+		{Key: "request_id", Value: "req-1"},
+		{Key: "method", Value: "GET"},
+		{Key: "path", Value: "/orders"},
+	}
+}
+
+func TestWithLazyMatchesWith(t *testing.T) {
+	var eager, lazy bytes.Buffer
+	eagerLog := logger.New(logger.Config{Output: &eager, Level: logger.InfoLevel}).With(requestFields()...)
+	lazyLog := logger.WithLazy(logger.New(logger.Config{Output: &lazy, Level: logger.InfoLevel}), requestFields()...)
+
+	for _, log := range []logger.Logger{eagerLog, lazyLog} {
+		log.Debug("filtered")
+		log.Info("handled", logger.Field{Key: "status", Value: 200})
+		log.With(logger.Field{Key: "step", Value: "auth"}).Warn("slow")
+	}
+
+	strip := func(s string) []string {
+		var lines []string
+		for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+			lines = append(lines, line[len("2006/01/02 15:04:05 "):])
+		}
+		return lines
+	}
+	if got, want := strip(lazy.String()), strip(eager.String()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected lazy output %q to match With output %q", got, want)
+	}
+
+	for _, level := range []logger.Level{logger.DebugLevel, logger.InfoLevel, logger.ErrorLevel} {
+		if lazyLog.Enabled(level) != eagerLog.Enabled(level) {
+			t.Errorf("Expected Enabled(%v) to match With", level)
+		}
+	}
+	if got, want := logger.Describe(lazyLog), logger.Describe(eagerLog); !reflect.DeepEqual(got.Fields, want.Fields) {
+		t.Errorf("Expected described fields %v, got %v", want.Fields, got.Fields)
+	}
+}
+
+func TestWithLazyFollowsLevelChanges(t *testing.T) {
+	var buf bytes.Buffer
+	base := logger.New(logger.Config{Output: &buf, Level: logger.InfoLevel})
+	log := logger.WithLazy(base, requestFields()...)
+
+	log.Debug("filtered")
+	base.SetLevel(logger.DebugLevel)
+	log.Debug("written")
+
+	output := buf.String()
+	if strings.Contains(output, "filtered") || !strings.Contains(output, "written") || !strings.Contains(output, "request_id=req-1") {
+		t.Errorf("Expected only the entry after SetLevel, got: %s", output)
+	}
+}
+
+func TestWithLazyConcurrentMaterialization(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.WithLazy(logger.New(logger.Config{Output: &buf}), requestFields()...)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			log.Info("concurrent")
+		}()
+	}
+	wg.Wait()
+
+	if got := strings.Count(buf.String(), "{request_id=req-1 method=GET path=/orders}"); got != 16 {
+		t.Errorf("Expected 16 entries with the lazy fields, got %d: %s", got, buf.String())
+	}
+}
+
+func TestWithLazyFilteredAllocations(t *testing.T) {
+	log := logger.New(logger.Config{Level: logger.InfoLevel, Output: io.Discard})
+	fields := requestFields()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		logger.WithLazy(log, fields...).Debug("filtered")
+	})
+
+	// The handle and its copy of the fields
+	if allocs > 2 {
+		t.Errorf("Expected at most 2 allocations for a lazy logger that never writes, got %v", allocs)
+	}
+}
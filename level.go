@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LevelEncoder renders a level in log output. Level.String is not affected
+// by the encoder in use.
+type LevelEncoder func(Level) string
+
+// UppercaseLevelEncoder renders levels as DEBUG, INFO, WARN, ERROR and
+// FATAL. It is the default.
+func UppercaseLevelEncoder(l Level) string {
+	return l.String()
+}
+
+// LowercaseLevelEncoder renders levels as debug, info, warn, error and
+// fatal
+func LowercaseLevelEncoder(l Level) string {
+	return strings.ToLower(l.String())
+}
+
+// PaddedLevelEncoder renders uppercase levels padded to five characters so
+// console output stays aligned
+func PaddedLevelEncoder(l Level) string {
+	return fmt.Sprintf("%-5s", l.String())
+}
+
+// ShortLevelLabels are the three letter labels dbg, inf, wrn, err and ftl
+var ShortLevelLabels = map[Level]string{
+	DebugLevel: "dbg",
+	InfoLevel:  "inf",
+	WarnLevel:  "wrn",
+	ErrorLevel: "err",
+	FatalLevel: "ftl",
+}
+
+// LabelLevelEncoder renders levels with the given labels, for example
+// ShortLevelLabels. The map is copied. Levels missing from it, including
+// levels outside the defined range, fall back to Level.String.
+func LabelLevelEncoder(labels map[Level]string) LevelEncoder {
+	copied := make(map[Level]string, len(labels))
+	for level, label := range labels {
+		copied[level] = label
+	}
+	return func(l Level) string {
+		if label, ok := copied[l]; ok {
+			return label
+		}
+		return l.String()
+	}
+}
+
+// SyslogSeverities maps levels to syslog severity numbers
+var SyslogSeverities = map[Level]int{
+	DebugLevel: 7,
+	InfoLevel:  6,
+	WarnLevel:  4,
+	ErrorLevel: 3,
+	FatalLevel: 2,
+}
+
+// NumericLevelEncoder renders levels as numbers using mapping. A nil
+// mapping uses SyslogSeverities. Levels missing from the mapping fall
+// back to their uppercase name.
+func NumericLevelEncoder(mapping map[Level]int) LevelEncoder {
+	if mapping == nil {
+		mapping = SyslogSeverities
+	}
+	return func(l Level) string {
+		if n, ok := mapping[l]; ok {
+			return strconv.Itoa(n)
+		}
+		return l.String()
+	}
+}
+
+// ParseLevel converts a level name to a Level. Names are matched case
+// insensitively after trimming surrounding whitespace, so the output of
+// the uppercase, lowercase and padded encoders round-trips. "warning" is
+// accepted for WarnLevel. Numbers are interpreted as syslog severities,
+// matching NumericLevelEncoder's default mapping.
+func ParseLevel(s string) (Level, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	switch name {
+	case "debug":
+		return DebugLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "warn", "warning":
+		return WarnLevel, nil
+	case "error":
+		return ErrorLevel, nil
+	case "fatal":
+		return FatalLevel, nil
+	}
+
+	if severity, err := strconv.Atoi(name); err == nil {
+		switch {
+		case severity == 7:
+			return DebugLevel, nil
+		case severity == 5 || severity == 6:
+			return InfoLevel, nil
+		case severity == 4:
+			return WarnLevel, nil
+		case severity == 3:
+			return ErrorLevel, nil
+		case severity >= 0 && severity <= 2:
+			return FatalLevel, nil
+		}
+	}
+
+	return InfoLevel, fmt.Errorf("unknown log level %q", s)
+}
@@ -0,0 +1,126 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+var allLevels = []logger.Level{
+	logger.DebugLevel, logger.InfoLevel, logger.WarnLevel, logger.ErrorLevel, logger.FatalLevel,
+}
+
+func TestLevelEncoders(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoder  logger.LevelEncoder
+		expected string
+	}{
+		{name: "default", expected: "[WARN]"},
+		{name: "uppercase", encoder: logger.UppercaseLevelEncoder, expected: "[WARN]"},
+		{name: "lowercase", encoder: logger.LowercaseLevelEncoder, expected: "[warn]"},
+		{name: "padded", encoder: logger.PaddedLevelEncoder, expected: "[WARN ]"},
+		{name: "numeric syslog", encoder: logger.NumericLevelEncoder(nil), expected: "[4]"},
+		{
+			name:     "numeric custom",
+			encoder:  logger.NumericLevelEncoder(map[logger.Level]int{logger.WarnLevel: 40}),
+			expected: "[40]",
+		},
+		{name: "short labels", encoder: logger.LabelLevelEncoder(logger.ShortLevelLabels), expected: "[wrn]"},
+		{
+			name:     "custom",
+			encoder:  func(l logger.Level) string { return "lvl-" + l.String() },
+			expected: "[lvl-WARN]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := logger.New(logger.Config{Output: &buf, LevelEncoder: tt.encoder})
+
+			log.Warn("encoded")
+
+			if !strings.Contains(buf.String(), tt.expected+" encoded") {
+				t.Errorf("Expected level %s, got: %s", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestParseLevelRoundTrip(t *testing.T) {
+	encoders := map[string]logger.LevelEncoder{
+		"uppercase": logger.UppercaseLevelEncoder,
+		"lowercase": logger.LowercaseLevelEncoder,
+		"padded":    logger.PaddedLevelEncoder,
+		"numeric":   logger.NumericLevelEncoder(nil),
+	}
+
+	for name, encode := range encoders {
+		for _, level := range allLevels {
+			parsed, err := logger.ParseLevel(encode(level))
+			if err != nil {
+				t.Errorf("%s: failed to parse %q: %v", name, encode(level), err)
+				continue
+			}
+			if parsed != level {
+				t.Errorf("%s: expected %v, got %v", name, level, parsed)
+			}
+		}
+	}
+}
+
+func TestParseLevelInvalid(t *testing.T) {
+	for _, input := range []string{"", "verbose", "42", "-1"} {
+		if _, err := logger.ParseLevel(input); err == nil {
+			t.Errorf("Expected error parsing %q", input)
+		}
+	}
+}
+
+func TestLabelLevelEncoder(t *testing.T) {
+	labels := map[logger.Level]string{logger.InfoLevel: "I"}
+	encode := logger.LabelLevelEncoder(labels)
+	labels[logger.InfoLevel] = "changed"
+
+	tests := []struct {
+		level    logger.Level
+		expected string
+	}{
+		{level: logger.InfoLevel, expected: "I"},
+		{level: logger.ErrorLevel, expected: "ERROR"},
+		{level: logger.Level(42), expected: "UNKNOWN"},
+	}
+	for _, tt := range tests {
+		if got := encode(tt.level); got != tt.expected {
+			t.Errorf("Expected %q for level %d, got %q", tt.expected, int(tt.level), got)
+		}
+	}
+
+	if got := logger.ErrorLevel.String(); got != "ERROR" {
+		t.Errorf("Expected Level.String to be unaffected, got %q", got)
+	}
+}
+
+func TestLevelLabelsInStructuredFormats(t *testing.T) {
+	tests := []struct {
+		format   logger.Format
+		encoder  logger.LevelEncoder
+		expected string
+	}{
+		{format: logger.FormatJSON, encoder: logger.LabelLevelEncoder(logger.ShortLevelLabels), expected: `"level":"err"`},
+		{format: logger.FormatLogfmt, encoder: logger.LabelLevelEncoder(logger.ShortLevelLabels), expected: "level=err"},
+		{format: logger.FormatLogfmt, encoder: logger.UppercaseLevelEncoder, expected: "level=ERROR"},
+		{format: logger.FormatLogfmt, expected: "level=error"},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		logger.New(logger.Config{Output: &buf, Format: tt.format, LevelEncoder: tt.encoder}).Error("labeled")
+		if !strings.Contains(buf.String(), tt.expected) {
+			t.Errorf("Expected %s output to contain %q, got: %s", tt.format, tt.expected, buf.String())
+		}
+	}
+}
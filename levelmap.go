@@ -0,0 +1,129 @@
+package logger
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// LevelMap translates the severities of third-party logging libraries
+// into Levels. Adapters bridging such libraries consult it for every
+// entry, so a mapping can be tuned without touching the adapter:
+//
+//	levels := logger.MapLevels().
+//		From("WARNING").To(logger.WarnLevel).
+//		From("WARNING").To(logger.DebugLevel).WhenPrefix("transport is closing")
+//
+// Severity names are matched case insensitively. Rules with a message
+// prefix take precedence over plain rules for the same severity, and the
+// longest matching prefix wins. Severities without a rule map to the
+// fallback level, InfoLevel unless changed with Otherwise.
+//
+// A LevelMap is safe for concurrent use and can be replaced at runtime
+// with Replace.
+type LevelMap struct {
+	state atomic.Value // *levelMapState
+}
+
+type levelMapState struct {
+	rules    []levelRule
+	fallback Level
+}
+
+type levelRule struct {
+	from   string
+	prefix string
+	to     Level
+}
+
+// LevelRuleBuilder completes a rule started with LevelMap.From
+type LevelRuleBuilder struct {
+	m    *LevelMap
+	from string
+}
+
+// MapLevels returns an empty LevelMap
+func MapLevels() *LevelMap {
+	m := &LevelMap{}
+	m.state.Store(&levelMapState{fallback: InfoLevel})
+	return m
+}
+
+// From starts a rule for the given source severity
+func (m *LevelMap) From(severity string) *LevelRuleBuilder {
+	return &LevelRuleBuilder{m: m, from: strings.ToLower(severity)}
+}
+
+// To completes the rule, mapping its severity onto level
+func (b *LevelRuleBuilder) To(level Level) *LevelMap {
+	b.m.update(func(s *levelMapState) {
+		s.rules = append(s.rules, levelRule{from: b.from, to: level})
+	})
+	return b.m
+}
+
+// WhenPrefix restricts the most recently added rule to messages starting
+// with prefix
+func (m *LevelMap) WhenPrefix(prefix string) *LevelMap {
+	m.update(func(s *levelMapState) {
+		if len(s.rules) > 0 {
+			s.rules[len(s.rules)-1].prefix = prefix
+		}
+	})
+	return m
+}
+
+// Otherwise sets the level used for severities without a matching rule
+func (m *LevelMap) Otherwise(level Level) *LevelMap {
+	m.update(func(s *levelMapState) {
+		s.fallback = level
+	})
+	return m
+}
+
+// Replace atomically swaps in the rules of other. Lookups in progress see
+// either the old or the new rules, never a mix.
+func (m *LevelMap) Replace(other *LevelMap) {
+	m.state.Store(other.load())
+}
+
+// Level returns the level for an entry with the given source severity and
+// message
+func (m *LevelMap) Level(severity, msg string) Level {
+	s := m.load()
+	severity = strings.ToLower(severity)
+
+	level := s.fallback
+	matchedPlain := false
+	longestPrefix := -1
+	for _, rule := range s.rules {
+		if rule.from != severity {
+			continue
+		}
+		switch {
+		case rule.prefix == "":
+			if !matchedPlain && longestPrefix < 0 {
+				level = rule.to
+			}
+			matchedPlain = true
+		case strings.HasPrefix(msg, rule.prefix) && len(rule.prefix) > longestPrefix:
+			level = rule.to
+			longestPrefix = len(rule.prefix)
+		}
+	}
+	return level
+}
+
+func (m *LevelMap) load() *levelMapState {
+	return m.state.Load().(*levelMapState)
+}
+
+// update applies fn to a copy of the current rules and stores the result
+func (m *LevelMap) update(fn func(*levelMapState)) {
+	current := m.load()
+	next := &levelMapState{
+		rules:    append([]levelRule(nil), current.rules...),
+		fallback: current.fallback,
+	}
+	fn(next)
+	m.state.Store(next)
+}
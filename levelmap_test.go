@@ -0,0 +1,57 @@
+package logger_test
+
+import (
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestLevelMap(t *testing.T) {
+	levels := logger.MapLevels().
+		From("WARNING").To(logger.WarnLevel).
+		From("WARNING").To(logger.DebugLevel).WhenPrefix("transport is closing").
+		From("WARNING").To(logger.InfoLevel).WhenPrefix("transport is closing: eof").
+		From("error").To(logger.ErrorLevel).
+		Otherwise(logger.DebugLevel)
+
+	tests := []struct {
+		severity string
+		msg      string
+		expected logger.Level
+	}{
+		{severity: "WARNING", msg: "connection reset", expected: logger.WarnLevel},
+		{severity: "warning", msg: "connection reset", expected: logger.WarnLevel},
+		{severity: "WARNING", msg: "transport is closing", expected: logger.DebugLevel},
+		{severity: "WARNING", msg: "transport is closing: eof", expected: logger.InfoLevel},
+		{severity: "ERROR", msg: "transport is closing", expected: logger.ErrorLevel},
+		{severity: "TRACE", msg: "anything", expected: logger.DebugLevel},
+	}
+
+	for _, tt := range tests {
+		if got := levels.Level(tt.severity, tt.msg); got != tt.expected {
+			t.Errorf("Level(%q, %q) = %v, expected %v", tt.severity, tt.msg, got, tt.expected)
+		}
+	}
+}
+
+func TestLevelMapPrefixRuleBeforePlainRule(t *testing.T) {
+	levels := logger.MapLevels().
+		From("WARN").To(logger.DebugLevel).WhenPrefix("noisy").
+		From("WARN").To(logger.ErrorLevel)
+
+	if got := levels.Level("WARN", "noisy message"); got != logger.DebugLevel {
+		t.Errorf("Expected prefix rule to win regardless of order, got %v", got)
+	}
+	if got := levels.Level("WARN", "other"); got != logger.ErrorLevel {
+		t.Errorf("Expected plain rule for other messages, got %v", got)
+	}
+}
+
+func TestLevelMapReplace(t *testing.T) {
+	levels := logger.MapLevels().From("WARNING").To(logger.WarnLevel)
+	levels.Replace(logger.MapLevels().From("WARNING").To(logger.ErrorLevel))
+
+	if got := levels.Level("WARNING", "msg"); got != logger.ErrorLevel {
+		t.Errorf("Expected replaced mapping, got %v", got)
+	}
+}
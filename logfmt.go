@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// encodeLogfmt renders an entry as a single logfmt line. Rendering
+// failures are reported in a _log_internal_error key rather than aborting
+// the entry.
+func (l *standardLogger) encodeLogfmt(now time.Time, level Level, msg string, fields []Field) string {
+	var b bytes.Buffer
+	var failures []string
+
+	timeKey, levelKey, msgKey := l.entryKeys("ts")
+	writeLogfmtKey(&b, timeKey)
+	b.WriteByte('=')
+	writeLogfmtValue(&b, formatTime(now, l.timeFormat))
+	b.WriteByte(' ')
+	writeLogfmtKey(&b, levelKey)
+	b.WriteByte('=')
+	writeLogfmtValue(&b, l.encodeLvl(level))
+	b.WriteByte(' ')
+	writeLogfmtKey(&b, msgKey)
+	b.WriteByte('=')
+	writeLogfmtValue(&b, msg)
+	writeLogfmtFields(&b, "", renameReserved(fields, timeKey, levelKey, msgKey), &failures)
+	if len(failures) > 0 {
+		b.WriteString(" " + internalErrorKey + "=")
+		writeLogfmtValue(&b, strings.Join(failures, "; "))
+		l.formatFailed(failures)
+	}
+
+	return b.String()
+}
+
+// writeLogfmtFields writes fields as space prefixed key=value pairs.
+// Grouped fields are expanded with their keys prefixed by the group name.
+func writeLogfmtFields(b *bytes.Buffer, prefix string, fields []Field, failures *[]string) {
+	for _, field := range fields {
+		key := prefix + field.Key
+		if group, ok := field.Value.(groupValue); ok {
+			writeLogfmtFields(b, key+".", group, failures)
+			continue
+		}
+
+		value, err := formatValue(field.Value)
+		if err != nil {
+			*failures = append(*failures, fmt.Sprintf("%s: %v", key, err))
+		}
+		b.WriteByte(' ')
+		writeLogfmtKey(b, key)
+		b.WriteByte('=')
+		writeLogfmtValue(b, value)
+	}
+}
+
+// writeLogfmtKey writes key with the characters logfmt does not allow in
+// keys replaced by underscores
+func writeLogfmtKey(b *bytes.Buffer, key string) {
+	if key == "" {
+		b.WriteByte('_')
+		return
+	}
+	for _, r := range key {
+		if r <= ' ' || r == '=' || r == '"' || r == 0x7f || r == utf8.RuneError {
+			b.WriteByte('_')
+			continue
+		}
+		b.WriteRune(r)
+	}
+}
+
+// writeLogfmtValue writes s bare, or quoted with JSON escapes if it is
+// empty or contains spaces, equals signs, quotes, backslashes or control
+// characters
+func writeLogfmtValue(b *bytes.Buffer, s string) {
+	if needsLogfmtQuote(s) {
+		writeJSONString(b, s)
+		return
+	}
+	b.WriteString(s)
+}
+
+func needsLogfmtQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == 0x7f || r == utf8.RuneError {
+			return true
+		}
+	}
+	return false
+}
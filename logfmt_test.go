@@ -0,0 +1,161 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+// decodeLogfmt parses a logfmt line following the rules of the common Go
+// and Ruby decoders: keys run up to '=' or a space, values are bare up to
+// the next space or double quoted with backslash escapes
+func decodeLogfmt(t *testing.T, line string) map[string]string {
+	t.Helper()
+	pairs := map[string]string{}
+	for i := 0; i < len(line); {
+		if line[i] == ' ' {
+			i++
+			continue
+		}
+		start := i
+		for i < len(line) && line[i] != '=' && line[i] != ' ' {
+			i++
+		}
+		key := line[start:i]
+		if i == len(line) || line[i] != '=' {
+			pairs[key] = ""
+			continue
+		}
+		i++
+
+		if i < len(line) && line[i] == '"' {
+			end := i + 1
+			for end < len(line) && line[end] != '"' {
+				if line[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(line) {
+				t.Fatalf("Unterminated quoted value for %q in %q", key, line)
+			}
+			var value string
+			if err := json.Unmarshal([]byte(line[i:end+1]), &value); err != nil {
+				t.Fatalf("Invalid quoted value for %q in %q: %v", key, line, err)
+			}
+			pairs[key] = value
+			i = end + 1
+			continue
+		}
+
+		start = i
+		for i < len(line) && line[i] != ' ' {
+			if line[i] == '"' || line[i] == '=' {
+				t.Fatalf("Unexpected %q in bare value of %q in %q", line[i], key, line)
+			}
+			i++
+		}
+		pairs[key] = line[start:i]
+	}
+	return pairs
+}
+
+type logfmtStringer struct{}
+
+func (logfmtStringer) String() string { return "from stringer" }
+
+func TestLogfmtFormat(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatLogfmt, Clock: fixedClock(now), Prefix: "ignored "})
+
+	log.With(logger.Field{Key: "service", Value: "api"}).Warn("User logged in",
+		logger.Field{Key: "attempt", Value: 3},
+		logger.Field{Key: "ok", Value: true},
+		logger.Field{Key: "err", Value: errors.New(`open "a b": denied`)},
+		logger.Field{Key: "who", Value: logfmtStringer{}},
+		logger.Field{Key: "query", Value: "a=b"},
+		logger.Field{Key: "empty", Value: ""},
+		logger.Field{Key: "multi", Value: "line1\nline2\ttab"},
+		logger.Field{Key: "path", Value: `C:\tmp`},
+		logger.Field{Key: "bad key", Value: "v"},
+		logger.Group("http", logger.Field{Key: "method", Value: "GET"}),
+	)
+
+	line := strings.TrimSuffix(buf.String(), "\n")
+	if !strings.HasPrefix(line, "ts=2024-03-01T12:00:00Z level=warn msg=\"User logged in\" service=api attempt=3 ok=true") {
+		t.Errorf("Unexpected logfmt line: %s", line)
+	}
+	if strings.Contains(line, "\n") {
+		t.Errorf("Expected a single line, got %q", line)
+	}
+
+	expected := map[string]string{
+		"ts":          "2024-03-01T12:00:00Z",
+		"level":       "warn",
+		"msg":         "User logged in",
+		"service":     "api",
+		"attempt":     "3",
+		"ok":          "true",
+		"err":         `open "a b": denied`,
+		"who":         "from stringer",
+		"query":       "a=b",
+		"empty":       "",
+		"multi":       "line1\nline2\ttab",
+		"path":        `C:\tmp`,
+		"bad_key":     "v",
+		"http.method": "GET",
+	}
+	if got := decodeLogfmt(t, line); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected round trip to give %v, got %v", expected, got)
+	}
+}
+
+func TestLogfmtRoundTripValues(t *testing.T) {
+	values := []string{
+		"plain", "with space", `"quoted"`, "k=v", `back\slash`, "tab\there",
+		"\x00\x1f\x7f", "héllo wörld", "emoji 🎉", "trailing ", "=", `\"`,
+		string([]byte{0xff, 'a'}),
+	}
+
+	for _, value := range values {
+		t.Run(fmt.Sprintf("%q", value), func(t *testing.T) {
+			var buf bytes.Buffer
+			log := logger.New(logger.Config{Output: &buf, Format: logger.FormatLogfmt})
+			log.Info(value, logger.Field{Key: "v", Value: value})
+
+			decoded := decodeLogfmt(t, strings.TrimSuffix(buf.String(), "\n"))
+			want := strings.ToValidUTF8(value, "\uFFFD")
+			if decoded["msg"] != want || decoded["v"] != want {
+				t.Errorf("Expected %q to round trip, got msg=%q v=%q from %q", want, decoded["msg"], decoded["v"], buf.String())
+			}
+		})
+	}
+}
+
+func TestLogfmtDescribe(t *testing.T) {
+	log := logger.New(logger.Config{Output: &bytes.Buffer{}, Format: logger.FormatLogfmt})
+	if got := logger.Describe(log).Format; got != "logfmt" {
+		t.Errorf("Expected format logfmt, got %q", got)
+	}
+}
+
+func TestLogfmtCustomEntryKeys(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatLogfmt, LevelKey: "severity", Clock: fixedClock(now)})
+
+	log.Warn("slow", logger.Field{Key: "severity", Value: "high"}, logger.Field{Key: "ts", Value: 1})
+
+	expected := "ts=2024-03-01T12:00:00Z severity=warn msg=slow severity_field=high ts_field=1\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
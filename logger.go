@@ -1,12 +1,19 @@
 package logger
 
 import (
+	"bytes"
 	"context"
+	"encoding"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -44,26 +51,392 @@ type Field struct {
 	Value any
 }
 
-// Logger defines the interface for logging operations
-type Logger interface {
+// LevelLogger writes entries at each level. Its method set is stable:
+// code that only logs should depend on it rather than on Logger.
+type LevelLogger interface {
 	Debug(msg string, fields ...Field)
 	Info(msg string, fields ...Field)
 	Warn(msg string, fields ...Field)
 	Error(msg string, fields ...Field)
 	Fatal(msg string, fields ...Field)
+}
+
+// FieldLogger derives loggers carrying additional fields. Its method set
+// is stable.
+type FieldLogger interface {
 	With(fields ...Field) Logger
 	WithContext(ctx context.Context) Logger
 }
 
+// LifecycleLogger releases a logger's resources. Its method set is stable.
+type LifecycleLogger interface {
+	// Flush writes any buffered entries. Once it returns, every entry
+	// whose logging call returned before Flush was called has been passed
+	// to the output.
+	Flush() error
+	// Close flushes and releases outputs the logger opened itself, such
+	// as the file of a logger created by CreateFileLogger
+	Close() error
+}
+
+// LeveledControl inspects and changes the minimum level. Its method set is
+// stable.
+type LeveledControl interface {
+	// SetLevel changes the minimum level of the logger and every logger
+	// sharing its configuration
+	SetLevel(level Level)
+	// Enabled reports whether entries at level would be written
+	Enabled(level Level) bool
+}
+
+// Logger defines the interface for logging operations. It is composed of
+// the stable LevelLogger, FieldLogger, LifecycleLogger and LeveledControl
+// interfaces; depend on the narrowest of them that suffices. Further
+// capabilities are optional extensions such as NamedLogger and
+// FieldPusher, checked with type assertions.
+//
+// Logging methods never panic. Values whose String, Error or MarshalText
+// methods panic are rendered as "<panic>" and the failure is described in
+// a _log_internal_error field on the same entry.
+type Logger interface {
+	LevelLogger
+	FieldLogger
+	LifecycleLogger
+	LeveledControl
+}
+
 // Config holds logger configuration
 type Config struct {
 	Level      Level
 	Output     io.Writer
 	TimeFormat string
 	Prefix     string
+
+	// Format selects the encoding of entries. It defaults to FormatText.
+	// The standard library date and Prefix are only written in text format,
+	// and the date is left out when TimeFormat has sub-second precision.
+	Format Format
+
+	// TimeKey, LevelKey and MessageKey name the time, level and message in
+	// FormatJSON and FormatLogfmt entries. They default to time, level and
+	// msg in JSON and ts, level and msg in logfmt. A field with one of
+	// these keys is written with _field appended to its key.
+	TimeKey    string
+	LevelKey   string
+	MessageKey string
+
+	// Layout replaces the text format's line, for example
+	//
+	//	"{{.Level}} {{.Time}} [{{.Prefix}}] {{.Message}} {{.Fields}}"
+	//
+	// The placeholders are Time, Level, Prefix, Message and Fields; any
+	// other is a configuration error. The standard library date is not
+	// written, and Prefix only where the layout places it. Layout is only
+	// valid with FormatText.
+	Layout string
+
+	// Host is the host field of FormatGELF entries and the HOSTNAME of
+	// FormatSyslog messages. It defaults to os.Hostname.
+	Host string
+
+	// SyslogAppName is the APP-NAME of FormatSyslog messages. It defaults
+	// to the base name of the executable.
+	SyslogAppName string
+
+	// SyslogFacility is the facility number, 1 to 23, of FormatSyslog
+	// messages. Zero selects 1, user-level messages.
+	SyslogFacility int
+
+	// SyslogSDID is the SD-ID of the structured data element holding
+	// fields in FormatSyslog messages. It defaults to DefaultSyslogSDID.
+	SyslogSDID string
+
+	// ECSLabels writes fields without an ECS counterpart under labels, as
+	// strings, in FormatECS. By default they are kept as they are, with
+	// dotted keys nested into objects.
+	ECSLabels bool
+
+	// ConsoleMessageWidth is the column width messages are padded to in
+	// FormatConsole. It defaults to DefaultConsoleMessageWidth.
+	ConsoleMessageWidth int
+
+	// Color controls coloring of text and console output. The
+	// default ColorAuto colors output to a terminal unless NO_COLOR is set.
+	Color ColorMode
+
+	// ColorScheme overrides the style of the level token per level when
+	// output is colored. New panics, and NewWithError fails, if the scheme
+	// does not validate.
+	ColorScheme ColorScheme
+
+	// SortFields emits fields, including those added by options such as
+	// AddCaller and the fields of groups, in alphabetical key order instead
+	// of the default base, context, call-site order
+	SortFields bool
+
+	// ContextGroup, when set, places the fields added by WithContext, such
+	// as request_id, in a group of that name: ctx.request_id in text and a
+	// nested "ctx" object in JSON. They then no longer replace base fields
+	// with the same key.
+	ContextGroup string
+
+	// Clock returns the current time. It defaults to time.Now and can be
+	// replaced to make timestamps and durations deterministic in tests.
+	Clock func() time.Time
+
+	// TimeEncoding writes entry timestamps as epoch integers instead of
+	// strings. With TimeEncodingUnixMillis or TimeEncodingUnixNanos,
+	// TimeFormat is ignored and JSON output writes the time as a number.
+	// GELF, ECS and syslog keep the timestamps their formats require.
+	TimeEncoding TimeEncoding
+
+	// MaxFieldLength caps the length of field values in bytes. Longer
+	// strings, and values written as text such as errors, are cut and end
+	// with a marker giving the size removed, like ...(truncated 39.0MiB),
+	// before the entry is encoded. []byte values count their base64
+	// encoding. Zero means unlimited.
+	MaxFieldLength int
+
+	// MaxEntryBytes caps the size of an encoded entry, not counting the
+	// standard library date of text output and the trailing newline, for
+	// example to stay within a UDP datagram. Entries over the cap are
+	// encoded again without their largest fields, as many as needed, and
+	// carry truncated=true; the message is cut only when the entry does
+	// not fit without fields. Zero means unlimited.
+	MaxEntryBytes int
+
+	// PrettyJSON indents FormatJSON entries over several lines and
+	// separates them with a blank line, for reading logs during
+	// development. The output is no longer one entry per line, so never
+	// use it where logs are collected. It cannot be combined with
+	// MaxEntryBytes or MaxFieldLength.
+	PrettyJSON bool
+
+	// DetectMutations fingerprints pointer, slice and map values passed to
+	// With and PushFields and checks them again whenever an entry is
+	// written. A value that changed in between, which makes later entries
+	// describe a state the caller never logged, is reported once with a
+	// warning giving where it was captured and where it was logged. It
+	// hashes those values on every entry, so it is meant for debugging;
+	// pass values that must not change through Snapshot.
+	DetectMutations bool
+
+	// DuplicateKeys controls which value is written when base, context
+	// and call-site fields repeat a key. It defaults to DuplicateLastWins.
+	DuplicateKeys DuplicateKeyPolicy
+
+	// Multiline controls how line breaks in messages and field values are
+	// written by FormatText, layouts and FormatSyslog. It defaults to
+	// MultilineEscape, which keeps every entry on one line.
+	Multiline MultilinePolicy
+
+	// TimePrecision is the smallest difference between timestamps that
+	// must stay visible in TimeFormat, one second by default. New warns
+	// when TimeFormat loses it, or loses date or time components or the
+	// time zone offset; in Strict mode that is a configuration error.
+	// See CheckTimeFormat.
+	TimePrecision time.Duration
+
+	// UTC converts timestamps to UTC before they are formatted, including
+	// the standard library date of text output. It is shorthand for
+	// Location: time.UTC.
+	UTC bool
+
+	// Location converts timestamps to a time zone before they are
+	// formatted, taking precedence over UTC. Timestamps are in local time
+	// when neither is set.
+	Location *time.Location
+
+	// SpanTimestamps adds a companion <key>_at field holding the reference
+	// time to fields created with Since and Until
+	SpanTimestamps bool
+
+	// Destination tells the logger what kind of output it writes to so
+	// fields marked with FileOnly or ConsoleOnly can be filtered. The zero
+	// value includes every field.
+	Destination Destination
+
+	// AddCaller adds a caller field with the file and line of the logging
+	// call, after the call-site fields. Frames inside this package, such
+	// as the package-level helpers, are skipped.
+	AddCaller bool
+
+	// TrimCallerPath writes the caller as the package import path and file
+	// name, such as github.com/acme/api/handlers/user.go:42, instead of the
+	// absolute path of the file on the build machine
+	TrimCallerPath bool
+
+	// AddStackTrace adds a stack field with the call stack of the logging
+	// call, after the caller field, to entries at or above
+	// StackTraceLevel. The stack is only captured for entries that are
+	// written. Text output lists the frames on one line separated by
+	// semicolons; JSON and logfmt write one frame per line of the string.
+	AddStackTrace   bool
+	StackTraceLevel Level
+
+	// LevelEncoder renders the level of each entry in every format. It
+	// defaults to UppercaseLevelEncoder, or LowercaseLevelEncoder with
+	// FormatLogfmt and FormatECS.
+	LevelEncoder LevelEncoder
+
+	// IncludeDelta adds a delta_ms field with the milliseconds elapsed
+	// since the previous entry, measured when each logging call is made.
+	// The first entry reports 0.
+	IncludeDelta bool
+
+	// DeltaScope selects which entries share the previous-entry time used
+	// by IncludeDelta
+	DeltaScope DeltaScope
+
+	// ProviderTimeout bounds how long a Provided field may take to produce
+	// its value. Slower providers are replaced by a marker. It defaults to
+	// DefaultProviderTimeout.
+	ProviderTimeout time.Duration
+
+	// HumanReadable renders Bytes fields with binary units such as 10.0MiB
+	// and rounds durations to three significant digits in text output
+	HumanReadable bool
+
+	// GroupDigits separates thousands in integer values with underscores,
+	// such as 1_048_576, in text output
+	GroupDigits bool
+
+	// DurationPrecision is the unit Duration fields are rounded to in text
+	// output, such as time.Millisecond. By default they keep three
+	// significant digits.
+	DurationPrecision time.Duration
+
+	// RawUnits adds a <key>_ms field after Duration fields and a
+	// <key>_bytes field after Bytes fields in text output, holding the
+	// unrounded number for parsers next to the form meant for people
+	RawUnits bool
+
+	// Enrichers add fields to entries at or above a level, see EnrichAt
+	Enrichers []EnrichRule
+
+	// FatalConcurrencyPolicy selects what happens to entries logged from
+	// other goroutines after Fatal has been called. It defaults to
+	// FatalBlock.
+	FatalConcurrencyPolicy FatalPolicy
+
+	// FatalTimeout bounds how long FatalBlock holds concurrent calls. It
+	// defaults to DefaultFatalTimeout.
+	FatalTimeout time.Duration
+
+	// ExitFunc is called with status 1 after a Fatal entry is written. It
+	// defaults to os.Exit and can be replaced in tests. If it returns, the
+	// logger and everything derived from it stay finished: later entries
+	// are dropped without waiting, so create a new logger to go on.
+	ExitFunc func(code int)
+
+	// ClockStepThreshold enables clock step detection. When the wall
+	// clock moves more than this much further or less far than the
+	// monotonic clock between two entries, a Warn entry tagged
+	// clock_step_detected=true is written and following entries carry
+	// clock_suspect=true for ClockSuspectWindow. Zero disables detection.
+	ClockStepThreshold time.Duration
+
+	// ClockSuspectWindow is how long entries are marked clock_suspect after
+	// a step. It defaults to DefaultClockSuspectWindow.
+	ClockSuspectWindow time.Duration
+
+	// MonotonicClock returns the time elapsed on a clock that never steps,
+	// for clock step detection. It defaults to the process's monotonic
+	// clock and can be replaced in tests together with Clock.
+	MonotonicClock func() time.Duration
+
+	// AllowLevelRaise lets a WithMinLevel context override raise the
+	// minimum level as well as lower it
+	AllowLevelRaise bool
+
+	// SchemaObserver, if set, samples the fields of written entries to
+	// infer a type per key. Collection is off when it is nil.
+	SchemaObserver *SchemaObserver
+
+	// KeyGuard, if set, limits the number of distinct field keys written,
+	// see NewKeyGuard
+	KeyGuard *KeyGuard
+
+	// MemoryGovernor, if set, suppresses expensive enrichments while the
+	// process is under memory pressure, see NewMemoryGovernor
+	MemoryGovernor *MemoryGovernor
+
+	// DiskWatchdog degrades logging as the filesystem of the log file
+	// fills up. It is meant for file outputs; see DiskWatchdog.
+	DiskWatchdog *DiskWatchdog
+
+	// Strict enables checks meant for tests and development: codes that
+	// were not registered with RegisterCode are rejected, and entries with
+	// values that cannot be rendered, such as channels, funcs or cyclic
+	// structures, get an _unloggable_fields count
+	Strict bool
+
+	// OnStrictViolation receives the problems found in Strict mode, for
+	// example to fail a test. When nil they are reported in a
+	// _log_strict_violation field on the offending entry.
+	OnStrictViolation func(error)
+
+	// OnError receives the logger's own failures: a *WriteError when the
+	// output rejects an entry, wrapping ErrSinkUnavailable once it has
+	// been stopped, and a *FormatError when part of an entry could not be
+	// rendered. It is called with the logger locked and must not log to
+	// the same logger.
+	OnError func(error)
+
+	// OutputName identifies the output in health reports and descriptions.
+	// It defaults to stdout, stderr, the file name or the writer's type.
+	OutputName string
+
+	// BrokenPipeLimit is the number of consecutive broken pipe errors,
+	// such as EPIPE after the reader of a piped standard output exits,
+	// after which the logger stops writing to Output. It defaults to
+	// DefaultBrokenPipeLimit; a negative value never stops. SetOutput
+	// resumes writing.
+	BrokenPipeLimit int
+
+	// FallbackOutput receives the entries of a logger that stopped writing
+	// to Output. Without it they are dropped.
+	FallbackOutput io.Writer
+
+	// IgnoreSIGPIPE subscribes to SIGPIPE when Output is os.Stdout or
+	// os.Stderr, or becomes one through SetOutput, so a write to a broken
+	// standard output returns EPIPE instead of terminating the process.
+	// It changes SIGPIPE handling for the whole program and is off by
+	// default.
+	IgnoreSIGPIPE bool
+
+	// LogStartupSummary makes New write one Info entry describing the
+	// effective configuration, tagged logger_startup=true. It is written
+	// even when Level filters out Info entries.
+	LogStartupSummary bool
 }
 
-// DefaultConfig provides sensible defaults
+// DeltaScope selects which loggers share delta_ms tracking
+type DeltaScope int
+
+const (
+	// DeltaPerRoot tracks the previous entry across a logger created by
+	// New and everything derived from it
+	DeltaPerRoot DeltaScope = iota
+	// DeltaPerContext starts fresh tracking for every logger derived with
+	// WithContext, so each request's trail has its own deltas
+	DeltaPerContext
+)
+
+// Destination identifies the kind of output a logger writes to
+type Destination int
+
+const (
+	// DestinationAny includes all fields regardless of their visibility
+	DestinationAny Destination = iota
+	// DestinationConsole skips fields marked with FileOnly
+	DestinationConsole
+	// DestinationFile skips fields marked with ConsoleOnly
+	DestinationFile
+)
+
+// DefaultConfig provides sensible defaults. Timestamps are in local time;
+// set UTC on a copy, or use DefaultFactory.UTC(), to log in UTC.
 var DefaultConfig = Config{
 	Level:      InfoLevel,
 	Output:     os.Stdout,
@@ -73,70 +446,740 @@ var DefaultConfig = Config{
 
 // standardLogger implements Logger using Go's standard log package
 type standardLogger struct {
-	logger     *log.Logger
-	level      Level
-	timeFormat string
-	fields     []Field
-	mu         sync.Mutex
+	logger       *log.Logger
+	level        *levelState
+	timeFormat   string
+	multiline    MultilinePolicy
+	maxFieldLen  int
+	maxEntry     int
+	duplicates   DuplicateKeyPolicy
+	detectMut    bool
+	pretty       bool
+	format       Format
+	sortFields   bool
+	clock        func() time.Time
+	spanTimes    bool
+	dest         Destination
+	encodeLvl    LevelEncoder
+	delta        *deltaTracker
+	deltaScope   DeltaScope
+	out          *outputState
+	provideDur   time.Duration
+	providers    *providerWorker
+	human        bool
+	grouping     bool
+	durPrecision time.Duration
+	rawUnits     bool
+	strict       bool
+	onViolate    func(error)
+	onError      func(error)
+	enrichers    []EnrichRule
+	enrichMin    Level
+	colors       map[Level]string
+	host         string
+	ecsLabels    bool
+	syslog       syslogHeader
+	consoleWidth int
+	layout       []layoutPart
+	timeKey      string
+	levelKey     string
+	msgKey       string
+	prefix       string
+	addCaller    bool
+	trimCaller   bool
+	addStack     bool
+	stackLevel   Level
+	gate         *fatalGate
+	exit         func(code int)
+	fatalPolicy  FatalPolicy
+	fatalTimeout time.Duration
+	name         string
+	noVolume     bool
+	clockMon     *clockMonitor
+	schema       *SchemaObserver
+	keyGuard     *KeyGuard
+	governor     *MemoryGovernor
+	disk         *DiskWatchdog
+	extractors   []contextField // nil uses the global registry
+	excluded     map[string]bool
+	override     *Level // minimum level from WithMinLevel, if any
+	allowRaise   bool
+	fields       []Field // base fields in With() application order
+	ctxFields    []Field // fields derived from WithContext
+	captured     []*capturedValue
+	ctxGroup     string
+	pushed       []pushedFields
+	pushSeq      uint64
+	mu           sync.Mutex
+}
+
+// pushedFields is one layer of fields added with PushFields
+type pushedFields struct {
+	id       uint64
+	fields   []Field
+	captured []*capturedValue
+}
+
+// NewWithError is New returning configuration errors, such as an
+// invalid Layout or ColorScheme, instead of panicking
+func NewWithError(cfg Config) (Logger, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return New(cfg), nil
+}
+
+// validate checks the settings New cannot correct
+func (cfg Config) validate() error {
+	if err := cfg.ColorScheme.Validate(); err != nil {
+		return err
+	}
+	if cfg.Layout != "" {
+		if cfg.Format != FormatText {
+			return fmt.Errorf("layout: not supported with format %s", cfg.Format)
+		}
+		if _, err := parseLayout(cfg.Layout); err != nil {
+			return err
+		}
+	}
+	if cfg.PrettyJSON {
+		if cfg.Format != FormatJSON {
+			return fmt.Errorf("pretty JSON: not supported with format %s", cfg.Format)
+		}
+		if cfg.MaxEntryBytes > 0 || cfg.MaxFieldLength > 0 {
+			return fmt.Errorf("pretty JSON: not supported with MaxEntryBytes or MaxFieldLength")
+		}
+	}
+	if cfg.Strict && cfg.usesTimeFormat() {
+		if err := CheckTimeFormat(cfg.TimeFormat, cfg.TimePrecision); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
+// New returns a logger for cfg. It panics if the configuration is
+// invalid; use NewWithError to handle that as an error.
 func New(cfg Config) Logger {
+	if err := cfg.validate(); err != nil {
+		panic("logger: " + err.Error())
+	}
+	layout, _ := parseLayout(cfg.Layout)
 	if cfg.Output == nil {
 		cfg.Output = DefaultConfig.Output
 	}
+	switch cfg.TimeEncoding {
+	case TimeEncodingUnixMillis:
+		cfg.TimeFormat = TimeFormatUnixMillis
+	case TimeEncodingUnixNanos:
+		cfg.TimeFormat = TimeFormatUnixNanos
+	}
 	if cfg.TimeFormat == "" {
 		cfg.TimeFormat = DefaultConfig.TimeFormat
 	}
+	if cfg.Clock == nil {
+		cfg.Clock = time.Now
+	}
+	if cfg.Location == nil && cfg.UTC {
+		cfg.Location = time.UTC
+	}
+	if loc := cfg.Location; loc != nil {
+		clock := cfg.Clock
+		cfg.Clock = func() time.Time { return clock().In(loc) }
+	}
+	if cfg.ProviderTimeout <= 0 {
+		cfg.ProviderTimeout = DefaultProviderTimeout
+	}
+	if cfg.LevelEncoder == nil && (cfg.Format == FormatLogfmt || cfg.Format == FormatECS) {
+		cfg.LevelEncoder = LowercaseLevelEncoder
+	}
+	if cfg.LevelEncoder == nil {
+		cfg.LevelEncoder = UppercaseLevelEncoder
+	}
+	if (cfg.Format == FormatGELF || cfg.Format == FormatSyslog) && cfg.Host == "" {
+		cfg.Host = defaultHost()
+	}
+	if cfg.ConsoleMessageWidth <= 0 {
+		cfg.ConsoleMessageWidth = DefaultConsoleMessageWidth
+	}
+	if cfg.BrokenPipeLimit == 0 {
+		cfg.BrokenPipeLimit = DefaultBrokenPipeLimit
+	}
+	if cfg.FatalTimeout <= 0 {
+		cfg.FatalTimeout = DefaultFatalTimeout
+	}
+	if cfg.ExitFunc == nil {
+		cfg.ExitFunc = os.Exit
+	}
+
+	flags := log.LstdFlags
+	if cfg.Location == time.UTC {
+		flags |= log.LUTC
+	}
+	if subsecondFormat(cfg.TimeFormat) {
+		// A second-resolution date in front of a precise timestamp only
+		// makes entries look tied
+		flags = 0
+	}
+	logger := log.New(cfg.Output, cfg.Prefix, flags)
+	if cfg.Format != FormatText || layout != nil {
+		logger = log.New(cfg.Output, "", 0)
+	}
+	if cfg.IgnoreSIGPIPE {
+		guardBrokenPipe(cfg.Output)
+	}
+	var fallback *log.Logger
+	if cfg.FallbackOutput != nil {
+		fallback = log.New(cfg.FallbackOutput, logger.Prefix(), logger.Flags())
+	}
+
+	var clockMon *clockMonitor
+	if cfg.ClockStepThreshold > 0 {
+		if cfg.ClockSuspectWindow <= 0 {
+			cfg.ClockSuspectWindow = DefaultClockSuspectWindow
+		}
+		if cfg.MonotonicClock == nil {
+			cfg.MonotonicClock = monotonicSince()
+		}
+		clockMon = newClockMonitor(cfg.MonotonicClock, cfg.ClockStepThreshold, cfg.ClockSuspectWindow)
+	}
+
+	var delta *deltaTracker
+	if cfg.IncludeDelta {
+		delta = newDeltaTracker(cfg.Clock())
+	}
+
+	l := &standardLogger{
+		logger:       logger,
+		level:        newLevelState(cfg.Level),
+		timeFormat:   cfg.TimeFormat,
+		multiline:    cfg.Multiline,
+		maxFieldLen:  cfg.MaxFieldLength,
+		maxEntry:     cfg.MaxEntryBytes,
+		duplicates:   cfg.DuplicateKeys,
+		detectMut:    cfg.DetectMutations,
+		pretty:       cfg.PrettyJSON,
+		format:       cfg.Format,
+		sortFields:   cfg.SortFields,
+		ctxGroup:     cfg.ContextGroup,
+		clock:        cfg.Clock,
+		spanTimes:    cfg.SpanTimestamps,
+		dest:         cfg.Destination,
+		encodeLvl:    cfg.LevelEncoder,
+		delta:        delta,
+		deltaScope:   cfg.DeltaScope,
+		out:          &outputState{name: cfg.OutputName, brokenLimit: cfg.BrokenPipeLimit, fallback: fallback, ignoreSIGPIPE: cfg.IgnoreSIGPIPE},
+		provideDur:   cfg.ProviderTimeout,
+		providers:    &providerWorker{},
+		human:        cfg.HumanReadable,
+		grouping:     cfg.GroupDigits,
+		durPrecision: cfg.DurationPrecision,
+		rawUnits:     cfg.RawUnits,
+		strict:       cfg.Strict,
+		onViolate:    cfg.OnStrictViolation,
+		onError:      cfg.OnError,
+		enrichers:    append([]EnrichRule(nil), cfg.Enrichers...),
+		enrichMin:    minEnrichLevel(cfg.Enrichers),
+		gate:         newFatalGate(),
+		exit:         cfg.ExitFunc,
+		fatalPolicy:  cfg.FatalConcurrencyPolicy,
+		fatalTimeout: cfg.FatalTimeout,
+		allowRaise:   cfg.AllowLevelRaise,
+		clockMon:     clockMon,
+		schema:       cfg.SchemaObserver,
+		keyGuard:     cfg.KeyGuard,
+		governor:     cfg.MemoryGovernor,
+		disk:         cfg.DiskWatchdog,
+		host:         cfg.Host,
+		ecsLabels:    cfg.ECSLabels,
+		syslog:       newSyslogHeader(cfg),
+		consoleWidth: cfg.ConsoleMessageWidth,
+		layout:       layout,
+		timeKey:      cfg.TimeKey,
+		levelKey:     cfg.LevelKey,
+		msgKey:       cfg.MessageKey,
+		prefix:       cfg.Prefix,
+		addCaller:    cfg.AddCaller,
+		trimCaller:   cfg.TrimCallerPath,
+		addStack:     cfg.AddStackTrace,
+		stackLevel:   cfg.StackTraceLevel,
+		fields:       []Field{},
+	}
+	if cfg.Format.colorable() {
+		l.colors = levelColors(cfg.ColorScheme)
+		l.out.colorMode = cfg.Color
+		l.out.updateColor(cfg.Output)
+	}
+
+	if cfg.usesTimeFormat() {
+		if err := CheckTimeFormat(cfg.TimeFormat, cfg.TimePrecision); err != nil {
+			l.warnTimeFormat(err)
+		}
+	}
+	if cfg.LogStartupSummary {
+		l.logStartupSummary()
+	}
+	if l.disk != nil {
+		l.disk.attach(l)
+	}
+	return l
+}
+
+// warnTimeFormat writes a Warn entry describing what the time format
+// loses, whatever the level
+func (l *standardLogger) warnTimeFormat(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.writeEntry(WarnLevel, "time format loses information", []Field{
+		{Key: "time_format", Value: l.timeFormat},
+		{Key: "error", Value: err},
+	}, l.clock())
+}
+
+// clone returns a copy of the logger sharing its output and configuration,
+// with its own copy of the field groups
+func (l *standardLogger) clone() *standardLogger {
+	newLogger := &standardLogger{
+		logger:       l.logger,
+		level:        l.level,
+		timeFormat:   l.timeFormat,
+		multiline:    l.multiline,
+		maxFieldLen:  l.maxFieldLen,
+		maxEntry:     l.maxEntry,
+		duplicates:   l.duplicates,
+		detectMut:    l.detectMut,
+		pretty:       l.pretty,
+		captured:     l.captured[:len(l.captured):len(l.captured)],
+		format:       l.format,
+		sortFields:   l.sortFields,
+		clock:        l.clock,
+		spanTimes:    l.spanTimes,
+		dest:         l.dest,
+		encodeLvl:    l.encodeLvl,
+		delta:        l.delta,
+		deltaScope:   l.deltaScope,
+		out:          l.out,
+		provideDur:   l.provideDur,
+		providers:    l.providers,
+		human:        l.human,
+		grouping:     l.grouping,
+		durPrecision: l.durPrecision,
+		rawUnits:     l.rawUnits,
+		strict:       l.strict,
+		onViolate:    l.onViolate,
+		onError:      l.onError,
+		enrichers:    l.enrichers,
+		enrichMin:    l.enrichMin,
+		colors:       l.colors,
+		host:         l.host,
+		ecsLabels:    l.ecsLabels,
+		syslog:       l.syslog,
+		consoleWidth: l.consoleWidth,
+		layout:       l.layout,
+		timeKey:      l.timeKey,
+		levelKey:     l.levelKey,
+		msgKey:       l.msgKey,
+		prefix:       l.prefix,
+		addCaller:    l.addCaller,
+		trimCaller:   l.trimCaller,
+		addStack:     l.addStack,
+		stackLevel:   l.stackLevel,
+		gate:         l.gate,
+		exit:         l.exit,
+		fatalPolicy:  l.fatalPolicy,
+		fatalTimeout: l.fatalTimeout,
+		name:         l.name,
+		noVolume:     l.noVolume,
+		clockMon:     l.clockMon,
+		schema:       l.schema,
+		keyGuard:     l.keyGuard,
+		governor:     l.governor,
+		disk:         l.disk,
+		extractors:   l.extractors,
+		excluded:     l.excluded,
+		override:     l.override,
+		allowRaise:   l.allowRaise,
+		fields:       make([]Field, len(l.fields)),
+		ctxFields:    make([]Field, len(l.ctxFields)),
+		ctxGroup:     l.ctxGroup,
+	}
+	copy(newLogger.fields, l.fields)
+	copy(newLogger.ctxFields, l.ctxFields)
+	return newLogger
+}
 
-	logger := log.New(cfg.Output, cfg.Prefix, log.LstdFlags)
+// entryFields combines the field groups of an entry in their documented
+// order: base fields, then context fields, then pushed fields, then
+// call-site fields.
+func (l *standardLogger) entryFields(fields []Field) []Field {
+	size := len(l.fields) + len(l.ctxFields) + expandedLen(fields)
+	for _, layer := range l.pushed {
+		size += len(layer.fields)
+	}
 
-	return &standardLogger{
-		logger:     logger,
-		level:      cfg.Level,
-		timeFormat: cfg.TimeFormat,
-		fields:     []Field{},
+	allFields := make([]Field, 0, size)
+	allFields = append(allFields, l.fields...)
+	if l.ctxGroup != "" && len(l.ctxFields) > 0 {
+		allFields = append(allFields, Group(l.ctxGroup, l.ctxFields...))
+	} else {
+		allFields = append(allFields, l.ctxFields...)
+	}
+	for _, layer := range l.pushed {
+		allFields = append(allFields, layer.fields...)
 	}
+	return appendExpanded(allFields, fields)
 }
 
-// formatFields converts fields to a string representation
+// sortFields orders fields by key, keeping the relative order of equal
+// keys, and sorts the fields of groups the same way. Group values are
+// copied rather than sorted in place, since they belong to the caller.
+func sortFields(fields []Field) {
+	sort.SliceStable(fields, func(i, j int) bool {
+		return fields[i].Key < fields[j].Key
+	})
+	for i, field := range fields {
+		if group, ok := field.Value.(groupValue); ok {
+			sorted := append(groupValue(nil), group...)
+			sortFields(sorted)
+			fields[i].Value = sorted
+		}
+	}
+}
+
+// internalErrorKey is the field used to report failures that happened
+// while rendering user-provided values
+const internalErrorKey = "_log_internal_error"
+
+// formatValue renders a field value, recovering from panics raised by
+// user-provided String, Error or MarshalText methods
+func formatValue(value any) (s string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s = "<panic>"
+			err = fmt.Errorf("panic rendering value of type %T: %v", value, r)
+		}
+	}()
+
+	// The types of the typed constructors are written without fmt
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	}
+
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return "<nil>", nil
+	}
+
+	switch v := value.(type) {
+	case error:
+		return v.Error(), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	case encoding.TextMarshaler:
+		text, err := v.MarshalText()
+		if err != nil {
+			return "<error>", fmt.Errorf("marshal text of type %T: %w", value, err)
+		}
+		return string(text), nil
+	default:
+		if s, ok := formatUnloggable(value); ok {
+			return s, nil
+		}
+		return fmt.Sprintf("%v", value), nil
+	}
+}
+
+// formatFields converts fields to a string representation. Rendering
+// failures are reported through an additional _log_internal_error field
+// rather than aborting the entry.
 func (l *standardLogger) formatFields(fields []Field) string {
 	if len(fields) == 0 {
 		return ""
 	}
 
-	result := "{"
-	for i, field := range fields {
-		if i > 0 {
-			result += " "
+	var b strings.Builder
+	var failures []string
+	b.WriteString("{")
+	l.writeTextFields(&b, "", fields, &failures)
+	if len(failures) > 0 {
+		b.WriteString(" " + internalErrorKey + "=" + strings.Join(failures, "; "))
+		l.formatFailed(failures)
+	}
+	b.WriteString("}")
+
+	return b.String()
+}
+
+// writeTextFields renders fields as space separated key=value pairs.
+// Grouped fields are expanded with their keys prefixed by the group name.
+func (l *standardLogger) writeTextFields(b *strings.Builder, prefix string, fields []Field, failures *[]string) {
+	for _, field := range fields {
+		key := prefix + field.Key
+		if group, ok := field.Value.(groupValue); ok {
+			l.writeTextFields(b, key+".", group, failures)
+			continue
+		}
+
+		if b.Len() > 1 {
+			b.WriteString(" ")
+		}
+		value, err := l.formatTextValue(field.Value)
+		if err != nil {
+			*failures = append(*failures, fmt.Sprintf("%s: %v", key, err))
+		}
+		writeTextToken(b, key)
+		b.WriteString("=")
+		l.writeTextValue(b, value)
+		if l.rawUnits {
+			if suffix, raw, ok := rawUnit(field.Value); ok {
+				b.WriteString(" ")
+				writeTextToken(b, key+suffix)
+				b.WriteString("=" + raw)
+			}
 		}
-		result += fmt.Sprintf("%s=%v", field.Key, field.Value)
 	}
-	result += "}"
+}
 
-	return result
+// writeTextToken writes a key or value bare, or quoted with JSON escapes
+// when it is empty or contains spaces, equals signs, quotes, backslashes,
+// braces or control characters, any of which would make the field list
+// ambiguous
+func writeTextToken(b *strings.Builder, s string) {
+	if !needsLogfmtQuote(s) && !strings.ContainsAny(s, "{}") {
+		b.WriteString(s)
+		return
+	}
+	var quoted bytes.Buffer
+	writeJSONString(&quoted, s)
+	b.Write(quoted.Bytes())
 }
 
+// log formats and writes a single entry. It never panics: failures while
+// rendering user-provided values are reported in the entry itself and the
+// mutex is always released through defer. Once a Fatal entry has started
+// the exit sequence, other calls follow Config.FatalConcurrencyPolicy.
 func (l *standardLogger) log(level Level, msg string, fields ...Field) {
-	if level < l.level {
+	// Filtered entries return before any locking or allocation
+	if !l.enabled(level) {
+		return
+	}
+
+	if level == FatalLevel {
+		l.fatal(msg, fields)
 		return
 	}
+	if l.gate.closed() {
+		l.gate.hold(l.fatalPolicy, l.fatalTimeout)
+		return
+	}
+
+	l.write(level, msg, fields)
+}
+
+// enabled reports whether entries at the given level are written
+func (l *standardLogger) enabled(level Level) bool {
+	if level < l.minLevel() {
+		return false
+	}
+	if atomic.LoadInt32(&l.out.floor) != 0 && !l.aboveFloor(level) {
+		return false
+	}
+	if l.disk != nil {
+		return l.disk.Stage().allows(level)
+	}
+	return true
+}
+
+// minLevel returns the effective minimum level, taking a context override
+// into account
+func (l *standardLogger) minLevel() Level {
+	configured := l.level.get()
+	if l.override == nil {
+		return configured
+	}
+	if *l.override < configured || l.allowRaise {
+		return *l.override
+	}
+	return configured
+}
+
+func (l *standardLogger) Enabled(level Level) bool {
+	return l.enabled(level)
+}
+
+func (l *standardLogger) SetLevel(level Level) {
+	l.level.set(level)
+}
+
+// levelState is the minimum level shared by a logger created by New and
+// everything derived from it
+type levelState struct {
+	level int32
+}
+
+func newLevelState(level Level) *levelState {
+	return &levelState{level: int32(level)}
+}
+
+func (s *levelState) get() Level {
+	return Level(atomic.LoadInt32(&s.level))
+}
+
+func (s *levelState) set(level Level) {
+	atomic.StoreInt32(&s.level, int32(level))
+}
+
+// clockOf returns the clock of l, so that durations measured around its
+// entries follow Config.Clock, or time.Now for loggers without one
+func clockOf(l LevelLogger) func() time.Time {
+	if c, ok := l.(interface{ now() time.Time }); ok {
+		return c.now
+	}
+	return time.Now
+}
+
+func (l *standardLogger) now() time.Time {
+	return l.clock()
+}
 
+func (l *standardLogger) write(level Level, msg string, fields []Field) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	// Combie base fields with method fields
-	allFields := append(l.fields, fields...)
+	now := l.clock()
+	if l.clockMon != nil {
+		step, suspect := l.clockMon.check(now)
+		if step != 0 {
+			l.writeEntry(WarnLevel, "clock step detected", []Field{
+				{Key: "clock_step_detected", Value: true},
+				{Key: "delta", Value: step},
+			}, now)
+		}
+		if suspect {
+			fields = append(fields[:len(fields):len(fields)], Field{Key: "clock_suspect", Value: true})
+		}
+	}
+	if l.detectMut {
+		l.checkCaptured(now)
+	}
 
-	// Format the log entry
-	timestamp := time.Now().Format(l.timeFormat)
-	formattedFields := l.formatFields(allFields)
+	l.writeEntry(level, msg, fields, now)
+	releaseFieldSets(fields)
+}
 
-	// Log entry format: timestamp [LEVEL] message {fields}
-	l.logger.Printf("%s [%s] %s %s", timestamp, level.String(), msg, formattedFields)
+// writeEntry formats and writes one entry. The caller holds l.mu.
+func (l *standardLogger) writeEntry(level Level, msg string, fields []Field, now time.Time) {
+	// Combine base, context and method fields
+	allFields := l.resolveFields(l.entryFields(fields), now)
+	if l.addCaller {
+		allFields = append(allFields, Field{Key: "caller", Value: l.caller()})
+	}
+	suppress := l.governor != nil && l.governor.check(now)
+	suppressed := false
+	if l.addStack && level >= l.stackLevel {
+		if suppress && l.governor.governs("stack") {
+			suppressed = true
+		} else {
+			allFields = append(allFields, Field{Key: "stack", Value: stackTrace(callerFrames(32))})
+		}
+	}
+	if l.delta != nil {
+		allFields = append(allFields, Field{Key: "delta_ms", Value: l.delta.since(now)})
+	}
+	allFields, skipped := l.enrich(level, msg, now, allFields, suppress)
+	if suppress && l.governor.governs(LargeValues) && l.governor.shrinkLargeValues(allFields) {
+		suppressed = true
+	}
+	if suppressed || skipped {
+		allFields = append(allFields, Field{Key: suppressedKey, Value: "memory_pressure"})
+	}
+	allFields = resolveDuplicates(allFields, l.duplicates)
+	if l.maxFieldLen > 0 {
+		l.truncateFields(allFields)
+	}
+	if l.strict {
+		allFields = l.checkUnloggable(allFields)
+	}
+	if l.keyGuard != nil {
+		allFields = l.keyGuard.filter(allFields)
+	}
+	if l.sortFields {
+		// allFields was built for this entry and may be reordered
+		sortFields(allFields)
+	}
+	if l.schema != nil {
+		l.schema.observe(allFields)
+	}
 
-	// Exit on fatal errors
-	if level == FatalLevel {
-		os.Exit(1)
+	if l.format == FormatText || l.format == FormatSyslog {
+		msg = l.multilineMessage(msg)
+	}
+
+	line := l.encode(now, level, msg, allFields)
+	if l.maxEntry > 0 && len(line) > l.maxEntry {
+		line = l.shrinkEntry(now, level, msg, allFields)
+	}
+	if atomic.LoadInt32(&l.out.broken) != 0 {
+		// The reader is gone: divert to the fallback or drop the entry
+		if l.out.fallback == nil {
+			l.writeFailed(l.Health().Name, ErrSinkUnavailable)
+		} else if err := l.out.fallback.Output(2, line); err != nil {
+			l.writeFailed(describeOutput(l.out.fallback.Writer()), err)
+		}
+	} else {
+		var werr *WriteError
+		err := l.logger.Output(2, line)
+		if err != nil {
+			werr = l.writeFailed(l.Health().Name, err)
+		}
+		firstFailure, stopped := l.out.record(werr, now)
+		if firstFailure {
+			reportOutputFailure(werr.Dest, err)
+		}
+		if stopped {
+			reportBrokenPipe(l.Health().Name, l.out.brokenLimit, l.out.fallback != nil)
+		}
+	}
+	if !l.noVolume {
+		// Volume and SLOs count entries once, not once per MultiLogger child
+		recordVolume(l.name, now, len(line)+1)
+		countSLOs(level, allFields)
+	}
+}
+
+// encode renders an entry in the configured format
+func (l *standardLogger) encode(now time.Time, level Level, msg string, fields []Field) string {
+	switch l.format {
+	case FormatJSON:
+		if l.pretty {
+			return indentJSON(l.encodeJSON(now, level, msg, fields))
+		}
+		return l.encodeJSON(now, level, msg, fields)
+	case FormatLogfmt:
+		return l.encodeLogfmt(now, level, msg, fields)
+	case FormatGELF:
+		return l.encodeGELF(now, level, msg, fields)
+	case FormatECS:
+		return l.encodeECS(now, level, msg, fields)
+	case FormatSyslog:
+		return l.encodeSyslog(now, level, msg, fields)
+	case FormatConsole:
+		return l.encodeConsole(now, level, msg, fields)
+	default:
+		if l.layout != nil {
+			return l.encodeLayout(now, level, msg, fields)
+		}
+		// Log entry format: timestamp [LEVEL] message {fields}
+		timestamp := formatTime(now, l.timeFormat)
+		return fmt.Sprintf("%s [%s] %s %s", timestamp, l.colorLevel(level, l.encodeLvl(level)), msg, l.formatFields(fields))
 	}
 }
 
@@ -160,52 +1203,173 @@ func (l *standardLogger) Fatal(msg string, fields ...Field) {
 	l.log(FatalLevel, msg, fields...)
 }
 
+func (l *standardLogger) WarnCode(code, msg string, fields ...Field) {
+	l.log(WarnLevel, msg, withCode(code, fields)...)
+}
+
+func (l *standardLogger) ErrorCode(code, msg string, fields ...Field) {
+	l.log(ErrorLevel, msg, withCode(code, fields)...)
+}
+
 // With returns a new logger with the given fields added
 func (l *standardLogger) With(fields ...Field) Logger {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	newLogger := &standardLogger{
-		logger:     l.logger,
-		level:      l.level,
-		timeFormat: l.timeFormat,
-		fields:     make([]Field, len(l.fields), len(l.fields)+len(fields)),
+	newLogger := l.clone()
+	newLogger.fields = append(newLogger.fields, fields...)
+	if l.detectMut {
+		newLogger.captured = l.captureValues(newLogger.captured, "", fields, l.caller())
 	}
+	return newLogger
+}
 
-	copy(newLogger.fields, l.fields)
+// WithLazy returns a handle that derives With(fields...) on first use
+func (l *standardLogger) WithLazy(fields ...Field) Logger {
+	return newLazy(l, fields)
+}
 
-	newLogger.fields = append(newLogger.fields, fields...)
+// Named returns a logger with name appended to the logger's name
+func (l *standardLogger) Named(name string) Logger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	newLogger := l.clone()
+	if newLogger.name != "" && name != "" {
+		newLogger.name += "." + name
+	} else if name != "" {
+		newLogger.name = name
+	}
+	newLogger.fields = setField(newLogger.fields, Field{Key: "logger", Value: newLogger.name})
 	return newLogger
 }
 
-// WithContext returns a new logger with context values
+// PushFields layers fields onto this logger until undo is called. Layers
+// can be undone in any order and calling undo more than once is harmless.
+func (l *standardLogger) PushFields(fields ...Field) (undo func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.pushSeq++
+	id := l.pushSeq
+	layer := make([]Field, len(fields))
+	copy(layer, fields)
+	pushed := pushedFields{id: id, fields: layer}
+	if l.detectMut {
+		pushed.captured = l.captureValues(nil, "", layer, l.caller())
+	}
+	l.pushed = append(l.pushed, pushed)
+
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		for i, p := range l.pushed {
+			if p.id == id {
+				l.pushed = append(l.pushed[:i:i], l.pushed[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// WithContext returns a new logger with context values. Context-derived
+// fields replace any existing field with the same key, keeping its
+// position, instead of being appended again, so applying the same context
+// repeatedly is idempotent.
 func (l *standardLogger) WithContext(ctx context.Context) Logger {
-	// Start with the current logger's fields
-	newFields := make([]Field, len(l.fields))
-	copy(newFields, l.fields)
+	l.mu.Lock()
+	newLogger := l.clone()
+	l.mu.Unlock()
 
-	// Add request ID if available
-	if requestID, ok := GetRequestID(ctx); ok {
-		newFields = append(newFields, Field{Key: "request_id", Value: requestID})
+	if newLogger.delta != nil && newLogger.deltaScope == DeltaPerContext {
+		newLogger.delta = newDeltaTracker(newLogger.clock())
 	}
 
-	// Add user ID if available
-	if userID, ok := GetUserID(ctx); ok {
-		newFields = append(newFields, Field{Key: "user_id", Value: userID})
+	if level, ok := GetMinLevel(ctx); ok {
+		newLogger.override = &level
+		newLogger.setContextField(Field{Key: "level_override", Value: strings.ToLower(level.String())})
 	}
 
-	// Add session ID if available
-	if sessionID, ok := GetSessionID(ctx); ok {
-		newFields = append(newFields, Field{Key: "session_id", Value: sessionID})
+	// Add the registered context values, including request, user and
+	// session IDs
+	for _, field := range contextFieldsFrom(ctx, newLogger.contextExtractors()) {
+		if newLogger.excluded[field.Key] {
+			continue
+		}
+		newLogger.setContextField(field)
 	}
 
-	// Create a new logger with all the fields
-	newLogger := &standardLogger{
-		logger:     l.logger,
-		level:      l.level,
-		timeFormat: l.timeFormat,
-		fields:     newFields,
+	return newLogger
+}
+
+// contextExtractors returns the extractors used by WithContext
+func (l *standardLogger) contextExtractors() []contextField {
+	if l.extractors != nil {
+		return l.extractors
 	}
+	return registeredContextFields()
+}
 
+// WithContextExtractors returns a logger using extractors instead of the
+// global context fields
+func (l *standardLogger) WithContextExtractors(extractors ...ContextExtractor) Logger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	newLogger := l.clone()
+	newLogger.extractors = make([]contextField, len(extractors))
+	for i, e := range extractors {
+		newLogger.extractors[i] = contextField{key: e.Key, name: e.Field, transform: e.Transform}
+	}
 	return newLogger
 }
+
+// ExcludeContextFields returns a logger that drops the named context
+// fields. The exclusion set is copied so derived loggers never share
+// later additions.
+func (l *standardLogger) ExcludeContextFields(names ...string) Logger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	newLogger := l.clone()
+	newLogger.excluded = make(map[string]bool, len(l.excluded)+len(names))
+	for name := range l.excluded {
+		newLogger.excluded[name] = true
+	}
+	for _, name := range names {
+		newLogger.excluded[name] = true
+	}
+	return newLogger
+}
+
+// setContextField replaces an existing base or context field with the same
+// key, or appends the field to the context group. With Config.ContextGroup
+// base fields live in another namespace and are left alone.
+func (l *standardLogger) setContextField(field Field) {
+	if l.ctxGroup == "" && replaceField(l.fields, field) {
+		return
+	}
+	l.ctxFields = setField(l.ctxFields, field)
+}
+
+// replaceField replaces the value of the first field with the same key and
+// reports whether one was found
+func replaceField(fields []Field, field Field) bool {
+	for i := range fields {
+		if fields[i].Key == field.Key {
+			fields[i].Value = field.Value
+			return true
+		}
+	}
+	return false
+}
+
+// setField replaces the value of the first field with the same key, or
+// appends the field if the key is not present
+func setField(fields []Field, field Field) []Field {
+	if replaceField(fields, field) {
+		return fields
+	}
+	return append(fields, field)
+}
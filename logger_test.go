@@ -202,3 +202,348 @@ func TestLoggerConcurrent(t *testing.T) {
 		}
 	}
 }
+
+type panickingStringer struct{}
+
+func (panickingStringer) String() string {
+	panic("boom")
+}
+
+// entryWriter records every Write call so tests can assert that each entry
+// reaches the output as a single line
+type entryWriter struct {
+	writes []string
+}
+
+func (w *entryWriter) Write(p []byte) (int, error) {
+	w.writes = append(w.writes, string(p))
+	return len(p), nil
+}
+
+func TestLoggerPanickingValue(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+
+	log.Info("first", logger.Field{Key: "bad", Value: panickingStringer{}})
+	// The logger must remain usable after a value panicked
+	log.Info("second")
+
+	output := buf.String()
+	if !strings.Contains(output, "bad=<panic>") {
+		t.Errorf("Expected panicking value to be replaced, got: %s", output)
+	}
+	if !strings.Contains(output, "_log_internal_error=") {
+		t.Errorf("Expected internal error field, got: %s", output)
+	}
+	if !strings.Contains(output, "second") {
+		t.Error("Expected logger to keep working after a panicking value")
+	}
+}
+
+func TestLoggerNilStringer(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+
+	var ptr *bytes.Buffer
+	log.Info("nil pointer", logger.Field{Key: "buf", Value: ptr})
+
+	if !strings.Contains(buf.String(), "buf=<nil>") {
+		t.Errorf("Expected nil pointer to render as <nil>, got: %s", buf.String())
+	}
+}
+
+func FuzzLogger(f *testing.F) {
+	f.Add("message", "key", "value")
+	f.Add("", "", "")
+	f.Add("invalid \xff\xfe utf-8", "k\x00ey", "v\xc3\x28")
+	f.Add("100% %s %d", "percent%v", "%!s(MISSING)")
+	f.Add(strings.Repeat("m", 1<<16), strings.Repeat("k", 1<<10), strings.Repeat("v", 1<<16))
+
+	f.Fuzz(func(t *testing.T, msg, key, value string) {
+		w := &entryWriter{}
+		log := logger.New(logger.Config{Level: logger.DebugLevel, Output: w})
+
+		log.With(logger.Field{Key: key, Value: []byte(value)}).Info(msg,
+			logger.Field{Key: key, Value: value},
+			logger.Field{Key: "stringer", Value: panickingStringer{}},
+		)
+
+		if len(w.writes) != 1 {
+			t.Fatalf("Expected exactly one write per entry, got %d", len(w.writes))
+		}
+		if !strings.HasSuffix(w.writes[0], "\n") {
+			t.Errorf("Expected entry to be newline terminated: %q", w.writes[0])
+		}
+	})
+}
+
+func TestLoggerWithContextChained(t *testing.T) {
+	ctx := logger.WithRequestID(context.Background(), "req-1")
+	other := logger.WithRequestID(context.Background(), "req-2")
+
+	tests := []struct {
+		name     string
+		derive   func(logger.Logger) logger.Logger
+		expected string
+	}{
+		{
+			name: "same context twice",
+			derive: func(l logger.Logger) logger.Logger {
+				return l.WithContext(ctx).WithContext(ctx)
+			},
+			expected: "request_id=req-1",
+		},
+		{
+			name: "different context replaces value",
+			derive: func(l logger.Logger) logger.Logger {
+				return l.WithContext(ctx).WithContext(other)
+			},
+			expected: "request_id=req-2",
+		},
+		{
+			name: "context replaces user supplied field",
+			derive: func(l logger.Logger) logger.Logger {
+				return l.With(logger.Field{Key: "request_id", Value: "manual"}).WithContext(ctx)
+			},
+			expected: "request_id=req-1",
+		},
+		{
+			name: "user supplied field after context is kept",
+			derive: func(l logger.Logger) logger.Logger {
+				return l.WithContext(ctx).With(logger.Field{Key: "user_id", Value: "u-1"}).WithContext(ctx)
+			},
+			expected: "{user_id=u-1 request_id=req-1}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := tt.derive(logger.New(logger.Config{Output: &buf}))
+			log.Info("test message")
+
+			output := buf.String()
+			if strings.Count(output, "request_id=") != 1 {
+				t.Errorf("Expected request_id exactly once, got: %s", output)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected log output to contain %q, got: %s", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestLoggerFieldOrdering(t *testing.T) {
+	ctx := logger.WithUserID(logger.WithRequestID(context.Background(), "req-1"), "user-1")
+
+	tests := []struct {
+		name     string
+		cfg      logger.Config
+		derive   func(logger.Logger) logger.Logger
+		fields   []logger.Field
+		expected string
+	}{
+		{
+			name:     "call-site fields only",
+			fields:   []logger.Field{{Key: "b", Value: 1}, {Key: "a", Value: 2}},
+			expected: "{b=1 a=2}",
+		},
+		{
+			name: "base fields in With order",
+			derive: func(l logger.Logger) logger.Logger {
+				return l.With(logger.Field{Key: "z", Value: 1}).With(logger.Field{Key: "y", Value: 2})
+			},
+			fields:   []logger.Field{{Key: "x", Value: 3}},
+			expected: "{z=1 y=2 x=3}",
+		},
+		{
+			name: "context fields after base fields",
+			derive: func(l logger.Logger) logger.Logger {
+				return l.WithContext(ctx).With(logger.Field{Key: "service", Value: "api"})
+			},
+			fields:   []logger.Field{{Key: "action", Value: "login"}},
+			expected: "{service=api request_id=req-1 user_id=user-1 action=login}",
+		},
+		{
+			name: "alphabetical ordering",
+			cfg:  logger.Config{SortFields: true},
+			derive: func(l logger.Logger) logger.Logger {
+				return l.WithContext(ctx).With(logger.Field{Key: "service", Value: "api"})
+			},
+			fields:   []logger.Field{{Key: "action", Value: "login"}},
+			expected: "{action=login request_id=req-1 service=api user_id=user-1}",
+		},
+		{
+			name:     "alphabetical ordering is stable for equal keys",
+			cfg:      logger.Config{SortFields: true},
+			fields:   []logger.Field{logger.Group("g", logger.Field{Key: "k", Value: 2}, logger.Field{Key: "a", Value: 0}, logger.Field{Key: "k", Value: 1})},
+			expected: "{g.a=0 g.k=2 g.k=1}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.cfg.Output = &buf
+			log := logger.New(tt.cfg)
+			if tt.derive != nil {
+				log = tt.derive(log)
+			}
+
+			log.Info("test message", tt.fields...)
+
+			if !strings.Contains(buf.String(), "[INFO] test message "+tt.expected) {
+				t.Errorf("Expected fields %s, got: %s", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestLoggerPushFields(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+	sibling := log.With(logger.Field{Key: "sibling", Value: true})
+
+	for _, id := range []string{"a", "b"} {
+		undo := logger.PushFields(log, logger.Field{Key: "item_id", Value: id})
+		log.Info("processing", logger.Field{Key: "step", Value: 1})
+		sibling.Info("sibling entry")
+		undo()
+		undo()
+	}
+	log.Info("after loop")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("Expected 5 entries, got %d: %s", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[0], "{item_id=a step=1}") || !strings.Contains(lines[2], "{item_id=b step=1}") {
+		t.Errorf("Expected pushed fields before call-site fields, got: %s", buf.String())
+	}
+	if strings.Contains(lines[1], "item_id") || strings.Contains(lines[3], "item_id") {
+		t.Errorf("Expected siblings to be unaffected by pushes, got: %s", buf.String())
+	}
+	if strings.Contains(lines[4], "item_id") {
+		t.Errorf("Expected undo to remove pushed fields, got: %s", lines[4])
+	}
+}
+
+func TestLoggerPushFieldsOutOfOrderUndo(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+
+	undoOuter := logger.PushFields(log, logger.Field{Key: "outer", Value: 1})
+	undoInner := logger.PushFields(log, logger.Field{Key: "inner", Value: 2})
+	undoOuter()
+	log.Info("inner only")
+	undoInner()
+
+	if !strings.Contains(buf.String(), "inner only {inner=2}") {
+		t.Errorf("Expected only the inner layer to remain, got: %s", buf.String())
+	}
+}
+
+func TestLoggerPushFieldsConcurrent(t *testing.T) {
+	log := logger.MultiLogger(logger.New(logger.Config{Output: &bytes.Buffer{}}))
+
+	done := make(chan bool)
+	for i := 0; i < 10; i++ {
+		go func(id int) {
+			for j := 0; j < 50; j++ {
+				undo := logger.PushFields(log, logger.Field{Key: "goroutine", Value: id})
+				log.Info("concurrent push")
+				undo()
+			}
+			done <- true
+		}(i)
+	}
+	for i := 0; i < 10; i++ {
+		<-done
+	}
+}
+
+func TestFieldOrderIsDeterministic(t *testing.T) {
+	profile := map[string]any{"zeta": 1, "alpha": []int{2, 1}, "mid": map[string]int{"y": 1, "b": 2}}
+
+	formats := []logger.Format{logger.FormatText, logger.FormatJSON, logger.FormatLogfmt}
+	for _, format := range formats {
+		for _, sorted := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/sorted=%v", format, sorted), func(t *testing.T) {
+				var buf bytes.Buffer
+				now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+				log := logger.New(logger.Config{Output: &buf, Format: format, SortFields: sorted, Clock: fixedClock(now)}).
+					With(logger.Field{Key: "service", Value: "api"}, logger.Field{Key: "b_base", Value: 1})
+
+				var first string
+				for i := 0; i < 100; i++ {
+					buf.Reset()
+					log.Info("profile",
+						logger.Field{Key: "profile", Value: profile},
+						logger.Group("req", logger.Field{Key: "path", Value: "/"}, logger.Field{Key: "method", Value: "GET"}),
+						logger.Field{Key: "a_call", Value: 2},
+					)
+					// Drop the standard library date of text output
+					line := buf.String()
+					line = line[strings.Index(line, "2024"):]
+					if i == 0 {
+						first = line
+					} else if line != first {
+						t.Fatalf("Expected identical output on every call, got:\n%s\n%s", first, line)
+					}
+				}
+
+				service, call := strings.Index(first, "service"), strings.Index(first, "a_call")
+				if !sorted && service > call {
+					t.Errorf("Expected base fields before call-site fields, got %s", first)
+				}
+				if sorted && (call > strings.Index(first, "b_base") || strings.Index(first, "method") > strings.Index(first, "path")) {
+					t.Errorf("Expected keys sorted, including inside groups, got %s", first)
+				}
+			})
+		}
+	}
+}
+
+func TestTextFieldQuoting(t *testing.T) {
+	tests := []struct {
+		name     string
+		field    logger.Field
+		expected string
+	}{
+		{name: "plain value", field: logger.Field{Key: "k", Value: "v"}, expected: "{k=v}"},
+		{name: "empty string", field: logger.Field{Key: "k", Value: ""}, expected: `{k=""}`},
+		{name: "spaces", field: logger.Field{Key: "k", Value: "hello world"}, expected: `{k="hello world"}`},
+		{name: "tab", field: logger.Field{Key: "k", Value: "a\tb"}, expected: `{k="a\tb"}`},
+		{name: "newline", field: logger.Field{Key: "k", Value: "a\nb"}, expected: `{k="a\nb"}`},
+		{name: "embedded quotes", field: logger.Field{Key: "k", Value: `say "hi"`}, expected: `{k="say \"hi\""}`},
+		{name: "equals sign", field: logger.Field{Key: "k", Value: "a=b"}, expected: `{k="a=b"}`},
+		{name: "embedded brace", field: logger.Field{Key: "k", Value: "x}"}, expected: `{k="x}"}`},
+		{name: "unicode", field: logger.Field{Key: "k", Value: "héllo→世界"}, expected: "{k=héllo→世界}"},
+		{name: "key with space", field: logger.Field{Key: "my key", Value: 1}, expected: `{"my key"=1}`},
+		{name: "key with equals", field: logger.Field{Key: "a=b", Value: 1}, expected: `{"a=b"=1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := logger.New(logger.Config{Output: &buf})
+
+			log.Info("test message", tt.field)
+
+			if !strings.Contains(buf.String(), "[INFO] test message "+tt.expected+"\n") {
+				t.Errorf("Expected fields %s, got: %s", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestMessageIsNotAFormatString(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+
+	log.Info("100% done: %s %d", logger.Field{Key: "k", Value: "v"})
+
+	if !strings.Contains(buf.String(), "[INFO] 100% done: %s %d {k=v}") || strings.Contains(buf.String(), "%!") {
+		t.Errorf("Expected the message to be written verbatim, got: %s", buf.String())
+	}
+}
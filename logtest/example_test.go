@@ -0,0 +1,47 @@
+package logtest_test
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+	"github.com/MichaelAJay/go-logger/logtest"
+)
+
+func ExampleNewSink() {
+	sink := logtest.NewSink()
+	log := logger.New(logger.Config{Output: sink, Format: logger.FormatJSON, Clock: func() time.Time { return time.Unix(0, 0).UTC() }})
+
+	go log.Info("from a worker", logger.Field{Key: "job", Value: 7})
+
+	if err := logtest.WaitForEntries(sink, 1, time.Second); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(sink.Entries()[0])
+	// Output:
+	// {"time":"1970-01-01T00:00:00Z","level":"INFO","msg":"from a worker","job":7}
+}
+
+func ExampleMemFS_Fail() {
+	fsys := logtest.NewMemFS()
+	log, err := logger.CreateFileLoggerFS(fsys, "/var/log/app.log", logger.InfoLevel)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	// Keep the logger's own failure report off the example's output
+	emergency := logger.Emergency().(logger.OutputSetter)
+	emergency.SetOutput(logtest.NewSink())
+	defer emergency.SetOutput(os.Stderr)
+
+	fsys.Fail(logtest.OpWrite, syscall.ENOSPC)
+	log.Info("lost")
+
+	health := log.(logger.HealthReporter).Health()
+	fmt.Println(health.Healthy, health.LastError)
+	// Output:
+	// false write /var/log/app.log: no space left on device
+}
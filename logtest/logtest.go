@@ -0,0 +1,93 @@
+// Package logtest helps tests assert on what a logger wrote.
+//
+// A Sink records every entry written to it and is used as Config.Output:
+//
+//	sink := logtest.NewSink()
+//	log := logger.New(logger.Config{Output: sink})
+//	go worker(log)
+//	if err := logtest.WaitForEntries(sink, 3, time.Second); err != nil {
+//		t.Fatal(err)
+//	}
+//
+// NewTB returns a logger writing to a test's log instead, so the output of
+// the code under test is shown only when the test fails:
+//
+//	svc := NewService(logtest.NewTB(t, logger.DebugLevel))
+package logtest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sink is an io.Writer that records each write as one entry, without its
+// trailing newline. Loggers created by New issue exactly one write per
+// entry. A Sink is safe for concurrent use.
+type Sink struct {
+	mu      sync.Mutex
+	entries []string
+	// changed is closed and replaced whenever an entry is recorded
+	changed chan struct{}
+}
+
+// NewSink returns an empty Sink
+func NewSink() *Sink {
+	return &Sink{changed: make(chan struct{})}
+}
+
+// Write records p as one entry
+func (s *Sink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = append(s.entries, strings.TrimSuffix(string(p), "\n"))
+	close(s.changed)
+	s.changed = make(chan struct{})
+	return len(p), nil
+}
+
+// Entries returns a copy of the recorded entries in write order
+func (s *Sink) Entries() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.entries...)
+}
+
+// Len returns the number of recorded entries
+func (s *Sink) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+// Reset discards the recorded entries
+func (s *Sink) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = nil
+}
+
+// WaitForEntries blocks until sink has recorded at least n entries or the
+// timeout expires, in which case the error reports how many arrived. Use
+// it instead of sleeping when entries are logged from other goroutines.
+func WaitForEntries(sink *Sink, n int, timeout time.Duration) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		sink.mu.Lock()
+		got, changed := len(sink.entries), sink.changed
+		sink.mu.Unlock()
+		if got >= n {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-timer.C:
+			return fmt.Errorf("logtest: got %d of %d entries after %v", sink.Len(), n, timeout)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package logtest_test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+	"github.com/MichaelAJay/go-logger/logtest"
+)
+
+func TestSinkRecordsEntries(t *testing.T) {
+	sink := logtest.NewSink()
+	log := logger.New(logger.Config{Output: sink})
+
+	log.Info("first", logger.Field{Key: "k", Value: "v"})
+	log.Warn("second")
+
+	entries := sink.Entries()
+	if len(entries) != 2 || !strings.HasSuffix(entries[0], "[INFO] first {k=v}") || !strings.Contains(entries[1], "[WARN] second") {
+		t.Fatalf("Unexpected entries: %q", entries)
+	}
+
+	sink.Reset()
+	if sink.Len() != 0 {
+		t.Errorf("Expected Reset to discard entries, got %d", sink.Len())
+	}
+}
+
+func TestWaitForEntries(t *testing.T) {
+	sink := logtest.NewSink()
+	log := logger.New(logger.Config{Output: sink})
+
+	go func() {
+		for i := 0; i < 3; i++ {
+			time.Sleep(5 * time.Millisecond)
+			log.Info(fmt.Sprintf("entry %d", i))
+		}
+	}()
+
+	if err := logtest.WaitForEntries(sink, 3, 5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	err := logtest.WaitForEntries(sink, 4, 20*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "got 3 of 4 entries") {
+		t.Errorf("Expected a timeout error naming the count, got %v", err)
+	}
+}
+
+func TestNoEntryMissingAfterFlush(t *testing.T) {
+	sink := logtest.NewSink()
+	log := logger.New(logger.Config{Output: sink})
+
+	const producers, perProducer = 200, 20
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			child := log.With(logger.Field{Key: "producer", Value: p})
+			for i := 0; i < perProducer; i++ {
+				child.Info("produced")
+			}
+		}(p)
+	}
+	wg.Wait()
+
+	if err := log.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if got := sink.Len(); got != producers*perProducer {
+		t.Errorf("Expected every entry logged before Flush to be recorded, got %d of %d", got, producers*perProducer)
+	}
+}
@@ -0,0 +1,352 @@
+package logtest
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+// Operations that MemFS.Fail can make fail
+const (
+	OpOpen    = "open"
+	OpWrite   = "write"
+	OpRename  = "rename"
+	OpRemove  = "remove"
+	OpStat    = "stat"
+	OpSymlink = "symlink"
+	OpMkdir   = "mkdir"
+)
+
+// MemFS is an in-memory logger.FileSystem for tests of file loggers.
+// Writes are visible to ReadFile as soon as they return. Like on POSIX
+// systems, an open file keeps receiving writes after it is renamed or
+// removed, which is what rotation relies on. A MemFS is safe for
+// concurrent use.
+//
+// Fail injects errors that are hard to provoke on a real disk:
+//
+//	fsys := logtest.NewMemFS()
+//	fsys.Fail(logtest.OpWrite, syscall.ENOSPC)
+type MemFS struct {
+	mu       sync.Mutex
+	files    map[string]*memData
+	dirs     map[string]bool
+	links    map[string]string
+	failures map[string]error
+	now      func() time.Time
+}
+
+// memData is the content of a file, shared by its name and open handles
+type memData struct {
+	content []byte
+	mode    os.FileMode
+	modTime time.Time
+}
+
+// NewMemFS returns an empty MemFS whose root and working directories
+// exist
+func NewMemFS() *MemFS {
+	return &MemFS{
+		files:    map[string]*memData{},
+		dirs:     map[string]bool{".": true, string(filepath.Separator): true},
+		links:    map[string]string{},
+		failures: map[string]error{},
+		now:      time.Now,
+	}
+}
+
+var _ logger.FileSystem = (*MemFS)(nil)
+
+// Fail makes every following op fail with err, for example
+// syscall.ENOSPC for OpWrite or syscall.EACCES for OpOpen, until Fail is
+// called again with a nil err. Errors are returned as *os.PathError, so
+// errors.Is(err, fs.ErrPermission) holds for EACCES.
+func (m *MemFS) Fail(op string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err == nil {
+		delete(m.failures, op)
+		return
+	}
+	m.failures[op] = err
+}
+
+// SetClock sets the time recorded as the modification time of files
+func (m *MemFS) SetClock(now func() time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = now
+}
+
+// failure returns the injected error for op on name; m.mu must be held
+func (m *MemFS) failure(op, name string) error {
+	if err, ok := m.failures[op]; ok {
+		return &os.PathError{Op: op, Path: name, Err: err}
+	}
+	return nil
+}
+
+// resolve follows symlinks from name; m.mu must be held
+func (m *MemFS) resolve(name string) string {
+	name = filepath.Clean(name)
+	for i := 0; i < 8; i++ {
+		target, ok := m.links[name]
+		if !ok {
+			break
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(name), target)
+		}
+		name = filepath.Clean(target)
+	}
+	return name
+}
+
+// OpenFile opens name, creating it with O_CREATE. Its directory must
+// exist.
+func (m *MemFS) OpenFile(name string, flag int, perm os.FileMode) (logger.File, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.failure(OpOpen, name); err != nil {
+		return nil, err
+	}
+	path := m.resolve(name)
+	if m.dirs[path] {
+		return nil, &os.PathError{Op: OpOpen, Path: name, Err: syscall.EISDIR}
+	}
+	data, exists := m.files[path]
+	switch {
+	case exists && flag&os.O_CREATE != 0 && flag&os.O_EXCL != 0:
+		return nil, &os.PathError{Op: OpOpen, Path: name, Err: fs.ErrExist}
+	case !exists && flag&os.O_CREATE == 0:
+		return nil, &os.PathError{Op: OpOpen, Path: name, Err: fs.ErrNotExist}
+	case !exists && !m.dirs[filepath.Dir(path)]:
+		return nil, &os.PathError{Op: OpOpen, Path: name, Err: fs.ErrNotExist}
+	case !exists:
+		data = &memData{mode: perm, modTime: m.now()}
+		m.files[path] = data
+	case flag&os.O_TRUNC != 0:
+		data.content = nil
+		data.modTime = m.now()
+	}
+	return &memFile{fs: m, name: name, data: data, append: flag&os.O_APPEND != 0}, nil
+}
+
+// Rename moves oldpath to newpath, replacing any file there
+func (m *MemFS) Rename(oldpath, newpath string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.failure(OpRename, oldpath); err != nil {
+		return err
+	}
+	oldpath, newpath = filepath.Clean(oldpath), filepath.Clean(newpath)
+	if !m.dirs[filepath.Dir(newpath)] {
+		return &os.LinkError{Op: OpRename, Old: oldpath, New: newpath, Err: fs.ErrNotExist}
+	}
+	if target, ok := m.links[oldpath]; ok {
+		delete(m.links, oldpath)
+		delete(m.files, newpath)
+		m.links[newpath] = target
+		return nil
+	}
+	data, ok := m.files[oldpath]
+	if !ok {
+		return &os.LinkError{Op: OpRename, Old: oldpath, New: newpath, Err: fs.ErrNotExist}
+	}
+	delete(m.files, oldpath)
+	delete(m.links, newpath)
+	m.files[newpath] = data
+	return nil
+}
+
+// Remove removes the file or symlink name, or an empty directory
+func (m *MemFS) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.failure(OpRemove, name); err != nil {
+		return err
+	}
+	path := filepath.Clean(name)
+	if _, ok := m.links[path]; ok {
+		delete(m.links, path)
+		return nil
+	}
+	if _, ok := m.files[path]; ok {
+		delete(m.files, path)
+		return nil
+	}
+	if m.dirs[path] {
+		for _, entry := range m.names() {
+			if filepath.Dir(entry) == path {
+				return &os.PathError{Op: OpRemove, Path: name, Err: syscall.ENOTEMPTY}
+			}
+		}
+		delete(m.dirs, path)
+		return nil
+	}
+	return &os.PathError{Op: OpRemove, Path: name, Err: fs.ErrNotExist}
+}
+
+// Stat describes name, following symlinks
+func (m *MemFS) Stat(name string) (os.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.failure(OpStat, name); err != nil {
+		return nil, err
+	}
+	path := m.resolve(name)
+	if m.dirs[path] {
+		return memInfo{name: filepath.Base(path), mode: fs.ModeDir | 0755}, nil
+	}
+	data, ok := m.files[path]
+	if !ok {
+		return nil, &os.PathError{Op: OpStat, Path: name, Err: fs.ErrNotExist}
+	}
+	return memInfo{name: filepath.Base(path), size: int64(len(data.content)), mode: data.mode, modTime: data.modTime}, nil
+}
+
+// Symlink makes newname a symbolic link to oldname
+func (m *MemFS) Symlink(oldname, newname string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.failure(OpSymlink, newname); err != nil {
+		return err
+	}
+	path := filepath.Clean(newname)
+	_, isFile := m.files[path]
+	_, isLink := m.links[path]
+	if isFile || isLink || m.dirs[path] {
+		return &os.LinkError{Op: OpSymlink, Old: oldname, New: newname, Err: fs.ErrExist}
+	}
+	if !m.dirs[filepath.Dir(path)] {
+		return &os.LinkError{Op: OpSymlink, Old: oldname, New: newname, Err: fs.ErrNotExist}
+	}
+	m.links[path] = oldname
+	return nil
+}
+
+// MkdirAll creates path and any missing parents
+func (m *MemFS) MkdirAll(path string, perm os.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.failure(OpMkdir, path); err != nil {
+		return err
+	}
+	for dir := filepath.Clean(path); !m.dirs[dir]; dir = filepath.Dir(dir) {
+		if _, ok := m.files[dir]; ok {
+			return &os.PathError{Op: OpMkdir, Path: dir, Err: syscall.ENOTDIR}
+		}
+		m.dirs[dir] = true
+	}
+	return nil
+}
+
+// ReadFile returns the content of name, following symlinks
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data, ok := m.files[m.resolve(name)]
+	if !ok {
+		return nil, &os.PathError{Op: OpOpen, Path: name, Err: fs.ErrNotExist}
+	}
+	return append([]byte(nil), data.content...), nil
+}
+
+// Files returns the names of all files and symlinks in sorted order
+func (m *MemFS) Files() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.names()
+}
+
+// names lists files and symlinks; m.mu must be held
+func (m *MemFS) names() []string {
+	names := make([]string, 0, len(m.files)+len(m.links))
+	for name := range m.files {
+		names = append(names, name)
+	}
+	for name := range m.links {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// memFile is an open MemFS file
+type memFile struct {
+	fs     *MemFS
+	name   string
+	data   *memData
+	append bool
+	offset int
+	closed bool
+}
+
+// Write writes p at the file's offset, or at its end with O_APPEND
+func (f *memFile) Write(p []byte) (int, error) {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+
+	if f.closed {
+		return 0, &os.PathError{Op: OpWrite, Path: f.name, Err: os.ErrClosed}
+	}
+	if err := f.fs.failure(OpWrite, f.name); err != nil {
+		return 0, err
+	}
+	if f.append {
+		f.offset = len(f.data.content)
+	}
+	if end := f.offset + len(p); end > len(f.data.content) {
+		f.data.content = append(f.data.content, make([]byte, end-len(f.data.content))...)
+	}
+	copy(f.data.content[f.offset:], p)
+	f.offset += len(p)
+	f.data.modTime = f.fs.now()
+	return len(p), nil
+}
+
+// Close closes the file; writes after Close fail with os.ErrClosed
+func (f *memFile) Close() error {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+
+	if f.closed {
+		return &os.PathError{Op: "close", Path: f.name, Err: os.ErrClosed}
+	}
+	f.closed = true
+	return nil
+}
+
+// Name returns the name the file was opened with
+func (f *memFile) Name() string {
+	return f.name
+}
+
+// memInfo describes a MemFS file or directory
+type memInfo struct {
+	name    string
+	size    int64
+	mode    os.FileMode
+	modTime time.Time
+}
+
+func (i memInfo) Name() string       { return i.name }
+func (i memInfo) Size() int64        { return i.size }
+func (i memInfo) Mode() os.FileMode  { return i.mode }
+func (i memInfo) ModTime() time.Time { return i.modTime }
+func (i memInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i memInfo) Sys() any           { return nil }
@@ -0,0 +1,158 @@
+package logtest_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"reflect"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+	"github.com/MichaelAJay/go-logger/logtest"
+)
+
+func TestMemFSReadYourWrites(t *testing.T) {
+	fsys := logtest.NewMemFS()
+	if err := fsys.MkdirAll("/var/log/app", 0755); err != nil {
+		t.Fatal(err)
+	}
+	file, err := fsys.OpenFile("/var/log/app/app.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file.Write([]byte("one\n"))
+	if content, _ := fsys.ReadFile("/var/log/app/app.log"); string(content) != "one\n" {
+		t.Errorf("Expected the write to be visible at once, got %q", content)
+	}
+
+	// An open file follows its data through a rename, as rotation expects
+	if err := fsys.Rename("/var/log/app/app.log", "/var/log/app/app.log.1"); err != nil {
+		t.Fatal(err)
+	}
+	file.Write([]byte("two\n"))
+	if content, _ := fsys.ReadFile("/var/log/app/app.log.1"); string(content) != "one\ntwo\n" {
+		t.Errorf("Expected the renamed file to keep receiving writes, got %q", content)
+	}
+	if _, err := fsys.Stat("/var/log/app/app.log"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected the old name to be gone, got %v", err)
+	}
+
+	if err := fsys.Symlink("app.log.1", "/var/log/app/current"); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := fsys.Stat("/var/log/app/current"); err != nil || info.Size() != 8 {
+		t.Errorf("Expected the symlink to resolve to an 8 byte file, got %v, %v", info, err)
+	}
+	expected := []string{"/var/log/app/app.log.1", "/var/log/app/current"}
+	if files := fsys.Files(); !reflect.DeepEqual(files, expected) {
+		t.Errorf("Expected files %v, got %v", expected, files)
+	}
+
+	file.Close()
+	if _, err := file.Write([]byte("late")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Expected a write after Close to fail with os.ErrClosed, got %v", err)
+	}
+}
+
+func TestMemFSRequiresDirectory(t *testing.T) {
+	fsys := logtest.NewMemFS()
+	if _, err := fsys.OpenFile("/missing/app.log", os.O_CREATE|os.O_WRONLY, 0644); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected opening a file in a missing directory to fail, got %v", err)
+	}
+	if err := fsys.Remove("/missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected removing a missing file to fail, got %v", err)
+	}
+}
+
+func TestFileLoggerOnMemFS(t *testing.T) {
+	fsys := logtest.NewMemFS()
+	log, err := logger.CreateFileLoggerFS(fsys, "/var/log/app/app.log", logger.InfoLevel)
+	if err != nil {
+		t.Fatalf("CreateFileLoggerFS failed: %v", err)
+	}
+
+	log.Info("written", logger.Field{Key: "k", Value: "v"})
+	content, err := fsys.ReadFile("/var/log/app/app.log")
+	if err != nil || !strings.Contains(string(content), "[INFO] written {k=v}") {
+		t.Errorf("Expected the entry in the file, got %q, %v", content, err)
+	}
+
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := log.Close(); err != nil {
+		t.Errorf("Expected a second Close to be a no-op, got %v", err)
+	}
+
+	// Reopening appends to the existing file
+	reopened, err := logger.NewFactory(logger.DefaultConfig).FileSystem(fsys).File("/var/log/app/app.log", logger.InfoLevel)
+	if err != nil {
+		t.Fatalf("Reopening failed: %v", err)
+	}
+	reopened.Info("again")
+	if content, _ := fsys.ReadFile("/var/log/app/app.log"); strings.Count(string(content), "\n") != 2 {
+		t.Errorf("Expected both entries after reopening, got %q", content)
+	}
+}
+
+func TestFileLoggerOpenDenied(t *testing.T) {
+	fsys := logtest.NewMemFS()
+	fsys.Fail(logtest.OpOpen, syscall.EACCES)
+
+	_, err := logger.CreateFileLoggerFS(fsys, "/var/log/app.log", logger.InfoLevel)
+	if !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("Expected a permission error, got %v", err)
+	}
+
+	fsys.Fail(logtest.OpOpen, nil)
+	if _, err := logger.CreateFileLoggerFS(fsys, "/var/log/app.log", logger.InfoLevel); err != nil {
+		t.Errorf("Expected opening to succeed once the failure is cleared, got %v", err)
+	}
+}
+
+func TestFileLoggerDiskFull(t *testing.T) {
+	fsys := logtest.NewMemFS()
+	log, err := logger.CreateFileLoggerFS(fsys, "app.log", logger.InfoLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := logger.Emergency().(logger.OutputSetter)
+	stderr.SetOutput(logtest.NewSink())
+	defer stderr.SetOutput(os.Stderr)
+
+	fsys.Fail(logtest.OpWrite, syscall.ENOSPC)
+	log.Info("lost")
+
+	health := log.(logger.HealthReporter).Health()
+	if health.Healthy || !errors.Is(health.Err, syscall.ENOSPC) {
+		t.Errorf("Expected the logger to report ENOSPC, got %+v", health)
+	}
+	if health.Name != "app.log" {
+		t.Errorf("Expected the output to be named after the file, got %q", health.Name)
+	}
+
+	fsys.Fail(logtest.OpWrite, nil)
+	log.Info("kept")
+	content, _ := fsys.ReadFile("app.log")
+	if strings.Contains(string(content), "lost") || !strings.Contains(string(content), "kept") {
+		t.Errorf("Expected only the entry written after recovery, got %q", content)
+	}
+	if !log.(logger.HealthReporter).Health().Healthy {
+		t.Error("Expected the logger to recover once space is freed")
+	}
+}
+
+func TestInitOnMemFS(t *testing.T) {
+	defer logger.SetDefaultLogger(logger.GetDefaultLogger())
+	fsys := logtest.NewMemFS()
+
+	log := logger.Init("svc", logger.WithFile("/var/log/svc.log"), logger.InitFileSystem(fsys), logger.InitLevel(logger.WarnLevel))
+	log.Warn("to file")
+
+	if content, _ := fsys.ReadFile("/var/log/svc.log"); !strings.Contains(string(content), "to file {service=svc") {
+		t.Errorf("Expected Init to write the file on the injected filesystem, got %q", content)
+	}
+}
@@ -0,0 +1,172 @@
+package logtest
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+// tbLayout renders entries for tb.Logf, which adds the time and call site
+const tbLayout = "{{.Level}} {{.Message}} {{.Fields}}"
+
+// tbLogger renders entries with an ordinary logger into a buffer and hands
+// each one to tb.Logf. Every method marks itself as a test helper, so the
+// reported file and line are those of the logging call.
+type tbLogger struct {
+	tb    testing.TB
+	inner logger.Logger
+	state *tbState
+}
+
+// tbState is shared by a TB logger and the loggers derived from it
+type tbState struct {
+	mu   sync.Mutex
+	buf  bytes.Buffer
+	done bool // set once the test has finished
+}
+
+// NewTB returns a logger writing entries at or above level to tb.Logf, so
+// they are shown with the output of a failing test, or with go test -v,
+// and hidden otherwise. Entries read "LEVEL message {key=value ...}".
+//
+// Fatal reports the entry with tb.Fatalf instead of exiting the process;
+// like tb.Fatalf, it must be called from the goroutine running the test.
+// Entries logged after the test finished are discarded. The logger and
+// loggers derived from it are safe for concurrent use, including from
+// parallel subtests each holding their own TB logger.
+func NewTB(tb testing.TB, level logger.Level) logger.Logger {
+	state := &tbState{}
+	inner := logger.New(logger.Config{
+		Level:                  level,
+		Output:                 &state.buf,
+		OutputName:             tb.Name(),
+		Layout:                 tbLayout,
+		Color:                  logger.ColorNever,
+		FatalConcurrencyPolicy: logger.FatalDrop,
+		ExitFunc:               func(int) {},
+	})
+	tb.Cleanup(func() {
+		state.mu.Lock()
+		defer state.mu.Unlock()
+		state.done = true
+	})
+	return &tbLogger{tb: tb, inner: inner, state: state}
+}
+
+// ReplaceDefault makes a debug level TB logger the package default logger
+// until the test finishes, and returns it. Tests calling it must not run
+// in parallel, since the default logger is shared by the whole package.
+// Entries logged through the package level functions report those
+// functions as their call site.
+func ReplaceDefault(tb testing.TB) logger.Logger {
+	l := NewTB(tb, logger.DebugLevel)
+	previous := logger.SwapDefaultLogger(l)
+	tb.Cleanup(func() { logger.SetDefaultLogger(previous) })
+	return l
+}
+
+// render runs log against the inner logger and returns what it wrote, or
+// "" if the entry was filtered or the test has finished
+func (l *tbLogger) render(log func()) string {
+	l.state.mu.Lock()
+	defer l.state.mu.Unlock()
+
+	l.state.buf.Reset()
+	log()
+	if l.state.done {
+		return ""
+	}
+	return strings.TrimSuffix(l.state.buf.String(), "\n")
+}
+
+// emit reports the entry written by log with tb.Logf
+func (l *tbLogger) emit(log func()) {
+	l.tb.Helper()
+	if line := l.render(log); line != "" {
+		l.tb.Logf("%s", line)
+	}
+}
+
+func (l *tbLogger) Debug(msg string, fields ...logger.Field) {
+	l.tb.Helper()
+	l.emit(func() { l.inner.Debug(msg, fields...) })
+}
+
+func (l *tbLogger) Info(msg string, fields ...logger.Field) {
+	l.tb.Helper()
+	l.emit(func() { l.inner.Info(msg, fields...) })
+}
+
+func (l *tbLogger) Warn(msg string, fields ...logger.Field) {
+	l.tb.Helper()
+	l.emit(func() { l.inner.Warn(msg, fields...) })
+}
+
+func (l *tbLogger) Error(msg string, fields ...logger.Field) {
+	l.tb.Helper()
+	l.emit(func() { l.inner.Error(msg, fields...) })
+}
+
+// Fatal fails the test with the entry and stops it with tb.Fatalf
+func (l *tbLogger) Fatal(msg string, fields ...logger.Field) {
+	l.tb.Helper()
+	line := l.render(func() { l.inner.Fatal(msg, fields...) })
+	if line == "" {
+		line = "FATAL " + msg
+	}
+	l.tb.Fatalf("%s", line)
+}
+
+func (l *tbLogger) WarnCode(code, msg string, fields ...logger.Field) {
+	l.tb.Helper()
+	fields = append([]logger.Field{logger.Code(code)}, fields...)
+	l.emit(func() { l.inner.Warn(msg, fields...) })
+}
+
+func (l *tbLogger) ErrorCode(code, msg string, fields ...logger.Field) {
+	l.tb.Helper()
+	fields = append([]logger.Field{logger.Code(code)}, fields...)
+	l.emit(func() { l.inner.Error(msg, fields...) })
+}
+
+// derive wraps a logger derived from the inner one
+func (l *tbLogger) derive(inner logger.Logger) logger.Logger {
+	return &tbLogger{tb: l.tb, inner: inner, state: l.state}
+}
+
+func (l *tbLogger) With(fields ...logger.Field) logger.Logger {
+	return l.derive(l.inner.With(fields...))
+}
+
+func (l *tbLogger) WithLazy(fields ...logger.Field) logger.Logger {
+	return l.derive(logger.WithLazy(l.inner, fields...))
+}
+
+func (l *tbLogger) WithContext(ctx context.Context) logger.Logger {
+	return l.derive(l.inner.WithContext(ctx))
+}
+
+func (l *tbLogger) WithContextExtractors(extractors ...logger.ContextExtractor) logger.Logger {
+	return l.derive(logger.WithContextExtractors(l.inner, extractors...))
+}
+
+func (l *tbLogger) ExcludeContextFields(names ...string) logger.Logger {
+	return l.derive(logger.ExcludeContextFields(l.inner, names...))
+}
+
+func (l *tbLogger) Named(name string) logger.Logger {
+	return l.derive(logger.Named(l.inner, name))
+}
+
+func (l *tbLogger) PushFields(fields ...logger.Field) (undo func()) {
+	return logger.PushFields(l.inner, fields...)
+}
+
+func (l *tbLogger) Enabled(level logger.Level) bool { return l.inner.Enabled(level) }
+func (l *tbLogger) SetLevel(level logger.Level)     { l.inner.SetLevel(level) }
+func (l *tbLogger) Flush() error                    { return nil }
+func (l *tbLogger) Close() error                    { return nil }
@@ -0,0 +1,137 @@
+package logtest_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+	"github.com/MichaelAJay/go-logger/logtest"
+)
+
+// recordingTB captures what a TB logger reports
+type recordingTB struct {
+	testing.TB
+	mu       sync.Mutex
+	logs     []string
+	fatal    string
+	cleanups []func()
+}
+
+func (r *recordingTB) Helper()      {}
+func (r *recordingTB) Name() string { return "recording" }
+
+func (r *recordingTB) Logf(format string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logs = append(r.logs, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Fatalf(format string, args ...any) {
+	r.fatal = fmt.Sprintf(format, args...)
+}
+
+func (r *recordingTB) Cleanup(f func()) {
+	r.cleanups = append(r.cleanups, f)
+}
+
+func (r *recordingTB) finish() {
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.cleanups[i]()
+	}
+}
+
+func TestNewTB(t *testing.T) {
+	tb := &recordingTB{}
+	log := logtest.NewTB(tb, logger.InfoLevel)
+
+	log.Debug("filtered")
+	log.With(logger.Field{Key: "user", Value: "u1"}).Info("signed in", logger.Field{Key: "attempt", Value: 2})
+	log.Warn("no fields")
+
+	expected := []string{"INFO signed in {user=u1 attempt=2}", "WARN no fields"}
+	if fmt.Sprint(tb.logs) != fmt.Sprint(expected) {
+		t.Errorf("Expected %q, got %q", expected, tb.logs)
+	}
+}
+
+func TestNewTBFatal(t *testing.T) {
+	tb := &recordingTB{}
+	log := logtest.NewTB(tb, logger.InfoLevel)
+
+	log.Fatal("cannot continue", logger.Field{Key: "code", Value: 7})
+
+	if tb.fatal != "FATAL cannot continue {code=7}" {
+		t.Errorf("Expected Fatal to use tb.Fatalf, got %q", tb.fatal)
+	}
+	if len(tb.logs) != 0 {
+		t.Errorf("Expected nothing else logged, got %q", tb.logs)
+	}
+}
+
+func TestNewTBAfterTestFinished(t *testing.T) {
+	tb := &recordingTB{}
+	log := logtest.NewTB(tb, logger.InfoLevel)
+
+	tb.finish()
+	log.Info("too late")
+
+	if len(tb.logs) != 0 {
+		t.Errorf("Expected entries after the test to be discarded, got %q", tb.logs)
+	}
+}
+
+func TestNewTBConcurrent(t *testing.T) {
+	tb := &recordingTB{}
+	log := logtest.NewTB(tb, logger.InfoLevel)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			child := log.With(logger.Field{Key: "worker", Value: i})
+			for j := 0; j < 20; j++ {
+				child.Info("tick")
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if len(tb.logs) != 160 {
+		t.Fatalf("Expected 160 entries, got %d", len(tb.logs))
+	}
+	for _, line := range tb.logs {
+		var worker int
+		if _, err := fmt.Sscanf(line, "INFO tick {worker=%d}", &worker); err != nil {
+			t.Fatalf("Expected whole entries, got %q", line)
+		}
+	}
+}
+
+func TestNewTBParallelSubtests(t *testing.T) {
+	for i := 0; i < 4; i++ {
+		i := i
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			t.Parallel()
+			log := logtest.NewTB(t, logger.DebugLevel)
+			log.Debug("subtest", logger.Field{Key: "i", Value: i})
+		})
+	}
+}
+
+func TestReplaceDefault(t *testing.T) {
+	previous := logger.GetDefaultLogger()
+
+	t.Run("replaced", func(t *testing.T) {
+		log := logtest.ReplaceDefault(t)
+		if logger.GetDefaultLogger() != log {
+			t.Error("Expected the TB logger to be the default logger")
+		}
+		logger.Info("through the default logger")
+	})
+
+	if logger.GetDefaultLogger() != previous {
+		t.Error("Expected the default logger to be restored")
+	}
+}
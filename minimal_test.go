@@ -0,0 +1,92 @@
+package logger_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// minimalExample is a CLI that only uses core logging
+const minimalExample = `package main
+
+import "github.com/MichaelAJay/go-logger"
+
+func main() {
+	log := logger.New(logger.Config{})
+	log.Info("started", logger.Field{Key: "args", Value: 0})
+}
+`
+
+// optionalPackages must not be linked into binaries built with the
+// logger_minimal tag
+var optionalPackages = []string{"net", "net/http", "crypto/tls"}
+
+func TestMinimalBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds binaries")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not available")
+	}
+	repo, err := filepath.Abs(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	goMod := "module example.com/cli\n\ngo 1.18\n\nrequire github.com/MichaelAJay/go-logger v0.0.0\n\nreplace github.com/MichaelAJay/go-logger => " + repo + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(minimalExample), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command(goBin, args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("go %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+		return string(out)
+	}
+	linked := func(deps string) map[string]bool {
+		set := map[string]bool{}
+		for _, pkg := range strings.Fields(deps) {
+			set[pkg] = true
+		}
+		return set
+	}
+
+	full := linked(run("list", "-deps", "."))
+	minimal := linked(run("list", "-deps", "-tags", "logger_minimal", "."))
+	if !full["net/http"] {
+		t.Fatal("Expected the default build to link net/http; the check proves nothing otherwise")
+	}
+	for _, pkg := range optionalPackages {
+		if minimal[pkg] {
+			t.Errorf("Expected the minimal build not to depend on %s", pkg)
+		}
+	}
+
+	run("build", "-o", "full", ".")
+	run("build", "-tags", "logger_minimal", "-o", "minimal", ".")
+	fullInfo, err := os.Stat(filepath.Join(dir, "full"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	minimalInfo, err := os.Stat(filepath.Join(dir, "minimal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if minimalInfo.Size() >= fullInfo.Size() {
+		t.Errorf("Expected the minimal binary to be smaller, got %d bytes against %d", minimalInfo.Size(), fullInfo.Size())
+	}
+	t.Logf("binary size: %d bytes, %d with logger_minimal", fullInfo.Size(), minimalInfo.Size())
+}
@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+)
+
+// MultilinePolicy controls how line breaks in messages and field values
+// are written by FormatText, layouts and FormatSyslog. JSON, logfmt, GELF
+// and ECS always escape them, as their syntax requires, and FormatConsole
+// indents them for reading in a terminal.
+type MultilinePolicy int
+
+const (
+	// MultilineEscape writes line breaks as \n and \r, keeping every entry
+	// on one line. It is the default.
+	MultilineEscape MultilinePolicy = iota
+	// MultilineIndent keeps line breaks and starts every continuation line
+	// with MultilineIndentMarker, so collectors can join lines that begin
+	// with whitespace back into their entry
+	MultilineIndent
+	// MultilinePassthrough writes line breaks untouched
+	MultilinePassthrough
+)
+
+// MultilineIndentMarker starts continuation lines under MultilineIndent
+const MultilineIndentMarker = "    | "
+
+// String returns the name of the policy
+func (p MultilinePolicy) String() string {
+	switch p {
+	case MultilineEscape:
+		return "escape"
+	case MultilineIndent:
+		return "indent"
+	case MultilinePassthrough:
+		return "passthrough"
+	default:
+		return "unknown"
+	}
+}
+
+// multilineEscaper replaces line breaks under MultilineEscape
+var multilineEscaper = strings.NewReplacer("\r\n", `\r\n`, "\n", `\n`, "\r", `\r`)
+
+// multilineMessage applies the policy to a message
+func (l *standardLogger) multilineMessage(msg string) string {
+	if !strings.ContainsAny(msg, "\r\n") {
+		return msg
+	}
+	switch l.multiline {
+	case MultilineIndent:
+		return strings.ReplaceAll(strings.ReplaceAll(msg, "\r\n", "\n"), "\n", "\n"+MultilineIndentMarker)
+	case MultilinePassthrough:
+		return msg
+	default:
+		return multilineEscaper.Replace(msg)
+	}
+}
+
+// writeTextValue writes a text field value. Under MultilineEscape, line
+// breaks are escaped inside the quoted value like any other control
+// character. Otherwise they are kept between the quoted lines, followed
+// by the indent marker under MultilineIndent.
+func (l *standardLogger) writeTextValue(b *strings.Builder, s string) {
+	if l.multiline == MultilineEscape || !strings.Contains(s, "\n") {
+		writeTextToken(b, s)
+		return
+	}
+	sep := "\n"
+	if l.multiline == MultilineIndent {
+		sep += MultilineIndentMarker
+	}
+	b.WriteByte('"')
+	for i, line := range strings.Split(s, "\n") {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		var quoted bytes.Buffer
+		writeJSONString(&quoted, line)
+		b.Write(quoted.Bytes()[1 : quoted.Len()-1])
+	}
+	b.WriteByte('"')
+}
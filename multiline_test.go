@@ -0,0 +1,72 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestMultilinePolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   logger.MultilinePolicy
+		format   logger.Format
+		expected string
+	}{
+		{
+			name:     "escape by default",
+			expected: `[INFO] query failed:\nSELECT 1 {sql="SELECT *\nFROM t"}` + "\n",
+		},
+		{
+			name:     "indent",
+			policy:   logger.MultilineIndent,
+			expected: "[INFO] query failed:\n    | SELECT 1 {sql=\"SELECT *\n    | FROM t\"}\n",
+		},
+		{
+			name:     "passthrough",
+			policy:   logger.MultilinePassthrough,
+			expected: "[INFO] query failed:\nSELECT 1 {sql=\"SELECT *\nFROM t\"}\n",
+		},
+		{
+			name:     "syslog escapes the message",
+			format:   logger.FormatSyslog,
+			expected: `query failed:\nSELECT 1` + "\n",
+		},
+		{
+			name:     "json ignores the policy",
+			policy:   logger.MultilinePassthrough,
+			format:   logger.FormatJSON,
+			expected: `"msg":"query failed:\nSELECT 1","sql":"SELECT *\nFROM t"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := logger.New(logger.Config{Output: &buf, Format: tt.format, Multiline: tt.policy})
+
+			log.Info("query failed:\nSELECT 1", logger.Field{Key: "sql", Value: "SELECT *\nFROM t"})
+
+			if !strings.Contains(buf.String(), tt.expected) {
+				t.Errorf("Expected output to contain %q, got: %q", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestMultilineEscapeKeepsOneLinePerEntry(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+
+	log.Error("panic: boom\r\n\ngoroutine 1 [running]:\n\tmain.main()", logger.Field{Key: "stack", Value: "a\nb"})
+	log.Info("next")
+
+	if n := strings.Count(buf.String(), "\n"); n != 2 {
+		t.Errorf("Expected two lines, got %d: %s", n, buf.String())
+	}
+	if !strings.Contains(buf.String(), `panic: boom\r\n\ngoroutine 1 [running]:\n`) {
+		t.Errorf("Expected escaped line breaks, got: %s", buf.String())
+	}
+}
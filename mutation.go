@@ -0,0 +1,244 @@
+package logger
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"reflect"
+	"sync/atomic"
+	"time"
+)
+
+// capturedValue is a pointer, slice or map field value held by a logger
+// under Config.DetectMutations, with its fingerprint when it was captured
+type capturedValue struct {
+	key    string
+	value  any
+	sum    uint64
+	site   string
+	warned int32 // set once the change has been reported
+}
+
+// captureValues fingerprints the pointer, slice and map values in fields,
+// including those inside groups, and returns them appended to captured
+func (l *standardLogger) captureValues(captured []*capturedValue, prefix string, fields []Field, site string) []*capturedValue {
+	for _, field := range fields {
+		if group, ok := field.Value.(groupValue); ok {
+			captured = l.captureValues(captured, prefix+field.Key+".", group, site)
+			continue
+		}
+		if sum, ok := fingerprint(field.Value); ok {
+			captured = append(captured, &capturedValue{key: prefix + field.Key, value: field.Value, sum: sum, site: site})
+		}
+	}
+	return captured
+}
+
+// checkCaptured writes a warning for each captured value that changed
+// since it was captured, once per value. l.mu must be held.
+func (l *standardLogger) checkCaptured(now time.Time) {
+	check := func(captured []*capturedValue) {
+		for _, c := range captured {
+			if atomic.LoadInt32(&c.warned) != 0 {
+				continue
+			}
+			if sum, _ := fingerprint(c.value); sum == c.sum || !atomic.CompareAndSwapInt32(&c.warned, 0, 1) {
+				continue
+			}
+			l.writeEntry(WarnLevel, "logged field changed after it was captured", []Field{
+				{Key: "field", Value: c.key},
+				{Key: "captured_at", Value: c.site},
+				{Key: "logged_at", Value: l.caller()},
+				{Key: "hint", Value: "pass a copy, or use logger.Snapshot"},
+			}, now)
+		}
+	}
+	check(l.captured)
+	for _, layer := range l.pushed {
+		check(layer.captured)
+	}
+}
+
+// fingerprint returns a shallow hash of what a pointer, slice or map
+// value refers to: the values it holds directly, and the addresses and
+// lengths of anything they point to in turn. Other values cannot change
+// behind the logger's back and report false.
+func fingerprint(value any) (uint64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map:
+		if v.IsNil() {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+	h := fnv.New64a()
+	var buf [8]byte
+	write := func(n uint64) {
+		binary.LittleEndian.PutUint64(buf[:], n)
+		h.Write(buf[:])
+	}
+
+	var hashValue func(v reflect.Value, deref bool)
+	hashValue = func(v reflect.Value, deref bool) {
+		switch v.Kind() {
+		case reflect.Bool:
+			if v.Bool() {
+				write(1)
+			} else {
+				write(0)
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			write(uint64(v.Int()))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			write(v.Uint())
+		case reflect.Float32, reflect.Float64:
+			write(math.Float64bits(v.Float()))
+		case reflect.Complex64, reflect.Complex128:
+			write(math.Float64bits(real(v.Complex())))
+			write(math.Float64bits(imag(v.Complex())))
+		case reflect.String:
+			write(uint64(v.Len()))
+			h.Write([]byte(v.String()))
+		case reflect.Array:
+			for i := 0; i < v.Len(); i++ {
+				hashValue(v.Index(i), false)
+			}
+		case reflect.Struct:
+			for i := 0; i < v.NumField(); i++ {
+				hashValue(v.Field(i), false)
+			}
+		case reflect.Interface:
+			if !v.IsNil() {
+				hashValue(v.Elem(), false)
+			}
+		case reflect.Ptr:
+			write(uint64(v.Pointer()))
+			if deref && !v.IsNil() {
+				hashValue(v.Elem(), false)
+			}
+		case reflect.Slice:
+			write(uint64(v.Pointer()))
+			write(uint64(v.Len()))
+			if deref {
+				for i := 0; i < v.Len(); i++ {
+					hashValue(v.Index(i), false)
+				}
+			}
+		case reflect.Map:
+			write(uint64(v.Pointer()))
+			write(uint64(v.Len()))
+			if deref {
+				// Combine entries so map iteration order does not matter
+				var sum uint64
+				iter := v.MapRange()
+				for iter.Next() {
+					entry := fnv.New64a()
+					saved := h
+					h = entry
+					hashValue(iter.Key(), false)
+					hashValue(iter.Value(), false)
+					h = saved
+					sum += entry.Sum64()
+				}
+				write(sum)
+			}
+		default:
+			// Channels, functions and unsafe pointers by identity
+			write(uint64(v.Pointer()))
+		}
+	}
+	hashValue(v, true)
+	return h.Sum64(), true
+}
+
+// snapshotDepth bounds how deep Snapshot copies; deeper values are shared
+const snapshotDepth = 8
+
+// snapshotElements bounds how many slice, array and map elements Snapshot
+// copies in total; further elements are shared
+const snapshotElements = 10000
+
+// Snapshot returns a field holding a deep copy of v taken now, for
+// pointers, slices and maps that may change before the entry is written,
+// such as values passed to With. Copying stops at snapshotDepth levels
+// and snapshotElements elements, below which values are shared.
+// Unexported fields are copied as they are, so what they point to is
+// shared too.
+func Snapshot(key string, v any) Field {
+	if v == nil {
+		return Field{Key: key, Value: v}
+	}
+	budget := snapshotElements
+	return Field{Key: key, Value: deepCopy(reflect.ValueOf(v), snapshotDepth, &budget).Interface()}
+}
+
+// deepCopy returns a copy of v, sharing what lies below depth levels or
+// past the element budget
+func deepCopy(v reflect.Value, depth int, budget *int) reflect.Value {
+	if depth == 0 {
+		return v
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type().Elem())
+		copied.Elem().Set(deepCopy(v.Elem(), depth-1, budget))
+		return copied
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(deepCopy(v.Elem(), depth, budget))
+		return copied
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(copied, v)
+		for i := 0; i < v.Len() && *budget > 0; i++ {
+			*budget--
+			copied.Index(i).Set(deepCopy(v.Index(i), depth-1, budget))
+		}
+		return copied
+	case reflect.Array:
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		for i := 0; i < v.Len() && *budget > 0; i++ {
+			*budget--
+			copied.Index(i).Set(deepCopy(v.Index(i), depth-1, budget))
+		}
+		return copied
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			value := iter.Value()
+			if *budget > 0 {
+				*budget--
+				value = deepCopy(value, depth-1, budget)
+			}
+			copied.SetMapIndex(iter.Key(), value)
+		}
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if field := copied.Field(i); field.CanSet() {
+				field.Set(deepCopy(v.Field(i), depth-1, budget))
+			}
+		}
+		return copied
+	default:
+		return v
+	}
+}
@@ -0,0 +1,118 @@
+package logger_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+type request struct {
+	Method string
+	Path   string
+	Tags   []string
+	Meta   map[string]int
+	Parent *request
+}
+
+func TestDetectMutationsReportsChangedStruct(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, DetectMutations: true})
+
+	req := &request{Method: "GET", Path: "/a"}
+	reqLog := log.With(logger.Field{Key: "req", Value: req})
+	reqLog.Info("first")
+	if strings.Contains(buf.String(), "changed") {
+		t.Fatalf("Expected no warning before the value changes, got: %s", buf.String())
+	}
+
+	req.Path = "/b"
+	reqLog.Info("second")
+	reqLog.Info("third")
+
+	output := buf.String()
+	if strings.Count(output, "logged field changed after it was captured") != 1 {
+		t.Fatalf("Expected exactly one warning, got: %s", output)
+	}
+	if !strings.Contains(output, "field=req captured_at=") || !strings.Contains(output, "mutation_test.go:") {
+		t.Errorf("Expected the warning to name the field and the call sites, got: %s", output)
+	}
+}
+
+func TestDetectMutationsCoversSlicesMapsAndPushedFields(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, DetectMutations: true})
+
+	ids := []int{1, 2}
+	counts := map[string]int{"a": 1}
+	derived := log.With(logger.Group("batch", logger.Field{Key: "ids", Value: ids}))
+	undo := logger.PushFields(derived, logger.Field{Key: "counts", Value: counts})
+	defer undo()
+
+	ids[0] = 9
+	counts["a"] = 2
+	derived.Info("processed")
+
+	if !strings.Contains(buf.String(), "field=batch.ids") || !strings.Contains(buf.String(), "field=counts") {
+		t.Errorf("Expected warnings for the slice and the map, got: %s", buf.String())
+	}
+}
+
+func TestDetectMutationsIsOptIn(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+
+	req := &request{Path: "/a"}
+	reqLog := log.With(logger.Field{Key: "req", Value: req})
+	req.Path = "/b"
+	reqLog.Info("entry")
+
+	if strings.Contains(buf.String(), "changed") {
+		t.Errorf("Expected no detection unless enabled, got: %s", buf.String())
+	}
+}
+
+func TestSnapshotCopiesDeeply(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatJSON, DetectMutations: true})
+
+	req := &request{Method: "GET", Path: "/a", Tags: []string{"x"}, Meta: map[string]int{"n": 1}, Parent: &request{Path: "/root"}}
+	snapshot := logger.Snapshot("req", req)
+	reqLog := log.With(snapshot)
+
+	req.Path = "/b"
+	req.Tags[0] = "y"
+	req.Meta["n"] = 2
+	req.Parent.Path = "/changed"
+	reqLog.Info("handled")
+
+	if strings.Contains(buf.String(), "changed after") {
+		t.Fatalf("Expected a snapshot not to change, got: %s", buf.String())
+	}
+	got := decodeEntry(t, buf.String())["req"]
+	expected := map[string]any{
+		"Method": "GET", "Path": "/a", "Tags": []any{"x"}, "Meta": map[string]any{"n": float64(1)},
+		"Parent": map[string]any{"Method": "", "Path": "/root", "Tags": nil, "Meta": nil, "Parent": nil},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected the state at the time of Snapshot %v, got %v", expected, got)
+	}
+}
+
+func TestSnapshotIsBounded(t *testing.T) {
+	// A cycle is copied up to the depth limit and shared below it
+	cycle := &request{Path: "/loop"}
+	cycle.Parent = cycle
+
+	field := logger.Snapshot("req", cycle)
+	copied := field.Value.(*request)
+	if copied == cycle || copied.Path != "/loop" {
+		t.Errorf("Expected a copy of the value, got %p for %p", copied, cycle)
+	}
+
+	if logger.Snapshot("nil", nil).Value != nil {
+		t.Error("Expected a nil value to stay nil")
+	}
+}
@@ -0,0 +1,199 @@
+package logger
+
+import (
+	"io"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OutputSetter is implemented by loggers whose output can be replaced at
+// runtime. Loggers created by New and MultiLogger implement it.
+type OutputSetter interface {
+	// SetOutput makes the logger and every logger derived from it write
+	// to w. Entries are never split between the old and the new writer.
+	// If the logger opened the old writer itself, as CreateFileLogger
+	// does, the old writer is closed and the close error returned.
+	SetOutput(w io.Writer) error
+}
+
+// outputReporter is implemented by loggers that can tell which writer
+// they currently write to, so a MultiLogger can check that all of its
+// children share one before replacing it
+type outputReporter interface {
+	currentOutput() (io.Writer, bool)
+}
+
+// outputState is shared by a logger and everything derived from it
+type outputState struct {
+	mu     sync.Mutex
+	owned  io.Closer // the current writer if the logger opened it
+	name   string
+	health SinkHealth
+
+	// floor is the level of a temporary floor plus one, or zero when there
+	// is none; floorUntil is its expiry in Unix nanoseconds
+	floor      int32
+	floorUntil int64
+
+	// colored is non-zero while the level token is colored, as decided by
+	// colorMode for the current writer
+	colorMode ColorMode
+	colored   int32
+
+	// quiet suppresses failure reports to the emergency logger, for the
+	// emergency logger's own output
+	quiet bool
+
+	// ignoreSIGPIPE is Config.IgnoreSIGPIPE, applied again by SetOutput
+	ignoreSIGPIPE bool
+
+	// brokenRun counts consecutive broken pipe errors. Once it reaches
+	// brokenLimit, broken is set and entries go to fallback, if any,
+	// instead of the output.
+	brokenLimit int
+	brokenRun   int
+	broken      int32
+	fallback    *log.Logger
+}
+
+// SinkHealth reports the write health of one output
+type SinkHealth struct {
+	Name                string `json:"name"`
+	Healthy             bool   `json:"healthy"`
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	LastError           string `json:"last_error,omitempty"`
+
+	// Err is the last failure as a *WriteError, cleared by a successful
+	// write
+	Err         error     `json:"-"`
+	LastFailure time.Time `json:"last_failure,omitempty"`
+	LastSuccess time.Time `json:"last_success,omitempty"`
+
+	// BrokenPipe is set when the last error meant the reader of the output
+	// is gone, such as EPIPE or a closed file
+	BrokenPipe bool `json:"broken_pipe,omitempty"`
+
+	// Stopped is set once writes to the output were abandoned after
+	// Config.BrokenPipeLimit consecutive broken pipe errors
+	Stopped bool `json:"stopped,omitempty"`
+}
+
+// HealthReporter is implemented by loggers that track write failures of
+// their output
+type HealthReporter interface {
+	Health() SinkHealth
+}
+
+// ChildrenHealthReporter is implemented by loggers fanning out to several
+// outputs, such as MultiLogger
+type ChildrenHealthReporter interface {
+	ChildrenHealth() []SinkHealth
+}
+
+// record updates the health after a write attempt. It reports whether
+// err is the first failure after a successful write, or of the output,
+// and whether it stopped writes to the output.
+func (o *outputState) record(err *WriteError, now time.Time) (firstFailure, stopped bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if err != nil {
+		o.health.ConsecutiveFailures++
+		o.health.LastError = err.Cause.Error()
+		o.health.Err = err
+		o.health.LastFailure = now
+		o.health.BrokenPipe = isBrokenPipe(err)
+		if o.health.BrokenPipe {
+			o.brokenRun++
+		} else {
+			o.brokenRun = 0
+		}
+		if o.brokenLimit > 0 && o.brokenRun == o.brokenLimit {
+			atomic.StoreInt32(&o.broken, 1)
+			stopped = !o.quiet
+		}
+		return o.health.ConsecutiveFailures == 1 && !o.quiet, stopped
+	}
+	o.health.ConsecutiveFailures = 0
+	o.health.LastSuccess = now
+	o.health.Err = nil
+	o.health.BrokenPipe = false
+	o.brokenRun = 0
+	return false, false
+}
+
+// Health returns the write health of the logger's output
+func (l *standardLogger) Health() SinkHealth {
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+
+	health := l.out.health
+	health.Name = l.outputName()
+	health.Stopped = atomic.LoadInt32(&l.out.broken) != 0
+	health.Healthy = health.ConsecutiveFailures == 0 && !health.Stopped
+	return health
+}
+
+func (l *standardLogger) currentOutput() (io.Writer, bool) {
+	return l.logger.Writer(), true
+}
+
+// SetOutput swaps the writer shared with all derived loggers. The
+// underlying log.Logger serializes the swap with in-flight writes. An
+// output stopped by broken pipe errors is resumed.
+func (l *standardLogger) SetOutput(w io.Writer) error {
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+
+	name := l.outputName()
+	if l.out.ignoreSIGPIPE {
+		guardBrokenPipe(w)
+	}
+	l.logger.SetOutput(w)
+	l.out.brokenRun = 0
+	atomic.StoreInt32(&l.out.broken, 0)
+	if l.format.colorable() {
+		l.out.updateColor(w)
+	}
+
+	return l.closeOwned(name)
+}
+
+// Flush returns nil: entries are written synchronously, so nothing is
+// ever buffered
+func (l *standardLogger) Flush() error {
+	return nil
+}
+
+// Close releases the output if the logger opened it, such as the file of
+// a logger created by CreateFileLogger. Every logger sharing the output is
+// affected; writers it was not given ownership of are left open. Close
+// also stops the goroutine evaluating Provided fields and detaches the
+// output from its DiskWatchdog.
+func (l *standardLogger) Close() error {
+	l.providers.stop()
+	if l.disk != nil {
+		l.disk.detach(l.out)
+	}
+
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+
+	return l.closeOwned(l.outputName())
+}
+
+// closeOwned closes the output the logger opened, if any, reporting a
+// failure as a *WriteError for dest. The caller holds l.out.mu.
+func (l *standardLogger) closeOwned(dest string) error {
+	owned := l.out.owned
+	l.out.owned = nil
+	if owned == nil {
+		return nil
+	}
+	if err := owned.Close(); err != nil {
+		return &WriteError{Dest: dest, Cause: err}
+	}
+	return nil
+}
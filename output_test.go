@@ -0,0 +1,223 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestSetOutputPropagatesToChildren(t *testing.T) {
+	var before, after bytes.Buffer
+	log := logger.New(logger.Config{Output: &before})
+	child := log.With(logger.Field{Key: "child", Value: true})
+
+	if err := log.(logger.OutputSetter).SetOutput(&after); err != nil {
+		t.Fatalf("SetOutput failed: %v", err)
+	}
+	child.Info("after swap")
+
+	if before.Len() != 0 {
+		t.Errorf("Expected nothing written to the old output, got: %s", before.String())
+	}
+	if !strings.Contains(after.String(), "after swap {child=true}") {
+		t.Errorf("Expected child to follow the new output, got: %s", after.String())
+	}
+}
+
+func TestSetOutputClosesOwnedFile(t *testing.T) {
+	log, err := logger.CreateFileLogger(filepath.Join(t.TempDir(), "app.log"), logger.InfoLevel)
+	if err != nil {
+		t.Fatalf("Failed to create file logger: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := log.(logger.OutputSetter).SetOutput(&buf); err != nil {
+		t.Fatalf("Expected the owned file to close cleanly: %v", err)
+	}
+	// The buffer is not owned, so swapping again must not try to close it
+	if err := log.(logger.OutputSetter).SetOutput(&bytes.Buffer{}); err != nil {
+		t.Fatalf("SetOutput failed: %v", err)
+	}
+}
+
+func TestSetOutputConcurrent(t *testing.T) {
+	first, second := &syncBuffer{}, &syncBuffer{}
+	log := logger.MultiLogger(logger.New(logger.Config{Output: first}))
+	setter := log.(logger.OutputSetter)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				log.Info("concurrent entry", logger.Field{Key: "goroutine", Value: id})
+			}
+		}(i)
+	}
+	for i := 0; i < 20; i++ {
+		if i%2 == 0 {
+			setter.SetOutput(second)
+		} else {
+			setter.SetOutput(first)
+		}
+	}
+	wg.Wait()
+
+	total := 0
+	for _, buf := range []*syncBuffer{first, second} {
+		for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+			if line == "" {
+				continue
+			}
+			total++
+			if !strings.Contains(line, "[INFO] concurrent entry {goroutine=") || !strings.HasSuffix(line, "}") {
+				t.Fatalf("Found a torn line: %q", line)
+			}
+		}
+	}
+	if total != 800 {
+		t.Errorf("Expected 800 entries across both outputs, got %d", total)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestChildrenHealth(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.MultiLogger(
+		logger.New(logger.Config{Output: &buf, OutputName: "console"}),
+		logger.New(logger.Config{Output: failingWriter{}, OutputName: "file"}),
+	)
+
+	log.Info("first")
+	log.Info("second")
+
+	if strings.Count(buf.String(), "[INFO]") != 2 {
+		t.Errorf("Expected the healthy child to keep writing, got: %s", buf.String())
+	}
+
+	health := log.(logger.ChildrenHealthReporter).ChildrenHealth()
+	if len(health) != 2 {
+		t.Fatalf("Expected health for two children, got: %+v", health)
+	}
+	if health[0].Name != "console" || !health[0].Healthy || health[0].LastSuccess.IsZero() {
+		t.Errorf("Expected healthy console child, got: %+v", health[0])
+	}
+	if health[1].Name != "file" || health[1].Healthy || health[1].ConsecutiveFailures != 2 || health[1].LastError != "disk full" {
+		t.Errorf("Expected failing file child, got: %+v", health[1])
+	}
+
+	d := logger.Describe(log)
+	if d.Children[1].Health == nil || d.Children[1].Health.Healthy {
+		t.Errorf("Expected description to include health, got: %+v", d.Children[1])
+	}
+}
+
+func TestHealthRecovers(t *testing.T) {
+	log := logger.New(logger.Config{Output: failingWriter{}})
+	log.Info("fails")
+
+	log.(logger.OutputSetter).SetOutput(&bytes.Buffer{})
+	log.Info("succeeds")
+
+	health := log.(logger.HealthReporter).Health()
+	if !health.Healthy || health.ConsecutiveFailures != 0 || health.LastError != "disk full" {
+		t.Errorf("Expected health to recover after a successful write, got: %+v", health)
+	}
+}
+
+func TestMultiLoggerSetOutputMixedOutputs(t *testing.T) {
+	var console, replacement bytes.Buffer
+	path := filepath.Join(t.TempDir(), "app.log")
+	file, err := logger.CreateFileLogger(path, logger.InfoLevel)
+	if err != nil {
+		t.Fatalf("Failed to create file logger: %v", err)
+	}
+	defer file.Close()
+	log := logger.MultiLogger(logger.New(logger.Config{Output: &console}), file)
+
+	if err := log.(logger.OutputSetter).SetOutput(&replacement); err == nil {
+		t.Fatal("Expected an error for children writing to different outputs")
+	}
+	log.Info("still split")
+
+	if replacement.Len() != 0 {
+		t.Errorf("Expected no child to move to the new writer, got: %s", replacement.String())
+	}
+	if !strings.Contains(console.String(), "still split") {
+		t.Errorf("Expected the console child to keep its output, got: %s", console.String())
+	}
+	content, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(content), "still split") {
+		t.Errorf("Expected the file to stay open and receive the entry, got %q, %v", content, err)
+	}
+}
+
+// sliceWriter is a writer that cannot be compared with ==
+type sliceWriter struct{ lines []string }
+
+func (w sliceWriter) Write(p []byte) (int, error) { return len(p), nil }
+
+// boxedWriter is comparable but holds a writer that may not be
+type boxedWriter struct{ io.Writer }
+
+func TestMultiLoggerSetOutputUncomparableOutputs(t *testing.T) {
+	for name, w := range map[string]io.Writer{
+		"uncomparable": sliceWriter{lines: []string{"a"}},
+		"boxed":        boxedWriter{sliceWriter{}},
+	} {
+		log := logger.MultiLogger(
+			logger.New(logger.Config{Output: w}),
+			logger.New(logger.Config{Output: w}),
+		)
+		if err := log.(logger.OutputSetter).SetOutput(&bytes.Buffer{}); err == nil {
+			t.Errorf("%s: expected an error for writers that cannot be compared", name)
+		}
+	}
+}
+
+func TestMultiLoggerSetOutputSharedOutput(t *testing.T) {
+	var before, after bytes.Buffer
+	log := logger.MultiLogger(
+		logger.New(logger.Config{Output: &before}),
+		logger.New(logger.Config{Output: &before, Format: logger.FormatJSON}),
+	)
+
+	if err := log.(logger.OutputSetter).SetOutput(&after); err != nil {
+		t.Fatalf("Expected children sharing a writer to move together: %v", err)
+	}
+	log.Info("moved")
+	if before.Len() != 0 || strings.Count(after.String(), "moved") != 2 {
+		t.Errorf("Expected both children on the new writer, got before=%q after=%q", before.String(), after.String())
+	}
+}
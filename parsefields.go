@@ -0,0 +1,162 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseFieldsOption configures ParseFields
+type ParseFieldsOption func(*parseFieldsOptions)
+
+type parseFieldsOptions struct {
+	stringsOnly bool
+}
+
+// ParseFieldsAsStrings keeps every parsed value as a string instead of
+// inferring ints, floats and bools
+func ParseFieldsAsStrings() ParseFieldsOption {
+	return func(o *parseFieldsOptions) {
+		o.stringsOnly = true
+	}
+}
+
+// ParseFields parses comma separated key=value pairs, such as the value of
+// a --log-fields flag, into fields in the order given. Values containing
+// commas, equals signs or quotes are written as Go double quoted strings:
+//
+//	service=api,replica=3,note="a, b"
+//
+// Unquoted values that parse as integers, floats or true/false become
+// int, float64 and bool values unless ParseFieldsAsStrings is given;
+// quoted values are always strings. Spaces around pairs are ignored. An
+// empty string yields no fields. Empty pairs, empty or duplicate keys and
+// malformed quoting are errors naming the offending pair.
+func ParseFields(s string, opts ...ParseFieldsOption) ([]Field, error) {
+	var o parseFieldsOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	var fields []Field
+	seen := map[string]bool{}
+	for pos := 0; pos <= len(s); {
+		token, value, quoted, next, err := nextFieldPair(s, pos)
+		if err != nil {
+			return nil, fmt.Errorf("field %d %q: %w", len(fields)+1, token, err)
+		}
+		pos = next
+
+		eq := strings.IndexByte(token, '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("field %d %q: missing '='", len(fields)+1, token)
+		}
+		key := strings.TrimSpace(token[:eq])
+		if key == "" {
+			return nil, fmt.Errorf("field %d %q: empty key", len(fields)+1, token)
+		}
+		if strings.ContainsAny(key, `"`) {
+			return nil, fmt.Errorf("field %d %q: quote in key", len(fields)+1, token)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("field %d %q: duplicate key %q", len(fields)+1, token, key)
+		}
+		seen[key] = true
+
+		var v any = value
+		if !quoted && !o.stringsOnly {
+			v = inferFieldValue(value)
+		}
+		fields = append(fields, Field{Key: key, Value: v})
+	}
+	return fields, nil
+}
+
+// nextFieldPair scans the pair starting at pos. It returns the raw token
+// for error messages, the unquoted value, whether the value was quoted and
+// the position after the separating comma, or len(s)+1 at the end.
+func nextFieldPair(s string, pos int) (token, value string, quoted bool, next int, err error) {
+	end := pos
+	eq := -1
+	for end < len(s) && s[end] != ',' {
+		if s[end] == '=' {
+			eq = end
+			break
+		}
+		end++
+	}
+	if eq < 0 {
+		token = strings.TrimSpace(s[pos:end])
+		if token == "" {
+			return token, "", false, 0, fmt.Errorf("empty field")
+		}
+		return token, "", false, end + 1, nil
+	}
+
+	// Find the end of the value, honoring quotes
+	start := eq + 1
+	for start < len(s) && s[start] == ' ' {
+		start++
+	}
+	if start < len(s) && s[start] == '"' {
+		closing := start + 1
+		for closing < len(s) && s[closing] != '"' {
+			if s[closing] == '\\' {
+				closing++
+			}
+			closing++
+		}
+		if closing >= len(s) {
+			return strings.TrimSpace(s[pos:]), "", true, 0, fmt.Errorf("unterminated quoted value")
+		}
+		token = strings.TrimSpace(s[pos : closing+1])
+		value, err = strconv.Unquote(s[start : closing+1])
+		if err != nil {
+			return token, "", true, 0, fmt.Errorf("invalid quoted value: %w", err)
+		}
+
+		end = closing + 1
+		for end < len(s) && s[end] == ' ' {
+			end++
+		}
+		if end < len(s) && s[end] != ',' {
+			return strings.TrimSpace(s[pos:]), "", true, 0, fmt.Errorf("unexpected text after quoted value")
+		}
+		return token, value, true, end + 1, nil
+	}
+
+	end = start
+	for end < len(s) && s[end] != ',' {
+		end++
+	}
+	token = strings.TrimSpace(s[pos:end])
+	value = strings.TrimSpace(s[start:end])
+	if strings.ContainsAny(value, `="`) {
+		return token, "", false, 0, fmt.Errorf("unquoted value contains '=' or '\"'")
+	}
+	return token, value, false, end + 1, nil
+}
+
+// inferFieldValue converts an unquoted value to an int, float64 or bool
+// when it is unambiguously one
+func inferFieldValue(s string) any {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n
+	}
+	if strings.ContainsAny(s, "0123456789") {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return s
+}
@@ -0,0 +1,127 @@
+package logger_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestParseFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     []logger.ParseFieldsOption
+		expected []logger.Field
+	}{
+		{name: "empty", input: "  "},
+		{
+			name:  "inferred types",
+			input: "service=api,replica=3,ratio=0.5,canary=true,version=1.2.3",
+			expected: []logger.Field{
+				{Key: "service", Value: "api"},
+				{Key: "replica", Value: 3},
+				{Key: "ratio", Value: 0.5},
+				{Key: "canary", Value: true},
+				{Key: "version", Value: "1.2.3"},
+			},
+		},
+		{
+			name:  "strings only",
+			input: "replica=3,canary=true",
+			opts:  []logger.ParseFieldsOption{logger.ParseFieldsAsStrings()},
+			expected: []logger.Field{
+				{Key: "replica", Value: "3"},
+				{Key: "canary", Value: "true"},
+			},
+		},
+		{
+			name:  "quoted values",
+			input: `note="a, b", expr="x=1", quote="say \"hi\"", n="3"`,
+			expected: []logger.Field{
+				{Key: "note", Value: "a, b"},
+				{Key: "expr", Value: "x=1"},
+				{Key: "quote", Value: `say "hi"`},
+				{Key: "n", Value: "3"},
+			},
+		},
+		{
+			name:  "spaces and empty values",
+			input: " env = prod , empty= ",
+			expected: []logger.Field{
+				{Key: "env", Value: "prod"},
+				{Key: "empty", Value: ""},
+			},
+		},
+		{
+			name:     "words that look like numbers stay strings",
+			input:    "a=NaN,b=inf,c=TRUE",
+			expected: []logger.Field{{Key: "a", Value: "NaN"}, {Key: "b", Value: "inf"}, {Key: "c", Value: "TRUE"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := logger.ParseFields(tt.input, tt.opts...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(fields, tt.expected) {
+				t.Errorf("Expected %#v, got %#v", tt.expected, fields)
+			}
+		})
+	}
+}
+
+func TestParseFieldsErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "a=1,b", expected: `field 2 "b": missing '='`},
+		{input: "a=1,,b=2", expected: `field 2 "": empty field`},
+		{input: "a=1,", expected: `field 2 "": empty field`},
+		{input: "=1", expected: `field 1 "=1": empty key`},
+		{input: "a=1,a=2", expected: `duplicate key "a"`},
+		{input: `a="open`, expected: `field 1 "a=\"open": unterminated quoted value`},
+		{input: `a="x"y`, expected: "unexpected text after quoted value"},
+		{input: `a="\q"`, expected: "invalid quoted value"},
+		{input: "a=b=c", expected: `field 1 "a=b=c": unquoted value contains`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := logger.ParseFields(tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func FuzzParseFields(f *testing.F) {
+	for _, seed := range []string{
+		"service=api,replica=3",
+		`note="a, b",x=1.5`,
+		`a="\"`,
+		"=,=,",
+		`a="x"  , b = "y\\"`,
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		fields, err := logger.ParseFields(input)
+		if err != nil {
+			return
+		}
+		seen := map[string]bool{}
+		for _, field := range fields {
+			if field.Key == "" || seen[field.Key] {
+				t.Errorf("Invalid or duplicate key %q parsed from %q", field.Key, input)
+			}
+			seen[field.Key] = true
+		}
+	})
+}
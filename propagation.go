@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// TextMapCarrier is the string header map of a message or request, as
+// exposed by messaging clients for Kafka, NATS or SQS, that
+// InjectContext and ExtractContext carry IDs in
+type TextMapCarrier interface {
+	Get(key string) string
+	Set(key, value string)
+	Keys() []string
+}
+
+// MapCarrier adapts map[string]string to TextMapCarrier
+type MapCarrier map[string]string
+
+// Get returns the value of key
+func (c MapCarrier) Get(key string) string {
+	return c[key]
+}
+
+// Set stores value under key
+func (c MapCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+// Keys returns the keys of the map
+func (c MapCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// propagatedKey pairs a context key holding a string with its header
+type propagatedKey struct {
+	key    any
+	header string
+}
+
+var (
+	propagatedMu sync.Mutex
+	// propagated holds an immutable []propagatedKey, replaced on every
+	// registration so contexts can be injected without locking
+	propagated atomic.Value
+)
+
+func init() {
+	propagated.Store([]propagatedKey{
+		{key: RequestIDKey, header: "X-Request-Id"},
+		{key: UserIDKey, header: "X-User-Id"},
+		{key: SessionIDKey, header: "X-Session-Id"},
+	})
+}
+
+// RegisterPropagatedKey makes InjectContext and ExtractContext carry the
+// string stored in contexts under key in the named header, for example a
+// trace ID. The request, user and session IDs are registered as
+// X-Request-Id, X-User-Id and X-Session-Id. Registering a header twice is
+// an error.
+func RegisterPropagatedKey(key any, header string) error {
+	propagatedMu.Lock()
+	defer propagatedMu.Unlock()
+
+	current := propagated.Load().([]propagatedKey)
+	for _, p := range current {
+		if strings.EqualFold(p.header, header) {
+			return fmt.Errorf("header %q is already propagated", header)
+		}
+	}
+
+	next := make([]propagatedKey, len(current), len(current)+1)
+	copy(next, current)
+	next = append(next, propagatedKey{key: key, header: header})
+	propagated.Store(next)
+	return nil
+}
+
+// InjectContext writes the IDs stored in ctx into carrier, one header per
+// propagated key. Keys without a non-empty string in ctx are skipped.
+func InjectContext(ctx context.Context, carrier TextMapCarrier) {
+	for _, p := range propagated.Load().([]propagatedKey) {
+		if value, ok := ctx.Value(p.key).(string); ok && value != "" {
+			carrier.Set(p.header, value)
+		}
+	}
+}
+
+// ExtractContext returns ctx with the IDs found in carrier, so a message
+// consumer logs with the request ID of the producer. Header names are
+// matched case insensitively, since some clients lowercase them. Headers
+// that are absent or empty leave ctx unchanged.
+func ExtractContext(ctx context.Context, carrier TextMapCarrier) context.Context {
+	keys := carrier.Keys()
+	for _, p := range propagated.Load().([]propagatedKey) {
+		value := carrier.Get(p.header)
+		if value == "" {
+			for _, key := range keys {
+				if strings.EqualFold(key, p.header) {
+					value = carrier.Get(key)
+					break
+				}
+			}
+		}
+		if value != "" {
+			ctx = context.WithValue(ctx, p.key, value)
+		}
+	}
+	return ctx
+}
@@ -0,0 +1,83 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+type traceIDKey struct{}
+
+func init() {
+	if err := logger.RegisterPropagatedKey(traceIDKey{}, "X-Trace-Id"); err != nil {
+		panic(err)
+	}
+}
+
+func TestContextPropagationRoundTrip(t *testing.T) {
+	testPropagationRoundTrip(t, logger.MapCarrier{})
+}
+
+// testPropagationRoundTrip injects IDs into carrier and extracts them
+// again, with the user ID absent
+func testPropagationRoundTrip(t *testing.T, carrier logger.TextMapCarrier) {
+	t.Helper()
+	ctx := logger.WithRequestID(context.Background(), "req-1")
+	ctx = logger.WithSessionID(ctx, "sess-9")
+	ctx = context.WithValue(ctx, traceIDKey{}, "trace-7")
+
+	logger.InjectContext(ctx, carrier)
+	if len(carrier.Keys()) != 3 {
+		t.Errorf("Expected three headers with the user ID absent, got %v", carrier.Keys())
+	}
+
+	restored := logger.ExtractContext(context.Background(), carrier)
+
+	if id, _ := logger.GetRequestID(restored); id != "req-1" {
+		t.Errorf("Expected request ID req-1, got %q", id)
+	}
+	if id, _ := logger.GetSessionID(restored); id != "sess-9" {
+		t.Errorf("Expected session ID sess-9, got %q", id)
+	}
+	if id, _ := restored.Value(traceIDKey{}).(string); id != "trace-7" {
+		t.Errorf("Expected trace ID trace-7, got %q", id)
+	}
+	if _, ok := logger.GetUserID(restored); ok {
+		t.Error("Expected the absent user ID to stay absent")
+	}
+}
+
+func TestExtractContextIgnoresHeaderCase(t *testing.T) {
+	carrier := logger.MapCarrier{"x-request-id": "req-2", "x-user-id": ""}
+
+	ctx := logger.ExtractContext(logger.WithUserID(context.Background(), "kept"), carrier)
+
+	if id, _ := logger.GetRequestID(ctx); id != "req-2" {
+		t.Errorf("Expected a lowercased header to be found, got %q", id)
+	}
+	if id, _ := logger.GetUserID(ctx); id != "kept" {
+		t.Errorf("Expected an empty header to leave the context unchanged, got %q", id)
+	}
+}
+
+func TestExtractedContextLogs(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+	headers := logger.MapCarrier{}
+	logger.InjectContext(logger.WithRequestID(context.Background(), "req-3"), headers)
+
+	log.WithContext(logger.ExtractContext(context.Background(), headers)).Info("consumed")
+
+	if !strings.Contains(buf.String(), "consumed {request_id=req-3}") {
+		t.Errorf("Expected the producer's request ID, got: %s", buf.String())
+	}
+}
+
+func TestRegisterPropagatedKeyTwice(t *testing.T) {
+	if err := logger.RegisterPropagatedKey(struct{}{}, "x-trace-id"); err == nil {
+		t.Error("Expected registering a header twice to fail")
+	}
+}
@@ -0,0 +1,153 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultProviderTimeout is the default budget for Provided fields
+const DefaultProviderTimeout = 10 * time.Millisecond
+
+// Markers written in place of Provided values that could not be produced
+const (
+	providerTimeoutMarker = "<provider timeout>"
+	providerPanicMarker   = "<provider panic>"
+)
+
+// providedValue is the value of fields created with Provided
+type providedValue func() any
+
+// Provided returns a field whose value is produced by fn every time an
+// entry carrying the field is written, unlike values captured when With
+// is called. Use it for state that changes over time, such as a queue
+// depth.
+//
+// fn runs on a worker goroutine owned by the logger and the entry waits
+// for it up to Config.ProviderTimeout while holding the logger's lock, so
+// providers must be cheap. A provider that exceeds the budget or panics is
+// replaced by a marker value. A slow provider keeps the worker busy until
+// it returns, and entries written meanwhile get the timeout marker.
+func Provided(key string, fn func() any) Field {
+	return Field{Key: key, Value: providedValue(fn)}
+}
+
+type providerJob struct {
+	seq uint64
+	fn  providedValue
+}
+
+type providerResult struct {
+	seq   uint64
+	value any
+	err   error
+}
+
+// providerWorker evaluates the Provided fields of a logger and everything
+// derived from it on one goroutine. The goroutine is started by the first
+// evaluation and joined by Close; entries written after Close start it
+// again.
+type providerWorker struct {
+	mu      sync.Mutex // serializes evaluations and guards the fields below
+	jobs    chan providerJob
+	results chan providerResult
+	done    chan struct{}
+	timer   *time.Timer
+	seq     uint64
+	tasks   backgroundGroup
+}
+
+// evaluateProvider runs fn within the logger's provider budget
+func (l *standardLogger) evaluateProvider(fn providedValue) any {
+	return l.providers.evaluate(fn, l.provideDur)
+}
+
+// evaluate runs fn on the worker and waits for it up to budget
+func (w *providerWorker) evaluate(fn providedValue, budget time.Duration) any {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.done == nil {
+		w.start()
+	}
+	if !w.timer.Stop() {
+		select {
+		case <-w.timer.C:
+		default:
+		}
+	}
+	w.timer.Reset(budget)
+
+	w.seq++
+	select {
+	case w.jobs <- providerJob{seq: w.seq, fn: fn}:
+	case <-w.timer.C:
+		// The worker is still busy with a slow provider
+		return providerTimeoutMarker
+	}
+	for {
+		select {
+		case r := <-w.results:
+			if r.seq != w.seq {
+				// The late result of a provider that timed out
+				continue
+			}
+			if r.err != nil {
+				return providerPanicMarker
+			}
+			return r.value
+		case <-w.timer.C:
+			return providerTimeoutMarker
+		}
+	}
+}
+
+// start starts the worker goroutine. w.mu must be held.
+func (w *providerWorker) start() {
+	w.jobs = make(chan providerJob)
+	w.results = make(chan providerResult, 1)
+	w.done = make(chan struct{})
+	if w.timer == nil {
+		w.timer = time.NewTimer(time.Hour)
+	}
+
+	jobs, results, done := w.jobs, w.results, w.done
+	w.tasks.Go(func() {
+		for {
+			select {
+			case job := <-jobs:
+				select {
+				case results <- runProvider(job):
+				case <-done:
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	})
+}
+
+// stop ends the worker goroutine and waits for it, which includes waiting
+// for a provider it is still running
+func (w *providerWorker) stop() {
+	w.mu.Lock()
+	if w.done != nil {
+		close(w.done)
+		w.jobs, w.results, w.done = nil, nil, nil
+	}
+	w.mu.Unlock()
+	w.tasks.Wait()
+}
+
+// runProvider calls the provider of job, recovering a panic
+func runProvider(job providerJob) (result providerResult) {
+	result.seq = job.seq
+	defer func() {
+		if r := recover(); r != nil {
+			result.err = fmt.Errorf("%v", r)
+		}
+	}()
+	result.value = job.fn()
+	return result
+}
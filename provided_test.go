@@ -0,0 +1,99 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestProvidedEvaluatedPerEntry(t *testing.T) {
+	var buf bytes.Buffer
+	depth := 0
+	base := logger.New(logger.Config{Output: &buf})
+	defer base.Close()
+	log := base.With(logger.Provided("queue_depth", func() any {
+		depth++
+		return depth
+	}))
+
+	log.Info("first")
+	log.Info("second")
+
+	output := buf.String()
+	if !strings.Contains(output, "first {queue_depth=1}") || !strings.Contains(output, "second {queue_depth=2}") {
+		t.Errorf("Expected provider to be evaluated for every entry, got: %s", output)
+	}
+}
+
+func TestProvidedSlowProvider(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, ProviderTimeout: 5 * time.Millisecond})
+	defer log.Close()
+	release := make(chan struct{})
+	defer close(release)
+
+	start := time.Now()
+	log.Info("slow", logger.Provided("free_disk", func() any {
+		<-release
+		return 0
+	}))
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the entry to be written within the budget, took %v", elapsed)
+	}
+	if !strings.Contains(buf.String(), `free_disk="<provider timeout>"`) {
+		t.Errorf("Expected timeout marker, got: %s", buf.String())
+	}
+}
+
+func TestProvidedPanickingProvider(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+	defer log.Close()
+
+	log.Info("panic", logger.Provided("generation", func() any {
+		panic("boom")
+	}))
+	log.Info("still logging")
+
+	output := buf.String()
+	if !strings.Contains(output, `generation="<provider panic>"`) {
+		t.Errorf("Expected panic marker, got: %s", output)
+	}
+	if !strings.Contains(output, "still logging") {
+		t.Error("Expected logger to keep working after a panicking provider")
+	}
+}
+
+func TestProvidedUsesOneWorker(t *testing.T) {
+	var buf syncBuffer
+	log := logger.New(logger.Config{Output: &buf, ProviderTimeout: 5 * time.Millisecond})
+	defer log.Close()
+	release := make(chan struct{})
+	stale := logger.Provided("value", func() any {
+		<-release
+		return "stale"
+	})
+
+	// Entries written while the worker is stuck time out without starting
+	// more goroutines
+	for i := 0; i < 5; i++ {
+		log.Info("stuck", stale)
+	}
+	if running := logger.RunningBackgroundTasks(); running != 1 {
+		t.Errorf("Expected one provider goroutine, got %d", running)
+	}
+	if strings.Count(buf.String(), `value="<provider timeout>"`) != 5 {
+		t.Errorf("Expected every stuck entry to time out, got: %s", buf.String())
+	}
+
+	// The late result of the slow provider is not given to the next entry
+	close(release)
+	log.Info("fresh", logger.Provided("value", func() any { return "fresh" }))
+	if !strings.Contains(buf.String(), "fresh {value=fresh}") {
+		t.Errorf("Expected the current provider's value, got: %s", buf.String())
+	}
+}
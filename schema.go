@@ -0,0 +1,222 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Defaults for NewSchemaObserver
+const (
+	DefaultSchemaMaxKeys     = 1000
+	DefaultSchemaSampleEvery = 1
+)
+
+// maxSchemaExampleLength bounds the example value kept per key
+const maxSchemaExampleLength = 64
+
+// SchemaObserver samples the fields of written entries to infer the type
+// of every key, for generating schemas downstream. Set it as
+// Config.SchemaObserver; several loggers may share one observer. It is
+// safe for concurrent use.
+type SchemaObserver struct {
+	maxKeys     int
+	sampleEvery int64
+	entries     int64
+
+	mu      sync.Mutex
+	keys    map[string]*keyStats
+	dropped int64
+}
+
+type keyStats struct {
+	count   int64
+	nulls   int64
+	types   map[string]int64
+	example string
+}
+
+// FieldSchema describes the values observed for one field key
+type FieldSchema struct {
+	Key string `json:"key"`
+	// Type is the most frequently observed non-null type: string, int,
+	// float, bool, duration, time, array, object or null
+	Type     string           `json:"type"`
+	Types    map[string]int64 `json:"types"`
+	Count    int64            `json:"count"`
+	NullRate float64          `json:"null_rate"`
+	// Example is a rendered value, after any redaction applied by the
+	// field's constructor, truncated to 64 bytes
+	Example string `json:"example,omitempty"`
+}
+
+// NewSchemaObserver returns an observer tracking at most maxKeys distinct
+// keys and sampling one entry in every sampleEvery. Non-positive values
+// select DefaultSchemaMaxKeys and DefaultSchemaSampleEvery.
+func NewSchemaObserver(maxKeys, sampleEvery int) *SchemaObserver {
+	if maxKeys <= 0 {
+		maxKeys = DefaultSchemaMaxKeys
+	}
+	if sampleEvery <= 0 {
+		sampleEvery = DefaultSchemaSampleEvery
+	}
+	return &SchemaObserver{
+		maxKeys:     maxKeys,
+		sampleEvery: int64(sampleEvery),
+		keys:        make(map[string]*keyStats),
+	}
+}
+
+// observe records the fields of one entry if it is sampled
+func (o *SchemaObserver) observe(fields []Field) {
+	if (atomic.AddInt64(&o.entries, 1)-1)%o.sampleEvery != 0 {
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.observeFields("", fields)
+}
+
+func (o *SchemaObserver) observeFields(prefix string, fields []Field) {
+	for _, field := range fields {
+		key := prefix + field.Key
+		if group, ok := field.Value.(groupValue); ok {
+			o.observeFields(key+".", group)
+			continue
+		}
+
+		stats, ok := o.keys[key]
+		if !ok {
+			if len(o.keys) >= o.maxKeys {
+				o.dropped++
+				continue
+			}
+			stats = &keyStats{types: make(map[string]int64)}
+			o.keys[key] = stats
+		}
+
+		typ := schemaType(field.Value)
+		stats.count++
+		stats.types[typ]++
+		if typ == "null" {
+			stats.nulls++
+		} else if stats.example == "" {
+			example, _ := formatValue(field.Value)
+			stats.example = truncateString(example, maxSchemaExampleLength)
+		}
+	}
+}
+
+// schemaType classifies a field value
+func schemaType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case time.Duration, timeSpan, durationValue:
+		return "duration"
+	case time.Time:
+		return "time"
+	case byteSize:
+		return "int"
+	case codeValue:
+		return "string"
+	case error, fmt.Stringer:
+		return "string"
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return "null"
+		}
+		return schemaType(rv.Elem().Interface())
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return "int"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return "null"
+		}
+		return "array"
+	case reflect.Map, reflect.Struct:
+		if rv.Kind() == reflect.Map && rv.IsNil() {
+			return "null"
+		}
+		return "object"
+	}
+	return "string"
+}
+
+// Report returns the schema of every observed key, sorted by key
+func (o *SchemaObserver) Report() []FieldSchema {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	schemas := make([]FieldSchema, 0, len(o.keys))
+	for key, stats := range o.keys {
+		types := make(map[string]int64, len(stats.types))
+		dominant, dominantCount := "null", int64(0)
+		for typ, n := range stats.types {
+			types[typ] = n
+			if typ == "null" {
+				continue
+			}
+			if n > dominantCount || (n == dominantCount && typ < dominant) {
+				dominant, dominantCount = typ, n
+			}
+		}
+		schemas = append(schemas, FieldSchema{
+			Key:      key,
+			Type:     dominant,
+			Types:    types,
+			Count:    stats.count,
+			NullRate: float64(stats.nulls) / float64(stats.count),
+			Example:  stats.example,
+		})
+	}
+
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].Key < schemas[j].Key
+	})
+	return schemas
+}
+
+// DroppedKeys returns how many field occurrences were not tracked because
+// the key limit had been reached
+func (o *SchemaObserver) DroppedKeys() int64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.dropped
+}
+
+// ExportJSON returns the report as a JSON array, for a schema registry
+func (o *SchemaObserver) ExportJSON() ([]byte, error) {
+	return json.Marshal(o.Report())
+}
+
+// LogSchemaReport writes one Info entry per observed key, tagged
+// field_schema=true. Call it periodically to keep the schema in the log
+// stream itself.
+func LogSchemaReport(l LevelLogger, o *SchemaObserver) {
+	for _, s := range o.Report() {
+		l.Info("field schema",
+			Field{Key: "field_schema", Value: true},
+			Field{Key: "key", Value: s.Key},
+			Field{Key: "type", Value: s.Type},
+			Field{Key: "count", Value: s.Count},
+			Field{Key: "null_rate", Value: s.NullRate},
+		)
+	}
+}
@@ -0,0 +1,126 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func schemaByKey(o *logger.SchemaObserver) map[string]logger.FieldSchema {
+	schemas := map[string]logger.FieldSchema{}
+	for _, s := range o.Report() {
+		schemas[s.Key] = s
+	}
+	return schemas
+}
+
+func TestSchemaTypeInference(t *testing.T) {
+	observer := logger.NewSchemaObserver(0, 0)
+	log := logger.New(logger.Config{Output: io.Discard, SchemaObserver: observer}).
+		With(logger.Field{Key: "service", Value: "api"})
+
+	log.Info("a",
+		logger.Field{Key: "duration_ms", Value: 12.5},
+		logger.Field{Key: "status", Value: 200},
+		logger.Field{Key: "user", Value: nil},
+		logger.Field{Key: "elapsed", Value: time.Second},
+		logger.Field{Key: "tags", Value: []string{"x"}},
+		logger.Group("http", logger.Field{Key: "method", Value: "GET"}),
+	)
+	log.Info("b",
+		logger.Field{Key: "duration_ms", Value: 7.0},
+		logger.Field{Key: "status", Value: "200"},
+		logger.Field{Key: "user", Value: map[string]string{"id": "u1"}},
+		logger.Field{Key: "err", Value: errors.New("boom")},
+	)
+	log.Info("c",
+		logger.Field{Key: "duration_ms", Value: "slow"},
+		logger.Field{Key: "status", Value: 404},
+		logger.Field{Key: "ok", Value: true},
+	)
+
+	schemas := schemaByKey(observer)
+	tests := []struct {
+		key      string
+		typ      string
+		count    int64
+		nullRate float64
+	}{
+		{key: "service", typ: "string", count: 3},
+		{key: "duration_ms", typ: "float", count: 3},
+		{key: "status", typ: "int", count: 3},
+		{key: "user", typ: "object", count: 2, nullRate: 0.5},
+		{key: "elapsed", typ: "duration", count: 1},
+		{key: "tags", typ: "array", count: 1},
+		{key: "http.method", typ: "string", count: 1},
+		{key: "err", typ: "string", count: 1},
+		{key: "ok", typ: "bool", count: 1},
+	}
+	for _, tt := range tests {
+		s, ok := schemas[tt.key]
+		if !ok {
+			t.Errorf("Expected key %s in the report", tt.key)
+			continue
+		}
+		if s.Type != tt.typ || s.Count != tt.count || s.NullRate != tt.nullRate {
+			t.Errorf("%s: expected type=%s count=%d null_rate=%v, got %+v", tt.key, tt.typ, tt.count, tt.nullRate, s)
+		}
+	}
+
+	if got := schemas["duration_ms"].Types; got["float"] != 2 || got["string"] != 1 {
+		t.Errorf("Expected mixed types to be counted, got %v", got)
+	}
+	if example := schemas["user"].Example; example != "map[id:u1]" {
+		t.Errorf("Expected the first non-null value as example, got %q", example)
+	}
+}
+
+func TestSchemaObserverLimits(t *testing.T) {
+	observer := logger.NewSchemaObserver(2, 2)
+	log := logger.New(logger.Config{Output: io.Discard, SchemaObserver: observer})
+
+	for i := 0; i < 4; i++ {
+		log.Info("entry",
+			logger.Field{Key: "a", Value: 1},
+			logger.Field{Key: "b", Value: 2},
+			logger.Field{Key: "c", Value: 3},
+		)
+	}
+
+	schemas := schemaByKey(observer)
+	if len(schemas) != 2 {
+		t.Errorf("Expected the key cap to hold, got %d keys", len(schemas))
+	}
+	if schemas["a"].Count != 2 {
+		t.Errorf("Expected every second entry to be sampled, got count %d", schemas["a"].Count)
+	}
+	if dropped := observer.DroppedKeys(); dropped != 2 {
+		t.Errorf("Expected 2 dropped occurrences, got %d", dropped)
+	}
+}
+
+func TestSchemaExport(t *testing.T) {
+	observer := logger.NewSchemaObserver(0, 0)
+	logger.New(logger.Config{Output: io.Discard, SchemaObserver: observer}).Info("x", logger.Field{Key: "n", Value: 1})
+
+	data, err := observer.ExportJSON()
+	if err != nil {
+		t.Fatalf("ExportJSON failed: %v", err)
+	}
+	var exported []logger.FieldSchema
+	if err := json.Unmarshal(data, &exported); err != nil || len(exported) != 1 || exported[0].Type != "int" {
+		t.Errorf("Expected one int key in the export, got %s (%v)", data, err)
+	}
+
+	var buf bytes.Buffer
+	logger.LogSchemaReport(logger.New(logger.Config{Output: &buf}), observer)
+	if !strings.Contains(buf.String(), "field_schema=true key=n type=int count=1") {
+		t.Errorf("Expected a schema entry, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"os"
+	"strconv"
+	"sync/atomic"
+)
+
+// signalSafeLineSize bounds a SignalSafeWrite line, including the prefix
+// and the newline. Longer messages are cut and end in "...".
+const signalSafeLineSize = 512
+
+// signalSafeSlots is the number of concurrent SignalSafeWrite calls that
+// can format at once. Calls finding every slot busy drop their line.
+const signalSafeSlots = 4
+
+// signalSafeOutput is the registered destination of SignalSafeWrite. It is
+// built once, with all of its buffers, and never modified afterwards.
+type signalSafeOutput struct {
+	file   *os.File // keeps the descriptor from being closed by a finalizer
+	fd     uintptr
+	prefix string
+	slots  [signalSafeSlots]signalSafeSlot
+}
+
+// signalSafeSlot is a line buffer claimed with busy
+type signalSafeSlot struct {
+	busy int32
+	buf  [signalSafeLineSize]byte
+}
+
+// signalSafe holds the registered *signalSafeOutput
+var signalSafe atomic.Value
+
+// SignalSafeFile selects the file SignalSafeWrite writes to, instead of
+// standard error
+func SignalSafeFile(f *os.File) InitOption {
+	return func(c *initConfig) {
+		c.signalSafeFile = f
+	}
+}
+
+// registerSignalSafe prepares SignalSafeWrite for writing to f. Lines
+// start with "service[pid] ", formatted now so that writing does not.
+func registerSignalSafe(f *os.File, service string) {
+	out := &signalSafeOutput{
+		file:   f,
+		fd:     f.Fd(),
+		prefix: service + "[" + strconv.Itoa(os.Getpid()) + "] ",
+	}
+	signalSafe.Store(out)
+}
+
+// SignalSafeWrite writes "service[pid] LEVEL msg" and a newline to the
+// file registered by Init, standard error unless SignalSafeFile was given.
+// It is meant for the last line written on the way down, from signal
+// handling code or after a crash, where the normal logging path may block
+// on a lock held by a stuck goroutine or allocate on a damaged heap.
+//
+// SignalSafeWrite takes no locks, does not allocate and performs a single
+// write system call on the registered descriptor. The line is formatted
+// into one of a few buffers allocated at registration; it carries no
+// timestamp, fields or caller, newlines in msg are replaced by spaces and
+// messages longer than the buffer are cut. It is a no-op before Init, and
+// a line is dropped if every buffer is in use by concurrent calls. Write
+// errors are ignored.
+//
+// Go code never runs inside the operating system's signal handler: the
+// runtime delivers signals to os/signal channels, which are read by
+// ordinary goroutines, and a C signal handler installed through cgo must
+// not call into Go at all. What SignalSafeWrite guarantees is that it does
+// not depend on any state the rest of the process may have left locked or
+// corrupted. It does not flush or synchronize other loggers, and entries
+// buffered elsewhere are not written.
+func SignalSafeWrite(level Level, msg string) {
+	out, _ := signalSafe.Load().(*signalSafeOutput)
+	if out == nil {
+		return
+	}
+	for i := range out.slots {
+		slot := &out.slots[i]
+		if !atomic.CompareAndSwapInt32(&slot.busy, 0, 1) {
+			continue
+		}
+		n := slot.format(out.prefix, level, msg)
+		writeFD(out.fd, slot.buf[:n])
+		atomic.StoreInt32(&slot.busy, 0)
+		return
+	}
+}
+
+// format fills the slot's buffer with the line and returns its length
+func (s *signalSafeSlot) format(prefix string, level Level, msg string) int {
+	const marker = "..."
+	limit := len(s.buf) - 1 // room for the newline
+
+	n := copy(s.buf[:limit], prefix)
+	n += copy(s.buf[n:limit], level.String())
+	if n < limit {
+		s.buf[n] = ' '
+		n++
+	}
+	for i := 0; i < len(msg); i++ {
+		if n == limit {
+			copy(s.buf[limit-len(marker):limit], marker)
+			break
+		}
+		c := msg[i]
+		if c == '\n' || c == '\r' {
+			c = ' '
+		}
+		s.buf[n] = c
+		n++
+	}
+	s.buf[n] = '\n'
+	return n + 1
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package logger
+
+import "syscall"
+
+// writeFD writes p to the descriptor fd with a single system call
+func writeFD(fd uintptr, p []byte) {
+	_, _ = syscall.Write(int(fd), p)
+}
@@ -0,0 +1,118 @@
+package logger_test
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+// initSignalSafe runs Init with SignalSafeWrite writing to a pipe and
+// returns its read end
+func initSignalSafe(t *testing.T) *bufio.Reader {
+	t.Helper()
+	previous := logger.GetDefaultLogger()
+	t.Cleanup(func() { logger.SetDefaultLogger(previous) })
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	t.Cleanup(func() {
+		r.Close()
+		w.Close()
+	})
+
+	logger.Init("svc", logger.SignalSafeFile(w))
+	return bufio.NewReader(r)
+}
+
+func TestSignalSafeWrite(t *testing.T) {
+	r := initSignalSafe(t)
+
+	logger.SignalSafeWrite(logger.FatalLevel, "shutting down\non SIGTERM")
+
+	line, err := r.ReadString('\n')
+	if err != nil {
+		t.Fatalf("Failed to read line: %v", err)
+	}
+	expected := fmt.Sprintf("svc[%d] FATAL shutting down on SIGTERM\n", os.Getpid())
+	if line != expected {
+		t.Errorf("Expected %q, got %q", expected, line)
+	}
+}
+
+func TestSignalSafeWriteTruncates(t *testing.T) {
+	r := initSignalSafe(t)
+
+	logger.SignalSafeWrite(logger.ErrorLevel, strings.Repeat("x", 2000))
+
+	line, err := r.ReadString('\n')
+	if err != nil {
+		t.Fatalf("Failed to read line: %v", err)
+	}
+	if len(line) != 512 || !strings.HasSuffix(line, "x...\n") {
+		t.Errorf("Expected a 512 byte line ending in a marker, got %d bytes: %q", len(line), line[len(line)-10:])
+	}
+}
+
+func TestSignalSafeWriteDoesNotAllocate(t *testing.T) {
+	r := initSignalSafe(t)
+	go io.Copy(io.Discard, r)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		logger.SignalSafeWrite(logger.ErrorLevel, "crashed")
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations, got %v", allocs)
+	}
+}
+
+// blockingWriter blocks every write until released, leaving the logger
+// writing to it holding its locks
+type blockingWriter struct {
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	w.entered <- struct{}{}
+	<-w.release
+	return len(p), nil
+}
+
+func TestSignalSafeWriteIgnoresHeldLocks(t *testing.T) {
+	r := initSignalSafe(t)
+
+	w := &blockingWriter{entered: make(chan struct{}), release: make(chan struct{})}
+	stuck := logger.New(logger.Config{Output: w})
+	logger.SetDefaultLogger(stuck)
+	go stuck.Info("stuck")
+	go logger.Info("also stuck")
+	<-w.entered
+	defer func() {
+		close(w.release)
+		<-w.entered
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		logger.SignalSafeWrite(logger.ErrorLevel, "still written")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected SignalSafeWrite not to wait for the blocked logger")
+	}
+
+	line, err := r.ReadString('\n')
+	if err != nil || !strings.Contains(line, "ERROR still written") {
+		t.Errorf("Expected the line to be written, got %q, %v", line, err)
+	}
+}
@@ -0,0 +1,8 @@
+package logger
+
+import "syscall"
+
+// writeFD writes p to the handle fd with a single system call
+func writeFD(fd uintptr, p []byte) {
+	_, _ = syscall.Write(syscall.Handle(fd), p)
+}
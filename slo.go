@@ -0,0 +1,238 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SLOMetricName is the name of the counter WriteSLOMetrics exposes, with
+// one series per SLO in its slo label
+const SLOMetricName = "log_slo_events_total"
+
+// noSLOLevel is the SLO floor while no SLO is registered: above every
+// level, so entries skip matching entirely
+const noSLOLevel = int32(FatalLevel) + 1
+
+// SLOMatcher selects the entries counted by an SLO. Matchers are built
+// with MatchLevel, MatchField, MatchAll and MatchAny.
+type SLOMatcher struct {
+	// minLevel is the lowest level the matcher can match
+	minLevel Level
+	match    func(level Level, fields []Field) bool
+}
+
+// MatchLevel matches entries at level or above
+func MatchLevel(level Level) SLOMatcher {
+	return SLOMatcher{
+		minLevel: level,
+		match:    func(l Level, _ []Field) bool { return l >= level },
+	}
+}
+
+// MatchField matches entries with a top-level field equal to value, such
+// as MatchField("domain", "checkout"). Codes can be matched with
+// MatchField("code", code).
+func MatchField(key string, value any) SLOMatcher {
+	return SLOMatcher{
+		minLevel: DebugLevel,
+		match: func(_ Level, fields []Field) bool {
+			for _, field := range fields {
+				if field.Key == key && valuesEqual(field.Value, value) {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// MatchAll matches entries matched by every one of matchers
+func MatchAll(matchers ...SLOMatcher) SLOMatcher {
+	min := DebugLevel
+	for _, m := range matchers {
+		if m.minLevel > min {
+			min = m.minLevel
+		}
+	}
+	return SLOMatcher{
+		minLevel: min,
+		match: func(level Level, fields []Field) bool {
+			for _, m := range matchers {
+				if !m.match(level, fields) {
+					return false
+				}
+			}
+			return true
+		},
+	}
+}
+
+// MatchAny matches entries matched by at least one of matchers
+func MatchAny(matchers ...SLOMatcher) SLOMatcher {
+	min := FatalLevel
+	for _, m := range matchers {
+		if m.minLevel < min {
+			min = m.minLevel
+		}
+	}
+	return SLOMatcher{
+		minLevel: min,
+		match: func(level Level, fields []Field) bool {
+			for _, m := range matchers {
+				if m.match(level, fields) {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// valuesEqual compares field values without panicking on uncomparable
+// types
+func valuesEqual(a, b any) bool {
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	if ta != tb {
+		return false
+	}
+	if ta == nil || !ta.Comparable() {
+		return ta == nil
+	}
+	return a == b
+}
+
+// SLOCount is the number of entries an SLO has counted
+type SLOCount struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+type slo struct {
+	name    string
+	matcher SLOMatcher
+	count   int64
+}
+
+var (
+	slosMu sync.Mutex
+	// slos holds an immutable []*slo, replaced on every registration so
+	// entries can be matched without locking
+	slos atomic.Value
+	// sloFloor is the lowest level any registered SLO can match
+	sloFloor = noSLOLevel
+)
+
+func init() {
+	slos.Store([]*slo(nil))
+}
+
+// RegisterSLO counts the entries matched by all of matchers under name,
+// for example failed checkouts:
+//
+//	logger.RegisterSLO("checkout_errors", logger.MatchLevel(logger.ErrorLevel), logger.MatchField("domain", "checkout"))
+//
+// Entries below the lowest level any SLO can match are not inspected, so
+// SLOs matching only errors leave Debug and Info logging untouched.
+// Entries fanned out by MultiLogger are counted once. Registering a name
+// twice is an error.
+func RegisterSLO(name string, matchers ...SLOMatcher) error {
+	slosMu.Lock()
+	defer slosMu.Unlock()
+
+	current := slos.Load().([]*slo)
+	for _, s := range current {
+		if s.name == name {
+			return fmt.Errorf("SLO %q is already registered", name)
+		}
+	}
+
+	matcher := MatchAll(matchers...)
+	next := make([]*slo, len(current), len(current)+1)
+	copy(next, current)
+	next = append(next, &slo{name: name, matcher: matcher})
+	slos.Store(next)
+	if int32(matcher.minLevel) < atomic.LoadInt32(&sloFloor) {
+		atomic.StoreInt32(&sloFloor, int32(matcher.minLevel))
+	}
+	return nil
+}
+
+// countSLOs counts an entry against every SLO it matches
+func countSLOs(level Level, fields []Field) {
+	if int32(level) < atomic.LoadInt32(&sloFloor) {
+		return
+	}
+	for _, s := range slos.Load().([]*slo) {
+		if level >= s.matcher.minLevel && s.matcher.match(level, fields) {
+			atomic.AddInt64(&s.count, 1)
+		}
+	}
+}
+
+// SLOCounts returns the count of every registered SLO, sorted by name
+func SLOCounts() []SLOCount {
+	current := slos.Load().([]*slo)
+	counts := make([]SLOCount, 0, len(current))
+	for _, s := range current {
+		counts = append(counts, SLOCount{Name: s.name, Count: atomic.LoadInt64(&s.count)})
+	}
+	sort.Slice(counts, func(i, j int) bool { return counts[i].Name < counts[j].Name })
+	return counts
+}
+
+// WriteSLOMetrics writes the SLO counts in the Prometheus text exposition
+// format as the counter SLOMetricName, for serving from a metrics
+// endpoint
+func WriteSLOMetrics(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("# HELP " + SLOMetricName + " Log entries matched by each registered SLO.\n")
+	b.WriteString("# TYPE " + SLOMetricName + " counter\n")
+	for _, count := range SLOCounts() {
+		fmt.Fprintf(&b, "%s{slo=%q} %d\n", SLOMetricName, count.Name, count.Count)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// SLOSummary writes the SLO counts to a logger periodically
+type SLOSummary struct {
+	stop chan struct{}
+	once sync.Once
+	bg   backgroundGroup
+}
+
+// StartSLOSummary logs an "slo summary" entry at Info with one field per
+// SLO every interval, until Stop is called
+func StartSLOSummary(log Logger, interval time.Duration) *SLOSummary {
+	s := &SLOSummary{stop: make(chan struct{})}
+	s.bg.Go(func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				counts := SLOCounts()
+				fields := make([]Field, 0, len(counts))
+				for _, count := range counts {
+					fields = append(fields, Field{Key: count.Name, Value: count.Count})
+				}
+				log.Info("slo summary", Group("slo", fields...))
+			case <-s.stop:
+				return
+			}
+		}
+	})
+	return s
+}
+
+// Stop ends the summaries and waits for the goroutine to exit
+func (s *SLOSummary) Stop() {
+	s.once.Do(func() { close(s.stop) })
+	s.bg.Wait()
+}
@@ -0,0 +1,160 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+// sloCount returns the current count of the named SLO
+func sloCount(t *testing.T, name string) int64 {
+	t.Helper()
+	for _, count := range logger.SLOCounts() {
+		if count.Name == name {
+			return count.Count
+		}
+	}
+	t.Fatalf("SLO %q is not registered", name)
+	return 0
+}
+
+func TestSLOMatcherComposition(t *testing.T) {
+	checkout := logger.Field{Key: "domain", Value: "checkout"}
+	search := logger.Field{Key: "domain", Value: "search"}
+	status := logger.Field{Key: "status", Value: 503}
+
+	tests := []struct {
+		name     string
+		matchers []logger.SLOMatcher
+		level    logger.Level
+		fields   []logger.Field
+		counted  bool
+	}{
+		{name: "level matches", matchers: []logger.SLOMatcher{logger.MatchLevel(logger.ErrorLevel)},
+			level: logger.ErrorLevel, counted: true},
+		{name: "level below", matchers: []logger.SLOMatcher{logger.MatchLevel(logger.ErrorLevel)},
+			level: logger.WarnLevel},
+		{name: "all must match", matchers: []logger.SLOMatcher{logger.MatchLevel(logger.ErrorLevel), logger.MatchField("domain", "checkout")},
+			level: logger.ErrorLevel, fields: []logger.Field{search}},
+		{name: "all match", matchers: []logger.SLOMatcher{logger.MatchLevel(logger.ErrorLevel), logger.MatchField("domain", "checkout")},
+			level: logger.ErrorLevel, fields: []logger.Field{checkout}, counted: true},
+		{name: "any matches", matchers: []logger.SLOMatcher{logger.MatchAny(logger.MatchField("status", 500), logger.MatchField("status", 503))},
+			level: logger.InfoLevel, fields: []logger.Field{status}, counted: true},
+		{name: "none of any", matchers: []logger.SLOMatcher{logger.MatchAny(logger.MatchField("status", 500), logger.MatchField("status", "503"))},
+			level: logger.InfoLevel, fields: []logger.Field{status}},
+		{name: "nested", matchers: []logger.SLOMatcher{logger.MatchAny(
+			logger.MatchAll(logger.MatchLevel(logger.ErrorLevel), logger.MatchField("domain", "checkout")),
+			logger.MatchField("status", 503),
+		)}, level: logger.WarnLevel, fields: []logger.Field{search, status}, counted: true},
+		{name: "uncomparable values", matchers: []logger.SLOMatcher{logger.MatchField("tags", []string{"a"})},
+			level: logger.InfoLevel, fields: []logger.Field{{Key: "tags", Value: []string{"a"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := "composition_" + strings.ReplaceAll(tt.name, " ", "_")
+			if err := logger.RegisterSLO(name, tt.matchers...); err != nil {
+				t.Fatalf("Failed to register SLO: %v", err)
+			}
+			log := logger.New(logger.Config{Output: &bytes.Buffer{}})
+
+			switch tt.level {
+			case logger.InfoLevel:
+				log.Info("entry", tt.fields...)
+			case logger.WarnLevel:
+				log.Warn("entry", tt.fields...)
+			case logger.ErrorLevel:
+				log.Error("entry", tt.fields...)
+			}
+
+			want := int64(0)
+			if tt.counted {
+				want = 1
+			}
+			if got := sloCount(t, name); got != want {
+				t.Errorf("Expected count %d, got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestSLOCountsUnderConcurrency(t *testing.T) {
+	if err := logger.RegisterSLO("concurrent_checkout_errors",
+		logger.MatchLevel(logger.ErrorLevel), logger.MatchField("domain", "checkout")); err != nil {
+		t.Fatalf("Failed to register SLO: %v", err)
+	}
+
+	var console, file bytes.Buffer
+	log := logger.MultiLogger(
+		logger.New(logger.Config{Output: &console}),
+		logger.New(logger.Config{Output: &file}),
+	).With(logger.Field{Key: "domain", Value: "checkout"})
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				log.Error("payment failed")
+				log.Info("payment retried")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := sloCount(t, "concurrent_checkout_errors"); got != 800 {
+		t.Errorf("Expected 800 errors counted once each, got %d", got)
+	}
+}
+
+func TestRegisterSLOTwice(t *testing.T) {
+	if err := logger.RegisterSLO("registered_twice", logger.MatchLevel(logger.ErrorLevel)); err != nil {
+		t.Fatalf("Failed to register SLO: %v", err)
+	}
+	if err := logger.RegisterSLO("registered_twice", logger.MatchLevel(logger.ErrorLevel)); err == nil {
+		t.Error("Expected registering a name twice to fail")
+	}
+}
+
+func TestWriteSLOMetrics(t *testing.T) {
+	if err := logger.RegisterSLO("metrics_fatal", logger.MatchLevel(logger.FatalLevel)); err != nil {
+		t.Fatalf("Failed to register SLO: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := logger.WriteSLOMetrics(&buf); err != nil {
+		t.Fatalf("Failed to write metrics: %v", err)
+	}
+	if !strings.Contains(buf.String(), "# TYPE log_slo_events_total counter\n") ||
+		!strings.Contains(buf.String(), `log_slo_events_total{slo="metrics_fatal"} 0`) {
+		t.Errorf("Expected a counter series per SLO, got: %s", buf.String())
+	}
+}
+
+func TestSLOSummary(t *testing.T) {
+	if err := logger.RegisterSLO("summary_errors", logger.MatchLevel(logger.ErrorLevel), logger.MatchField("summary", true)); err != nil {
+		t.Fatalf("Failed to register SLO: %v", err)
+	}
+	var buf syncBuffer
+	log := logger.New(logger.Config{Output: &buf})
+	log.Error("counted", logger.Field{Key: "summary", Value: true})
+
+	summary := logger.StartSLOSummary(log, 10*time.Millisecond)
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(buf.String(), "slo summary") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	summary.Stop()
+
+	if !strings.Contains(buf.String(), "slo.summary_errors=1") {
+		t.Errorf("Expected a summary entry with the counts, got: %s", buf.String())
+	}
+	if logger.RunningBackgroundTasks() != 0 {
+		t.Errorf("Expected Stop to join the summary goroutine")
+	}
+}
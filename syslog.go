@@ -0,0 +1,177 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSyslogSDID is the SD-ID of the structured data element carrying
+// fields in FormatSyslog. 32473 is the enterprise number reserved for
+// documentation; set Config.SyslogSDID to one under your own number.
+const DefaultSyslogSDID = "fields@32473"
+
+// syslogUserFacility is the facility used when none is configured
+const syslogUserFacility = 1
+
+// syslogTimeFormat is an RFC 5424 TIMESTAMP, which allows at most six
+// fractional digits
+const syslogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+// syslogHeader holds the per-logger parts of an RFC 5424 header
+type syslogHeader struct {
+	facility int
+	appName  string
+	procID   string
+	sdID     string
+}
+
+// newSyslogHeader resolves the syslog configuration, applying defaults
+func newSyslogHeader(cfg Config) syslogHeader {
+	h := syslogHeader{
+		facility: cfg.SyslogFacility,
+		appName:  cfg.SyslogAppName,
+		procID:   strconv.Itoa(os.Getpid()),
+		sdID:     cfg.SyslogSDID,
+	}
+	if h.facility <= 0 || h.facility > 23 {
+		h.facility = syslogUserFacility
+	}
+	if h.appName == "" {
+		h.appName = filepath.Base(os.Args[0])
+	}
+	if h.sdID == "" {
+		h.sdID = DefaultSyslogSDID
+	}
+	return h
+}
+
+// encodeSyslog renders an entry as an RFC 5424 message. The severity is
+// the syslog severity of the entry's level and the MSGID is the entry's
+// code, if any. Fields are written as SD-PARAMs of one SD-ELEMENT with
+// dotted group keys; the message follows the structured data.
+func (l *standardLogger) encodeSyslog(now time.Time, level Level, msg string, fields []Field) string {
+	var b bytes.Buffer
+	var failures []string
+
+	severity, ok := SyslogSeverities[level]
+	if !ok {
+		severity = SyslogSeverities[InfoLevel]
+	}
+	msgID := "-"
+	for _, field := range fields {
+		if code, ok := field.Value.(string); ok && field.Key == "code" {
+			msgID = syslogHeaderValue(code, 32)
+		}
+	}
+
+	b.WriteString("<")
+	b.WriteString(strconv.Itoa(l.syslog.facility*8 + severity))
+	b.WriteString(">1 ")
+	b.WriteString(now.Format(syslogTimeFormat))
+	b.WriteString(" ")
+	b.WriteString(syslogHeaderValue(l.host, 255))
+	b.WriteString(" ")
+	b.WriteString(syslogHeaderValue(l.syslog.appName, 48))
+	b.WriteString(" ")
+	b.WriteString(syslogHeaderValue(l.syslog.procID, 128))
+	b.WriteString(" ")
+	b.WriteString(msgID)
+	b.WriteString(" ")
+
+	var sd bytes.Buffer
+	writeSyslogParams(&sd, "", fields, &failures)
+	if len(failures) > 0 {
+		writeSyslogParam(&sd, internalErrorKey, strings.Join(failures, "; "))
+		l.formatFailed(failures)
+	}
+	if sd.Len() == 0 {
+		b.WriteString("-")
+	} else {
+		b.WriteString("[")
+		b.WriteString(syslogName(l.syslog.sdID))
+		b.Write(sd.Bytes())
+		b.WriteString("]")
+	}
+
+	if msg != "" {
+		b.WriteString(" ")
+		b.WriteString(msg)
+	}
+	return b.String()
+}
+
+// writeSyslogParams writes fields as SD-PARAMs
+func writeSyslogParams(b *bytes.Buffer, prefix string, fields []Field, failures *[]string) {
+	for _, field := range fields {
+		key := prefix + field.Key
+		if group, ok := field.Value.(groupValue); ok {
+			writeSyslogParams(b, key+".", group, failures)
+			continue
+		}
+		value, err := formatValue(field.Value)
+		if err != nil {
+			*failures = append(*failures, fmt.Sprintf("%s: %v", key, err))
+		}
+		writeSyslogParam(b, key, value)
+	}
+}
+
+// writeSyslogParam writes one SD-PARAM, escaping '"', '\' and ']' in the
+// value as RFC 5424 requires
+func writeSyslogParam(b *bytes.Buffer, name, value string) {
+	b.WriteString(" ")
+	b.WriteString(syslogName(name))
+	b.WriteString(`="`)
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '"', '\\', ']':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteString(`"`)
+}
+
+// syslogName makes s a valid SD-NAME: at most 32 printable ASCII
+// characters other than '=', ' ', ']' and '"', which are replaced by
+// underscores
+func syslogName(s string) string {
+	name := []byte(s)
+	for i, c := range name {
+		if c < 33 || c > 126 || c == '=' || c == ']' || c == '"' {
+			name[i] = '_'
+		}
+	}
+	if len(name) > 32 {
+		name = name[:32]
+	}
+	if len(name) == 0 {
+		return "_"
+	}
+	return string(name)
+}
+
+// syslogHeaderValue makes s a valid header field of at most max printable
+// ASCII characters, or the nil value "-" when it is empty
+func syslogHeaderValue(s string, max int) string {
+	if s == "" {
+		return "-"
+	}
+	value := []byte(s)
+	for i, c := range value {
+		if c < 33 || c > 126 {
+			value[i] = '_'
+		}
+	}
+	if len(value) > max {
+		value = value[:max]
+	}
+	return string(value)
+}
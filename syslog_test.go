@@ -0,0 +1,79 @@
+package logger_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestSyslogFormat(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2024, 3, 1, 12, 0, 0, 250*int(time.Millisecond), time.UTC)
+	log := logger.New(logger.Config{
+		Output:         &buf,
+		Format:         logger.FormatSyslog,
+		Host:           "api-1",
+		SyslogAppName:  "billing",
+		SyslogFacility: 16,
+		Clock:          fixedClock(now),
+	})
+
+	log.Error("payment failed",
+		logger.Field{Key: "attempt", Value: 3},
+		logger.Group("http", logger.Field{Key: "status", Value: 402}),
+	)
+
+	expected := fmt.Sprintf(`<131>1 2024-03-01T12:00:00.250000Z api-1 billing %d - [fields@32473 attempt="3" http.status="402"] payment failed`+"\n", os.Getpid())
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestSyslogEscaping(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatSyslog, SyslogSDID: "app@12345"})
+
+	log.Info("quoted", logger.Field{Key: "bad key=\"]", Value: `a "b" \c] d`})
+
+	if !strings.Contains(buf.String(), `[app@12345 bad_key___="a \"b\" \\c\] d"]`) {
+		t.Errorf("Expected names sanitized and values escaped, got %q", buf.String())
+	}
+}
+
+func TestSyslogDefaults(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatSyslog, Host: "h"})
+
+	log.Warn("no fields")
+	log.(logger.CodeLogger).WarnCode("E_CARD", "with code")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if !strings.HasPrefix(lines[0], "<12>1 ") || !strings.HasSuffix(lines[0], " - - no fields") {
+		t.Errorf("Expected user facility, no MSGID and no structured data, got %q", lines[0])
+	}
+	if !strings.Contains(lines[1], " E_CARD [fields@32473 code=\"E_CARD\"") {
+		t.Errorf("Expected the code as MSGID, got %q", lines[1])
+	}
+}
+
+func TestSyslogSeverities(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatSyslog, Level: logger.DebugLevel})
+
+	log.Debug("d")
+	log.Info("i")
+	log.Warn("w")
+	log.Error("e")
+
+	for i, prefix := range []string{"<15>", "<14>", "<12>", "<11>"} {
+		line := strings.Split(buf.String(), "\n")[i]
+		if !strings.HasPrefix(line, prefix) {
+			t.Errorf("Expected line %d to start with %s, got %q", i, prefix, line)
+		}
+	}
+}
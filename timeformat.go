@@ -0,0 +1,188 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Named time format presets for Config.TimeFormat. Any other value is
+// used as a time.Format layout.
+const (
+	// TimeFormatRFC3339 is the default, in the local time zone
+	TimeFormatRFC3339 = time.RFC3339
+	// TimeFormatRFC3339Nano adds nanoseconds to RFC 3339, so entries
+	// written in the same second keep their order
+	TimeFormatRFC3339Nano = time.RFC3339Nano
+	// TimeFormatRFC3339UTC converts timestamps to UTC, rendered with the
+	// Z designator
+	TimeFormatRFC3339UTC = "RFC3339UTC"
+	// TimeFormatUnixMillis renders milliseconds since the Unix epoch as
+	// an integer. Text output writes the digits without quoting.
+	TimeFormatUnixMillis = "UnixMillis"
+	// TimeFormatUnixNanos renders nanoseconds since the Unix epoch as an
+	// integer
+	TimeFormatUnixNanos = "UnixNanos"
+	// TimeFormatISOOrdinal renders ISO 8601 ordinal dates in UTC, such as
+	// 2024-061T12:00:00Z
+	TimeFormatISOOrdinal = "ISOOrdinal"
+)
+
+// TimeEncoding selects whether entry timestamps are strings or numbers
+type TimeEncoding int
+
+const (
+	// TimeEncodingRFC3339 writes timestamps as strings formatted with
+	// Config.TimeFormat. It is the default.
+	TimeEncodingRFC3339 TimeEncoding = iota
+	// TimeEncodingUnixMillis writes milliseconds since the Unix epoch,
+	// as a JSON number in JSON output
+	TimeEncodingUnixMillis
+	// TimeEncodingUnixNanos writes nanoseconds since the Unix epoch, as a
+	// JSON number in JSON output
+	TimeEncodingUnixNanos
+)
+
+// String returns the name of the encoding
+func (e TimeEncoding) String() string {
+	switch e {
+	case TimeEncodingRFC3339:
+		return "rfc3339"
+	case TimeEncodingUnixMillis:
+		return "unix_millis"
+	case TimeEncodingUnixNanos:
+		return "unix_nanos"
+	default:
+		return "unknown"
+	}
+}
+
+// timeFormatNames maps the names accepted by ParseTimeFormat to formats
+var timeFormatNames = map[string]string{
+	"rfc3339":     TimeFormatRFC3339,
+	"rfc3339nano": TimeFormatRFC3339Nano,
+	"rfc3339utc":  TimeFormatRFC3339UTC,
+	"unixmillis":  TimeFormatUnixMillis,
+	"unix_millis": TimeFormatUnixMillis,
+	"unixnanos":   TimeFormatUnixNanos,
+	"unix_nanos":  TimeFormatUnixNanos,
+	"isoordinal":  TimeFormatISOOrdinal,
+	"iso_ordinal": TimeFormatISOOrdinal,
+}
+
+// ParseTimeFormat resolves a time format given as a string, for example
+// from an environment variable or configuration file. Preset names are
+// matched case insensitively; anything containing the reference year 2006
+// is accepted as a layout.
+func ParseTimeFormat(name string) (string, error) {
+	if format, ok := timeFormatNames[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return format, nil
+	}
+	if strings.Contains(name, "2006") {
+		return name, nil
+	}
+	return "", fmt.Errorf("unknown time format %q", name)
+}
+
+// formatTime renders t according to a preset or layout
+func formatTime(t time.Time, format string) string {
+	switch format {
+	case TimeFormatRFC3339UTC:
+		return t.UTC().Format(time.RFC3339)
+	case TimeFormatUnixMillis:
+		return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+	case TimeFormatUnixNanos:
+		return strconv.FormatInt(t.UnixNano(), 10)
+	case TimeFormatISOOrdinal:
+		t = t.UTC()
+		return fmt.Sprintf("%04d-%03d%s", t.Year(), t.YearDay(), t.Format("T15:04:05Z07:00"))
+	default:
+		return t.Format(format)
+	}
+}
+
+// isEpochFormat reports whether format renders timestamps as integers,
+// which JSON output writes as numbers
+func isEpochFormat(format string) bool {
+	return format == TimeFormatUnixMillis || format == TimeFormatUnixNanos
+}
+
+// subsecondFormat reports whether format renders fractions of a second,
+// as TimeFormatRFC3339Nano, the epoch presets and layouts with fractional
+// seconds such as "15:04:05.000" do
+func subsecondFormat(format string) bool {
+	switch format {
+	case TimeFormatUnixMillis, TimeFormatUnixNanos:
+		return true
+	case TimeFormatRFC3339UTC, TimeFormatISOOrdinal:
+		return false
+	}
+	return strings.Contains(format, "05.0") || strings.Contains(format, "05.9") ||
+		strings.Contains(format, "05,0") || strings.Contains(format, "05,9")
+}
+
+// timeFormatProbes are the components CheckTimeFormat requires a format
+// to keep, each probed by moving a reference time by one unit of it
+var timeFormatProbes = []struct {
+	name string
+	move func(time.Time) time.Time
+}{
+	{"year", func(t time.Time) time.Time { return t.AddDate(1, 0, 0) }},
+	{"month", func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }},
+	{"day", func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }},
+	{"hour", func(t time.Time) time.Time { return t.Add(time.Hour) }},
+	{"minute", func(t time.Time) time.Time { return t.Add(time.Minute) }},
+}
+
+// CheckTimeFormat reports what a time format, a preset or layout, loses:
+// date or time components, the time zone offset, without which local
+// times repeat when daylight saving time ends, or precision finer than
+// precision, which defaults to one second. It works by formatting
+// reference times that differ only in one respect and comparing the
+// results, so it judges what the format renders rather than how the
+// layout is spelled.
+func CheckTimeFormat(format string, precision time.Duration) error {
+	if precision <= 0 {
+		precision = time.Second
+	}
+	// Aligned to every unit, so moving it by less than the format's
+	// resolution cannot cross into the next rendered value
+	ref := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	rendered := formatTime(ref, format)
+
+	var lost []string
+	for _, probe := range timeFormatProbes {
+		if formatTime(probe.move(ref), format) == rendered {
+			lost = append(lost, "the "+probe.name)
+		}
+	}
+	// The same wall clock time one hour apart, as on either side of a
+	// daylight saving change
+	shifted := ref.Add(-time.Hour).In(time.FixedZone("UTC+1", 60*60))
+	if formatTime(shifted, format) == rendered {
+		lost = append(lost, "the time zone offset")
+	}
+	if formatTime(ref.Add(precision), format) == rendered {
+		lost = append(lost, "precision of "+precision.String())
+	}
+
+	if len(lost) == 0 {
+		return nil
+	}
+	list := lost[0]
+	if len(lost) > 1 {
+		list = strings.Join(lost[:len(lost)-1], ", ") + " and " + lost[len(lost)-1]
+	}
+	return fmt.Errorf("time format %q loses %s", format, list)
+}
+
+// usesTimeFormat reports whether cfg's timestamps are rendered with
+// TimeFormat, which GELF, ECS, syslog and console output do not
+func (cfg Config) usesTimeFormat() bool {
+	switch cfg.Format {
+	case FormatText, FormatJSON, FormatLogfmt:
+		return cfg.TimeFormat != "" && cfg.TimeEncoding == TimeEncodingRFC3339
+	}
+	return false
+}
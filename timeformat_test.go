@@ -0,0 +1,216 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestTimeFormatPresets(t *testing.T) {
+	zone := time.FixedZone("UTC+2", 2*60*60)
+	now := time.Date(2024, 3, 1, 14, 30, 5, 123456789, zone)
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{format: logger.TimeFormatRFC3339, expected: "2024-03-01T14:30:05+02:00"},
+		{format: logger.TimeFormatRFC3339Nano, expected: "2024-03-01T14:30:05.123456789+02:00"},
+		{format: logger.TimeFormatRFC3339UTC, expected: "2024-03-01T12:30:05Z"},
+		{format: logger.TimeFormatUnixMillis, expected: "1709296205123"},
+		{format: logger.TimeFormatUnixNanos, expected: "1709296205123456789"},
+		{format: logger.TimeFormatISOOrdinal, expected: "2024-061T12:30:05Z"},
+		{format: "2006/01/02", expected: "2024/03/01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := logger.New(logger.Config{Output: &buf, TimeFormat: tt.format, Clock: fixedClock(now)})
+
+			log.Info("preset")
+
+			if !strings.Contains(buf.String(), tt.expected+" [INFO] preset") {
+				t.Errorf("Expected timestamp %s, got: %s", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestNanosecondTimestampsOrderEntries(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, TimeFormat: logger.TimeFormatRFC3339Nano})
+
+	log.Info("first")
+	log.Info("second")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected two entries, got: %s", buf.String())
+	}
+	first, _, _ := strings.Cut(lines[0], " ")
+	second, _, _ := strings.Cut(lines[1], " ")
+	if _, err := time.Parse(time.RFC3339Nano, first); err != nil {
+		t.Errorf("Expected entries to start with the precise timestamp, got: %s", lines[0])
+	}
+	if first == second {
+		t.Errorf("Expected back-to-back entries to have distinct timestamps, got %s twice", first)
+	}
+}
+
+func TestTimestampLocation(t *testing.T) {
+	now := time.Date(2024, 3, 1, 14, 30, 5, 0, time.FixedZone("UTC+2", 2*60*60))
+	tokyo := time.FixedZone("JST", 9*60*60)
+
+	tests := []struct {
+		name     string
+		cfg      logger.Config
+		expected string
+	}{
+		{name: "clock zone by default", expected: "2024-03-01T14:30:05+02:00"},
+		{name: "utc", cfg: logger.Config{UTC: true}, expected: "2024-03-01T12:30:05Z"},
+		{name: "location", cfg: logger.Config{Location: tokyo}, expected: "2024-03-01T21:30:05+09:00"},
+		{name: "location over utc", cfg: logger.Config{UTC: true, Location: tokyo}, expected: "2024-03-01T21:30:05+09:00"},
+		{name: "json", cfg: logger.Config{UTC: true, Format: logger.FormatJSON}, expected: `"2024-03-01T12:30:05Z"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			cfg := tt.cfg
+			cfg.Output = &buf
+			cfg.Clock = fixedClock(now)
+			log := logger.New(cfg)
+
+			log.Info("zoned")
+
+			if !strings.Contains(buf.String(), tt.expected) {
+				t.Errorf("Expected timestamp %s, got: %s", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestTimeEncoding(t *testing.T) {
+	now := time.Date(2024, 3, 1, 14, 30, 5, 123456789, time.UTC)
+
+	tests := []struct {
+		name       string
+		encoding   logger.TimeEncoding
+		format     logger.Format
+		timeFormat string
+		expected   string
+	}{
+		{name: "rfc3339 json", format: logger.FormatJSON, timeFormat: "2006-01-02", expected: `{"time":"2024-03-01",`},
+		{name: "millis json", encoding: logger.TimeEncodingUnixMillis, format: logger.FormatJSON, timeFormat: "2006-01-02", expected: `{"time":1709303405123,`},
+		{name: "nanos json", encoding: logger.TimeEncodingUnixNanos, format: logger.FormatJSON, expected: `{"time":1709303405123456789,`},
+		{name: "millis logfmt", encoding: logger.TimeEncodingUnixMillis, format: logger.FormatLogfmt, expected: "ts=1709303405123 "},
+		{name: "nanos text", encoding: logger.TimeEncodingUnixNanos, expected: "1709303405123456789 [INFO] encoded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := logger.New(logger.Config{
+				Output:       &buf,
+				Format:       tt.format,
+				TimeFormat:   tt.timeFormat,
+				TimeEncoding: tt.encoding,
+				Clock:        fixedClock(now),
+			})
+
+			log.Info("encoded")
+
+			if !strings.Contains(buf.String(), tt.expected) {
+				t.Errorf("Expected %s, got: %s", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestCheckTimeFormat(t *testing.T) {
+	tests := []struct {
+		format    string
+		precision time.Duration
+		lost      string
+	}{
+		{format: logger.TimeFormatRFC3339},
+		{format: logger.TimeFormatRFC3339UTC},
+		{format: logger.TimeFormatUnixMillis, precision: time.Millisecond},
+		{format: logger.TimeFormatISOOrdinal},
+		{format: "2006-01-02 15:04:05 MST"},
+		{format: "2006-01-02T15:04:05.000Z07:00", precision: time.Millisecond},
+		{format: logger.TimeFormatRFC3339Nano, precision: time.Microsecond},
+		{format: "15:04:05", lost: "loses the year, the month, the day and the time zone offset"},
+		{format: "2006-01-02 15:04:05", lost: "loses the time zone offset"},
+		{format: "Jan _2 15:04:05 Z07:00", lost: "loses the year"},
+		{format: "2006-01-02", lost: "loses the hour, the minute, the time zone offset and precision of 1s"},
+		{format: logger.TimeFormatRFC3339, precision: time.Millisecond, lost: "loses precision of 1ms"},
+		{format: "2006-01-02T15:04:05.000Z07:00", precision: time.Microsecond, lost: "loses precision of 1µs"},
+	}
+
+	for _, tt := range tests {
+		err := logger.CheckTimeFormat(tt.format, tt.precision)
+		if tt.lost == "" {
+			if err != nil {
+				t.Errorf("CheckTimeFormat(%q, %v): unexpected error %v", tt.format, tt.precision, err)
+			}
+			continue
+		}
+		if err == nil || !strings.HasSuffix(err.Error(), tt.lost) {
+			t.Errorf("CheckTimeFormat(%q, %v) = %v; expected it to end with %q", tt.format, tt.precision, err, tt.lost)
+		}
+	}
+}
+
+func TestLossyTimeFormatWarning(t *testing.T) {
+	var buf bytes.Buffer
+	logger.New(logger.Config{Output: &buf, Level: logger.ErrorLevel, TimeFormat: "15:04:05"})
+
+	expected := `[WARN] time format loses information {time_format=15:04:05 error="time format \"15:04:05\" loses the year`
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("Expected a warning entry %q, got: %s", expected, buf.String())
+	}
+
+	buf.Reset()
+	logger.New(logger.Config{Output: &buf, Format: logger.FormatConsole, TimeFormat: "15:04:05"})
+	if buf.Len() != 0 {
+		t.Errorf("Expected no warning for a format that ignores TimeFormat, got: %s", buf.String())
+	}
+
+	if _, err := logger.NewWithError(logger.Config{Output: &buf, Strict: true, TimeFormat: "2006-01-02 15:04:05"}); err == nil {
+		t.Error("Expected a lossy time format to be an error in strict mode")
+	}
+}
+
+func TestParseTimeFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		wantErr  bool
+	}{
+		{name: "RFC3339UTC", expected: logger.TimeFormatRFC3339UTC},
+		{name: "unix_millis", expected: logger.TimeFormatUnixMillis},
+		{name: "UnixNanos", expected: logger.TimeFormatUnixNanos},
+		{name: " IsoOrdinal ", expected: logger.TimeFormatISOOrdinal},
+		{name: "rfc3339nano", expected: time.RFC3339Nano},
+		{name: "2006-01-02 15:04", expected: "2006-01-02 15:04"},
+		{name: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		format, err := logger.ParseTimeFormat(tt.name)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Expected error for %q", tt.name)
+			}
+			continue
+		}
+		if err != nil || format != tt.expected {
+			t.Errorf("ParseTimeFormat(%q) = %q, %v; expected %q", tt.name, format, err, tt.expected)
+		}
+	}
+}
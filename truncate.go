@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"encoding"
+	"encoding/base64"
+	"fmt"
+)
+
+// truncateFields shortens values longer than l.maxFieldLen in place.
+// fields was built for the entry; groups are shared with the loggers that
+// hold them and are copied before they are changed.
+func (l *standardLogger) truncateFields(fields []Field) {
+	for i, field := range fields {
+		if group, ok := field.Value.(groupValue); ok {
+			if l.needsTruncation(group) {
+				copied := append(groupValue(nil), group...)
+				l.truncateFields(copied)
+				fields[i].Value = copied
+			}
+			continue
+		}
+		if value, ok := truncateValue(field.Value, l.maxFieldLen); ok {
+			fields[i].Value = value
+		}
+	}
+}
+
+// needsTruncation reports whether any value in fields, or in their
+// groups, is over the limit
+func (l *standardLogger) needsTruncation(fields []Field) bool {
+	for _, field := range fields {
+		if group, ok := field.Value.(groupValue); ok {
+			if l.needsTruncation(group) {
+				return true
+			}
+			continue
+		}
+		if _, ok := truncateValue(field.Value, l.maxFieldLen); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// truncateValue returns value cut to max bytes followed by a marker
+// giving the size removed, such as ...(truncated 39.0MiB). Strings and
+// values written as text, such as errors and fmt.Stringer values, count
+// their text; []byte values count their base64 encoding and are written
+// base64 encoded when truncated. It reports false for values within the
+// limit and values of other types.
+func truncateValue(value any, max int) (any, bool) {
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case []byte:
+		if base64.StdEncoding.EncodedLen(len(v)) <= max {
+			return nil, false
+		}
+		kept := base64.StdEncoding.DecodedLen(max)
+		return base64.StdEncoding.EncodeToString(v[:kept]) + truncationMarker(len(v)-kept), true
+	case stackTrace:
+		// Bounded by the frames captured
+		return nil, false
+	case error, fmt.Stringer, encoding.TextMarshaler:
+		text, err := formatValue(v)
+		if err != nil {
+			return nil, false
+		}
+		s = text
+	default:
+		return nil, false
+	}
+	if len(s) <= max {
+		return nil, false
+	}
+	kept := truncateString(s, max)
+	return kept + truncationMarker(len(s)-len(kept)), true
+}
+
+// truncationMarker follows a truncated value
+func truncationMarker(removed int) string {
+	return "...(truncated " + humanBytes(int64(removed)) + ")"
+}
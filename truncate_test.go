@@ -0,0 +1,76 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestMaxFieldLength(t *testing.T) {
+	body := strings.Repeat("x", 40<<20)
+
+	tests := []struct {
+		name     string
+		field    logger.Field
+		expected string
+	}{
+		{name: "short string", field: logger.Field{Key: "v", Value: "short"}, expected: "{v=short}"},
+		{name: "long string", field: logger.Field{Key: "body", Value: body}, expected: `{body="xxxxxxxx...(truncated 40.0MiB)"}`},
+		{name: "runes kept whole", field: logger.Field{Key: "v", Value: "ééééé"}, expected: `{v="éééé...(truncated 2B)"}`},
+		{name: "error", field: logger.Field{Key: "error", Value: errors.New("connection refused by peer")}, expected: `{error="connecti...(truncated 18B)"}`},
+		{name: "bytes", field: logger.Field{Key: "raw", Value: []byte("0123456789")}, expected: `{raw="MDEyMzQ1...(truncated 4B)"}`},
+		{name: "numbers untouched", field: logger.Field{Key: "n", Value: 1234567890123}, expected: "{n=1234567890123}"},
+		{name: "groups", field: logger.Group("http", logger.Field{Key: "body", Value: body}), expected: `{http.body="xxxxxxxx...(truncated 40.0MiB)"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := logger.New(logger.Config{Output: &buf, MaxFieldLength: 8})
+
+			log.Info("truncate", tt.field)
+
+			if !strings.Contains(buf.String(), "truncate "+tt.expected) {
+				t.Errorf("Expected %s, got: %.200s", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestMaxFieldLengthBoundsJSON(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf, Format: logger.FormatJSON, MaxFieldLength: 1024})
+
+	log.Info("response", logger.Field{Key: "body", Value: strings.Repeat("\x00", 1<<20)}, logger.Field{Key: "raw", Value: make([]byte, 1<<20)})
+
+	if buf.Len() > 16<<10 {
+		t.Errorf("Expected a bounded entry, got %d bytes", buf.Len())
+	}
+	entry := decodeEntry(t, buf.String())
+	raw, _ := entry["raw"].(string)
+	encoded, _, _ := strings.Cut(raw, "...")
+	if len(encoded) > 1024 {
+		t.Errorf("Expected at most 1024 base64 bytes, got %d", len(encoded))
+	}
+	if _, err := base64.StdEncoding.DecodeString(encoded); err != nil {
+		t.Errorf("Expected the kept bytes to stay valid base64: %v", err)
+	}
+}
+
+func TestMaxFieldLengthLeavesBaseFieldsIntact(t *testing.T) {
+	var buf bytes.Buffer
+	group := logger.Group("req", logger.Field{Key: "body", Value: "0123456789"})
+	base := logger.New(logger.Config{Output: &buf}).With(group)
+	limited := logger.New(logger.Config{Output: &buf, MaxFieldLength: 4}).With(group)
+
+	limited.Info("limited")
+	base.Info("unlimited")
+
+	if !strings.Contains(buf.String(), "unlimited {req.body=0123456789}") {
+		t.Errorf("Expected the shared group to be copied before truncation, got: %s", buf.String())
+	}
+}
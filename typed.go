@@ -0,0 +1,42 @@
+package logger
+
+import "time"
+
+// String returns a field holding a string. It is the same as
+// Field{Key: key, Value: v}; the typed constructors only save the
+// literal and let the compiler check the value's type.
+func String(key, v string) Field {
+	return Field{Key: key, Value: v}
+}
+
+// Int returns a field holding an int
+func Int(key string, v int) Field {
+	return Field{Key: key, Value: v}
+}
+
+// Int64 returns a field holding an int64
+func Int64(key string, v int64) Field {
+	return Field{Key: key, Value: v}
+}
+
+// Float64 returns a field holding a float64
+func Float64(key string, v float64) Field {
+	return Field{Key: key, Value: v}
+}
+
+// Bool returns a field holding a bool
+func Bool(key string, v bool) Field {
+	return Field{Key: key, Value: v}
+}
+
+// Time returns a field holding a time. It is rendered like a time.Time
+// in a literal field, not with Config.TimeFormat.
+func Time(key string, v time.Time) Field {
+	return Field{Key: key, Value: v}
+}
+
+// Any returns a field holding a value of any type, rendered the same way
+// as in a literal field
+func Any(key string, v any) Field {
+	return Field{Key: key, Value: v}
+}
@@ -0,0 +1,58 @@
+package logger_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func TestTypedFieldsMatchLiterals(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	typed := []logger.Field{
+		logger.String("s", "v"),
+		logger.Int("i", 3),
+		logger.Int64("i64", 1<<40),
+		logger.Float64("f", 0.25),
+		logger.Bool("b", true),
+		logger.Time("t", at),
+		logger.Any("a", []string{"x"}),
+	}
+	literal := []logger.Field{
+		{Key: "s", Value: "v"},
+		{Key: "i", Value: 3},
+		{Key: "i64", Value: int64(1 << 40)},
+		{Key: "f", Value: 0.25},
+		{Key: "b", Value: true},
+		{Key: "t", Value: at},
+		{Key: "a", Value: []string{"x"}},
+	}
+	if !reflect.DeepEqual(typed, literal) {
+		t.Fatalf("Expected typed fields to equal literals\nexpected: %v\ngot:      %v", literal, typed)
+	}
+
+	for _, format := range []logger.Format{logger.FormatText, logger.FormatJSON} {
+		var fromTyped, fromLiteral bytes.Buffer
+		cfg := logger.Config{Format: format, Clock: fixedClock(at), TimeFormat: logger.TimeFormatRFC3339Nano}
+		cfg.Output = &fromTyped
+		logger.New(cfg).Info("entry", typed...)
+		cfg.Output = &fromLiteral
+		logger.New(cfg).Info("entry", literal...)
+		if fromTyped.String() != fromLiteral.String() {
+			t.Errorf("Expected the same output in format %v\nexpected: %s\ngot:      %s", format, fromLiteral.String(), fromTyped.String())
+		}
+	}
+}
+
+func TestTypedFieldsText(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+
+	log.Info("entry", logger.String("s", "two words"), logger.Int("i", -7), logger.Float64("f", 1e21), logger.Bool("b", false))
+	if !strings.Contains(buf.String(), `{s="two words" i=-7 f=1e+21 b=false}`) {
+		t.Errorf("Expected the typed values rendered as before, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,220 @@
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// maxValueDepth bounds how deep composite values are walked when looking
+// for values that cannot be rendered
+const maxValueDepth = 32
+
+// visit identifies a pointer, map or slice on the path currently being
+// walked, so values that contain themselves can be detected
+type visit struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+// formatUnloggable renders values that fmt would print as a bare address
+// or recurse into forever. Channels, funcs and unsafe pointers become a
+// typed placeholder such as <chan int>, values reached again while walking
+// themselves become <cycle>, and nesting beyond maxValueDepth becomes
+// <max depth>. It reports false when value needs none of this.
+func formatUnloggable(value any) (string, bool) {
+	rv := reflect.ValueOf(value)
+	if !needsWalk(rv.Kind()) || !containsUnloggable(rv, 0, map[visit]bool{}) {
+		return "", false
+	}
+
+	var b strings.Builder
+	renderValue(&b, rv, 0, map[visit]bool{})
+	return b.String(), true
+}
+
+// isUnloggable reports whether formatUnloggable would replace any part of
+// value with a placeholder
+func isUnloggable(value any) bool {
+	rv := reflect.ValueOf(value)
+	return needsWalk(rv.Kind()) && containsUnloggable(rv, 0, map[visit]bool{})
+}
+
+// needsWalk reports whether values of kind k can hold unloggable values
+func needsWalk(k reflect.Kind) bool {
+	switch k {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Ptr,
+		reflect.Map, reflect.Slice, reflect.Array, reflect.Struct, reflect.Interface:
+		return true
+	}
+	return false
+}
+
+// enter marks a reference value as being walked. It reports false when the
+// value is already on the current path.
+func enter(v reflect.Value, visiting map[visit]bool) (visit, bool) {
+	key := visit{addr: v.Pointer(), typ: v.Type()}
+	if visiting[key] {
+		return key, false
+	}
+	visiting[key] = true
+	return key, true
+}
+
+func containsUnloggable(v reflect.Value, depth int, visiting map[visit]bool) bool {
+	if depth > maxValueDepth {
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return true
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return false
+		}
+		key, ok := enter(v, visiting)
+		if !ok {
+			return true
+		}
+		defer delete(visiting, key)
+
+		switch v.Kind() {
+		case reflect.Ptr:
+			return containsUnloggable(v.Elem(), depth+1, visiting)
+		case reflect.Map:
+			iter := v.MapRange()
+			for iter.Next() {
+				if containsUnloggable(iter.Key(), depth+1, visiting) || containsUnloggable(iter.Value(), depth+1, visiting) {
+					return true
+				}
+			}
+			return false
+		}
+		return containsElements(v, depth, visiting)
+	case reflect.Array:
+		return containsElements(v, depth, visiting)
+	case reflect.Interface:
+		return !v.IsNil() && containsUnloggable(v.Elem(), depth+1, visiting)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if containsUnloggable(v.Field(i), depth+1, visiting) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsElements(v reflect.Value, depth int, visiting map[visit]bool) bool {
+	for i := 0; i < v.Len(); i++ {
+		if containsUnloggable(v.Index(i), depth+1, visiting) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderValue writes v in the style of fmt's %v verb, substituting
+// placeholders for unloggable values
+func renderValue(b *strings.Builder, v reflect.Value, depth int, visiting map[visit]bool) {
+	if depth > maxValueDepth {
+		b.WriteString("<max depth>")
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Invalid:
+		b.WriteString("<nil>")
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		b.WriteString("<" + v.Type().String() + ">")
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			b.WriteString("<nil>")
+			return
+		}
+		key, ok := enter(v, visiting)
+		if !ok {
+			b.WriteString("<cycle>")
+			return
+		}
+		defer delete(visiting, key)
+
+		switch v.Kind() {
+		case reflect.Ptr:
+			b.WriteString("&")
+			renderValue(b, v.Elem(), depth+1, visiting)
+		case reflect.Map:
+			renderMap(b, v, depth, visiting)
+		default:
+			renderElements(b, v, depth, visiting)
+		}
+	case reflect.Array:
+		renderElements(b, v, depth, visiting)
+	case reflect.Interface:
+		if v.IsNil() {
+			b.WriteString("<nil>")
+			return
+		}
+		renderValue(b, v.Elem(), depth+1, visiting)
+	case reflect.Struct:
+		b.WriteString("{")
+		for i := 0; i < v.NumField(); i++ {
+			if i > 0 {
+				b.WriteString(" ")
+			}
+			renderValue(b, v.Field(i), depth+1, visiting)
+		}
+		b.WriteString("}")
+	default:
+		fmt.Fprint(b, v)
+	}
+}
+
+func renderElements(b *strings.Builder, v reflect.Value, depth int, visiting map[visit]bool) {
+	b.WriteString("[")
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		renderValue(b, v.Index(i), depth+1, visiting)
+	}
+	b.WriteString("]")
+}
+
+// renderMap writes map entries sorted by their rendered key, as fmt does
+// for ordered key types
+func renderMap(b *strings.Builder, v reflect.Value, depth int, visiting map[visit]bool) {
+	entries := make([]string, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		var entry strings.Builder
+		renderValue(&entry, iter.Key(), depth+1, visiting)
+		entry.WriteString(":")
+		renderValue(&entry, iter.Value(), depth+1, visiting)
+		entries = append(entries, entry.String())
+	}
+	sort.Strings(entries)
+
+	b.WriteString("map[" + strings.Join(entries, " ") + "]")
+}
+
+// checkUnloggable adds an _unloggable_fields count to entries holding
+// values rendered as placeholders, and reports them to OnStrictViolation
+func (l *standardLogger) checkUnloggable(fields []Field) []Field {
+	var keys []string
+	for _, field := range fields {
+		if isUnloggable(field.Value) {
+			keys = append(keys, field.Key)
+		}
+	}
+	if len(keys) == 0 {
+		return fields
+	}
+
+	if l.onViolate != nil {
+		l.onViolate(fmt.Errorf("unloggable values in fields %s", strings.Join(keys, ", ")))
+	}
+	return append(fields, Field{Key: "_unloggable_fields", Value: len(keys)})
+}
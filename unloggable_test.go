@@ -0,0 +1,91 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+type node struct {
+	Name string
+	Next *node
+}
+
+func TestUnloggableValues(t *testing.T) {
+	self := &node{Name: "loop"}
+	self.Next = self
+
+	selfMap := map[string]any{"id": 1}
+	selfMap["self"] = selfMap
+
+	selfSlice := []any{"first", nil}
+	selfSlice[1] = selfSlice
+
+	n := 42
+
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{name: "channel", value: make(chan int), expected: `v="<chan int>"`},
+		{name: "receive channel", value: make(<-chan string), expected: `v="<<-chan string>"`},
+		{name: "func", value: func(int) error { return nil }, expected: `v="<func(int) error>"`},
+		{name: "unsafe pointer", value: unsafe.Pointer(&n), expected: "v=<unsafe.Pointer>"},
+		{name: "self-referencing struct", value: self, expected: `v="&{loop <cycle>}"`},
+		{name: "map containing itself", value: selfMap, expected: `v="map[id:1 self:<cycle>]"`},
+		{name: "slice containing itself", value: selfSlice, expected: `v="[first <cycle>]"`},
+		{name: "nested func", value: struct {
+			ID      int
+			Handler func()
+		}{ID: 7}, expected: `v="{7 <func()>}"`},
+		{name: "ordinary struct", value: node{Name: "plain"}, expected: `v="{plain <nil>}"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := logger.New(logger.Config{Output: &buf})
+			log.Info("value", logger.Field{Key: "v", Value: tt.value})
+
+			if !strings.Contains(buf.String(), tt.expected) {
+				t.Errorf("Expected log output to contain %q, got: %s", tt.expected, buf.String())
+			}
+			if strings.Contains(buf.String(), "_unloggable_fields") {
+				t.Errorf("Expected no diagnostic outside strict mode, got: %s", buf.String())
+			}
+		})
+	}
+}
+
+func TestUnloggableStrict(t *testing.T) {
+	var violations []error
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{
+		Output:            &buf,
+		Strict:            true,
+		OnStrictViolation: func(err error) { violations = append(violations, err) },
+	})
+
+	log.Info("values",
+		logger.Field{Key: "ch", Value: make(chan int)},
+		logger.Field{Key: "ok", Value: "fine"},
+		logger.Group("g", logger.Field{Key: "fn", Value: func() {}}),
+	)
+
+	if !strings.Contains(buf.String(), "_unloggable_fields=2") {
+		t.Errorf("Expected _unloggable_fields=2, got: %s", buf.String())
+	}
+	if len(violations) != 1 || !strings.Contains(violations[0].Error(), "ch, g") {
+		t.Errorf("Expected one violation naming ch and g, got %v", violations)
+	}
+
+	buf.Reset()
+	log.Info("clean", logger.Field{Key: "ok", Value: []int{1, 2}})
+	if strings.Contains(buf.String(), "_unloggable_fields") {
+		t.Errorf("Expected no diagnostic for loggable values, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,187 @@
+package logger
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// rootName is the name volume is attributed to for loggers never named
+// with Named
+const rootName = "root"
+
+// NameVolume is the output produced under one logger name during one
+// time bucket
+type NameVolume struct {
+	Name    string    `json:"name"`
+	Start   time.Time `json:"start"`
+	Bytes   int64     `json:"bytes"`
+	Entries int64     `json:"entries"`
+}
+
+// volumeAccountant aggregates output volume per logger name in a ring of
+// time buckets
+type volumeAccountant struct {
+	resolution time.Duration
+	buckets    int
+	series     sync.Map // name -> *volumeSeries
+}
+
+type volumeSeries struct {
+	buckets []volumeBucket
+}
+
+type volumeBucket struct {
+	slot    int64 // bucket start in units of resolution since the epoch
+	bytes   int64
+	entries int64
+}
+
+// accountant holds the active *volumeAccountant, or nil when accounting is
+// disabled, so loggers pay a single atomic load
+var accountant atomic.Value
+
+func init() {
+	accountant.Store((*volumeAccountant)(nil))
+}
+
+// EnableVolumeAccounting starts counting the encoded bytes and entries
+// every logger writes, attributed to the name given with Named, or "root".
+// Counts are kept in buckets of the given resolution for the given
+// retention; older buckets are reused. Calling it again discards the
+// counts collected so far.
+//
+// When entries fan out through MultiLogger they are counted once, by the
+// first child, so volume reflects what the application logged rather than
+// the number of sinks.
+func EnableVolumeAccounting(resolution, retention time.Duration) {
+	if resolution <= 0 {
+		resolution = time.Hour
+	}
+	buckets := int(retention / resolution)
+	if buckets < 1 {
+		buckets = 1
+	}
+	accountant.Store(&volumeAccountant{resolution: resolution, buckets: buckets})
+}
+
+// DisableVolumeAccounting stops counting and discards the counts
+func DisableVolumeAccounting() {
+	accountant.Store((*volumeAccountant)(nil))
+}
+
+// VolumeReport returns the volume of every bucket that started within the
+// last window, sorted by name and then by bucket start. It returns nil
+// when accounting is disabled.
+func VolumeReport(window time.Duration) []NameVolume {
+	a := accountant.Load().(*volumeAccountant)
+	if a == nil {
+		return nil
+	}
+	return a.report(time.Now(), window)
+}
+
+// LogVolumeReport writes one Info entry per bucket of VolumeReport(window),
+// tagged volume_report=true. Call it periodically, for example daily, to
+// keep the attribution in the log stream itself.
+func LogVolumeReport(l LevelLogger, window time.Duration) {
+	for _, v := range VolumeReport(window) {
+		l.Info("log volume",
+			Field{Key: "volume_report", Value: true},
+			Field{Key: "name", Value: v.Name},
+			Field{Key: "start", Value: v.Start.UTC().Format(time.RFC3339)},
+			Field{Key: "bytes", Value: v.Bytes},
+			Field{Key: "entries", Value: v.Entries},
+		)
+	}
+}
+
+// recordVolume attributes n bytes written at now to name, if accounting
+// is enabled
+func recordVolume(name string, now time.Time, n int) {
+	a := accountant.Load().(*volumeAccountant)
+	if a == nil {
+		return
+	}
+	if name == "" {
+		name = rootName
+	}
+	a.record(name, now, int64(n))
+}
+
+func (a *volumeAccountant) record(name string, now time.Time, n int64) {
+	value, ok := a.series.Load(name)
+	if !ok {
+		value, _ = a.series.LoadOrStore(name, &volumeSeries{buckets: make([]volumeBucket, a.buckets)})
+	}
+	series := value.(*volumeSeries)
+
+	slot := now.UnixNano() / int64(a.resolution)
+	// Clocks before 1970, such as a zero time.Time, give negative slots
+	size := int64(len(series.buckets))
+	b := &series.buckets[(slot%size+size)%size]
+	if current := atomic.LoadInt64(&b.slot); current != slot {
+		// Reuse a bucket from an earlier period. Entries recorded by other
+		// goroutines while it is being reset may be lost.
+		if atomic.CompareAndSwapInt64(&b.slot, current, slot) {
+			atomic.StoreInt64(&b.bytes, 0)
+			atomic.StoreInt64(&b.entries, 0)
+		}
+	}
+	atomic.AddInt64(&b.bytes, n)
+	atomic.AddInt64(&b.entries, 1)
+}
+
+func (a *volumeAccountant) report(now time.Time, window time.Duration) []NameVolume {
+	nowSlot := now.UnixNano() / int64(a.resolution)
+	oldest := now.Add(-window).UnixNano() / int64(a.resolution)
+
+	var volumes []NameVolume
+	a.series.Range(func(key, value any) bool {
+		for i := range value.(*volumeSeries).buckets {
+			b := &value.(*volumeSeries).buckets[i]
+			slot := atomic.LoadInt64(&b.slot)
+			entries := atomic.LoadInt64(&b.entries)
+			if entries == 0 || slot < oldest || slot > nowSlot {
+				continue
+			}
+			volumes = append(volumes, NameVolume{
+				Name:    key.(string),
+				Start:   time.Unix(0, slot*int64(a.resolution)),
+				Bytes:   atomic.LoadInt64(&b.bytes),
+				Entries: entries,
+			})
+		}
+		return true
+	})
+
+	sort.Slice(volumes, func(i, j int) bool {
+		if volumes[i].Name != volumes[j].Name {
+			return volumes[i].Name < volumes[j].Name
+		}
+		return volumes[i].Start.Before(volumes[j].Start)
+	})
+	return volumes
+}
+
+// withoutAccounting returns l, or a copy of it that writes without
+// recording volume, so a fan-out is counted once
+func withoutAccounting(l Logger) Logger {
+	switch logger := l.(type) {
+	case *standardLogger:
+		logger.mu.Lock()
+		defer logger.mu.Unlock()
+		silent := logger.clone()
+		silent.noVolume = true
+		return silent
+	case *multiLogger:
+		children := make([]Logger, len(logger.loggers))
+		for i, child := range logger.loggers {
+			children[i] = withoutAccounting(child)
+		}
+		return &multiLogger{loggers: children}
+	default:
+		return l
+	}
+}
@@ -0,0 +1,125 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichaelAJay/go-logger"
+)
+
+func enableVolumeAccounting(t *testing.T) {
+	t.Helper()
+	logger.EnableVolumeAccounting(time.Hour, 24*time.Hour)
+	t.Cleanup(logger.DisableVolumeAccounting)
+}
+
+// volumeByName sums the report per logger name
+func volumeByName() map[string]logger.NameVolume {
+	totals := map[string]logger.NameVolume{}
+	for _, v := range logger.VolumeReport(time.Hour) {
+		total := totals[v.Name]
+		total.Name = v.Name
+		total.Bytes += v.Bytes
+		total.Entries += v.Entries
+		totals[v.Name] = total
+	}
+	return totals
+}
+
+func TestNamedLoggers(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+
+	db := logger.Named(logger.Named(log, "api"), "db")
+	db.Info("query", logger.Field{Key: "rows", Value: 3})
+
+	if !strings.Contains(buf.String(), "{logger=api.db rows=3}") {
+		t.Errorf("Expected nested name as a single logger field, got: %s", buf.String())
+	}
+	if name := logger.Describe(db).Name; name != "api.db" {
+		t.Errorf("Expected description name api.db, got %q", name)
+	}
+}
+
+func TestVolumeAccounting(t *testing.T) {
+	enableVolumeAccounting(t)
+
+	var buf bytes.Buffer
+	root := logger.New(logger.Config{Output: &buf})
+	db := logger.Named(root, "db")
+
+	root.Info("root entry")
+	db.Info("first")
+	db.With(logger.Field{Key: "k", Value: "v"}).Warn("second")
+
+	totals := volumeByName()
+	if totals["root"].Entries != 1 {
+		t.Errorf("Expected 1 root entry, got %+v", totals["root"])
+	}
+	if totals["db"].Entries != 2 {
+		t.Errorf("Expected 2 db entries, got %+v", totals["db"])
+	}
+	// The date prefix added by the standard library is not part of the
+	// encoded entry
+	stdPrefix := len("2006/01/02 15:04:05 ")
+	if got, want := totals["root"].Bytes+totals["db"].Bytes, int64(buf.Len()-3*stdPrefix); got != want {
+		t.Errorf("Expected %d bytes accounted, got %d", want, got)
+	}
+}
+
+func TestVolumeAccountingMultiLoggerCountsOnce(t *testing.T) {
+	enableVolumeAccounting(t)
+
+	var buf1, buf2 bytes.Buffer
+	multi := logger.Named(logger.MultiLogger(
+		logger.New(logger.Config{Output: &buf1}),
+		logger.New(logger.Config{Output: &buf2}),
+	), "worker")
+
+	multi.Info("fan out")
+	multi.With(logger.Field{Key: "job", Value: 1}).Error("fan out again")
+
+	if entries := volumeByName()["worker"].Entries; entries != 2 {
+		t.Errorf("Expected 2 entries for a two-sink fan-out, got %d", entries)
+	}
+	if !strings.Contains(buf2.String(), "fan out again") {
+		t.Errorf("Expected the second sink to keep writing, got: %s", buf2.String())
+	}
+}
+
+func TestVolumeAccountingDisabled(t *testing.T) {
+	logger.DisableVolumeAccounting()
+	logger.New(logger.Config{Output: &bytes.Buffer{}}).Info("not counted")
+
+	if report := logger.VolumeReport(time.Hour); report != nil {
+		t.Errorf("Expected no report while disabled, got %+v", report)
+	}
+}
+
+func TestLogVolumeReport(t *testing.T) {
+	enableVolumeAccounting(t)
+	logger.Named(logger.New(logger.Config{Output: &bytes.Buffer{}}), "billing").Info("entry")
+
+	var buf bytes.Buffer
+	logger.LogVolumeReport(logger.New(logger.Config{Output: &buf}), time.Hour)
+
+	if !strings.Contains(buf.String(), "volume_report=true name=billing") || !strings.Contains(buf.String(), "entries=1") {
+		t.Errorf("Expected a report entry for billing, got: %s", buf.String())
+	}
+}
+
+func TestVolumeAccountingClockBefore1970(t *testing.T) {
+	enableVolumeAccounting(t)
+
+	for _, now := range []time.Time{{}, time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC)} {
+		var buf bytes.Buffer
+		log := logger.New(logger.Config{Output: &buf, Clock: fixedClock(now)})
+		logger.Named(log, "svc").Info("entry")
+
+		if !strings.Contains(buf.String(), "entry") {
+			t.Errorf("Expected the entry at %v to be written, got: %s", now, buf.String())
+		}
+	}
+}